@@ -1,11 +1,19 @@
 package main
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"event_service/internal/config"
 	"event_service/internal/models"
 	"event_service/internal/repositories"
 	"event_service/internal/routes/rest"
 	"event_service/internal/service"
+	"event_service/pkg/blob"
+	pgdb "event_service/pkg/db"
+	"event_service/pkg/http/middleware"
+	"event_service/pkg/teams"
+	"event_service/pkg/tracing"
 	"event_service/pkg/utils"
 	"fmt"
 	"github.com/go-chi/chi/v5"
@@ -16,6 +24,8 @@ import (
 	"log/slog"
 	"net/http"
 	"os"
+	"time"
+	_ "time/tzdata"
 )
 
 const (
@@ -33,29 +43,73 @@ func main() {
 	logger.Info("Starting event-service", slog.String("env", cfg.Env))
 	logger.Debug("debug messages are enabled")
 
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		ServiceName: cfg.Tracing.ServiceName,
+		Exporter:    cfg.Tracing.Exporter,
+		Endpoint:    cfg.Tracing.Endpoint,
+		Insecure:    cfg.Tracing.Insecure,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		logger.Error("Failed to set up tracing", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			logger.Error("Failed to flush traces", slog.String("error", err.Error()))
+		}
+	}()
+
 	db := pg.Connect(&pg.Options{
 		Addr:     cfg.SQLDatabase.Addr,
 		User:     cfg.SQLDatabase.User,
 		Password: cfg.SQLDatabase.Password,
 		Database: cfg.SQLDatabase.Database,
 	})
+	db.AddQueryHook(pgdb.MetricsHook{})
+	db.AddQueryHook(pgdb.TracingHook{Database: cfg.SQLDatabase.Database})
 
 	InitM2M()
 	InitPrometheus()
 
-	router := chi.NewRouter()
+	auditService := service.NewAuditService(repositories.NewAuditRepository(db), db)
+	scheduleValidator := service.NewScheduleValidator(repositories.NewDateRepository(db), repositories.NewEventRepository(db),
+		repositories.NewTrackRepository(db), repositories.NewTimelineRepository(db), repositories.NewSessionRepository(db))
+
+	teamsClient := newTeamsClient(cfg)
+	statisticsCache := service.NewStatisticsCache(cfg.Statistics.CacheTTL)
+
+	blobStore, err := newBlobStore(cfg.Submissions)
+	if err != nil {
+		logger.Error("Failed to set up submission storage", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
 
-	router.Mount("/event", createEventHandler(db, logger))
-	router.Mount("/dates", createDateHandler(db, logger))
-	router.Mount("/status", createStatusHandler(db, logger))
-	router.Mount("/location", createLocationHandler(db, logger))
-	router.Mount("/track", createTrackHandler(db, logger))
-	router.Mount("/timeline", createTimelineHandler(db, logger))
-	router.Mount("/team-action-status", createTeamActionStatusHandler(db, logger))
-	router.Mount("/track-winner", createTrackWinnerHandler(db, logger))
+	router := chi.NewRouter()
+	router.Use(middleware.TracingMiddleware)
+	router.Use(middleware.MetricsMiddleware)
+	router.Use(middleware.ActorMiddleware(logger, cfg.AuthUrl, cfg.Admins))
+
+	router.Mount("/event", createEventHandler(db, logger, auditService, scheduleValidator))
+	router.Mount("/dates", createDateHandler(db, logger, auditService, scheduleValidator))
+	router.Mount("/status", createStatusHandler(db, logger, auditService))
+	router.Mount("/location", createLocationHandler(db, logger, auditService))
+	router.Mount("/track", createTrackHandler(db, logger, auditService, scheduleValidator, teamsClient, statisticsCache))
+	router.Mount("/timeline", createTimelineHandler(db, logger, auditService, scheduleValidator))
+	router.Mount("/team-action-status", createTeamActionStatusHandler(db, logger, auditService, teamsClient, blobStore, cfg.Submissions, statisticsCache))
+	router.Mount("/track-winner", createTrackWinnerHandler(db, logger, auditService))
+	router.Mount("/session", createSessionHandler(db, logger, auditService, scheduleValidator))
+	router.Mount("/speaker", createSpeakerHandler(db, logger, auditService, teamsClient))
+	router.Mount("/calendar", createCalendarHandler(db, logger))
+	router.Mount("/search", rest.NewSearch(logger, service.NewSearchService(repositories.NewSearchRepository(db), db)))
+	router.Mount("/audit", rest.NewAudit(logger, auditService))
 
 	router.Handle("/metrics", promhttp.Handler())
 
+	startRetention(db, logger, blobStore, cfg.Retention.Period)
+	go utils.ScheduleMetrics(logger, service.NewMetricsService(repositories.NewMetricsRepository(db), db))
+
 	logger.Info("starting server", slog.String("address", cfg.Address))
 
 	server := &http.Server{
@@ -80,6 +134,9 @@ func InitM2M() {
 	orm.RegisterTable((*models.LocationTrack)(nil))
 	orm.RegisterTable((*models.TrackJudge)(nil))
 	orm.RegisterTable((*models.TrackWinner)(nil))
+	orm.RegisterTable((*models.SessionSpeaker)(nil))
+	orm.RegisterTable((*models.StatusEvent)(nil))
+	orm.RegisterTable((*models.StatusTrack)(nil))
 }
 
 func InitPrometheus() {
@@ -89,6 +146,23 @@ func InitPrometheus() {
 	prometheus.MustRegister(utils.EventStartFailure)
 	prometheus.MustRegister(utils.EventEndSuccess)
 	prometheus.MustRegister(utils.EventEndFailure)
+	prometheus.MustRegister(utils.TrackStartSuccess)
+	prometheus.MustRegister(utils.TrackStartFailure)
+	prometheus.MustRegister(utils.TrackEndSuccess)
+	prometheus.MustRegister(utils.TrackEndFailure)
+	prometheus.MustRegister(utils.TimelineExpireSuccess)
+	prometheus.MustRegister(utils.TimelineExpireFailure)
+
+	prometheus.MustRegister(utils.EventsByStatus)
+	prometheus.MustRegister(utils.TracksByStatus)
+	prometheus.MustRegister(utils.ActiveTracks)
+	prometheus.MustRegister(utils.Registrations)
+	prometheus.MustRegister(utils.Submissions)
+
+	prometheus.MustRegister(middleware.HTTPRequests)
+	prometheus.MustRegister(middleware.HTTPRequestDuration)
+	prometheus.MustRegister(pgdb.QueryDuration)
+	prometheus.MustRegister(pgdb.QueryErrors)
 }
 
 func setupLogger(env string) *slog.Logger {
@@ -97,82 +171,219 @@ func setupLogger(env string) *slog.Logger {
 	switch env {
 	case envLocal:
 		log = slog.New(
-			slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}),
+			tracing.NewLogHandler(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug})),
 		)
 	case envDev:
 		log = slog.New(
-			slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}),
+			tracing.NewLogHandler(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug})),
 		)
 	case envProd:
 		log = slog.New(
-			slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}),
+			tracing.NewLogHandler(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo})),
 		)
 	}
 
 	return log
 }
 
-func createDateHandler(db *pg.DB, logger *slog.Logger) *chi.Mux {
+func newTeamsClient(cfg *config.Config) *teams.HTTPClient {
+	baseURL := cfg.Teams.URL
+	if baseURL == "" {
+		baseURL = teams.BaseURL(cfg.AuthUrl)
+	}
+
+	return teams.NewHTTPClient(teams.Config{
+		BaseURL:          baseURL,
+		Timeout:          cfg.Teams.Timeout,
+		Retries:          cfg.Teams.Retries,
+		RetryBackoff:     cfg.Teams.RetryBackoff,
+		FailureThreshold: cfg.Teams.FailureThreshold,
+		OpenTimeout:      cfg.Teams.OpenTimeout,
+		Token:            utils.AuthTokenFromContext,
+	})
+}
+
+func createDateHandler(db *pg.DB, logger *slog.Logger, auditService *service.AuditService,
+	scheduleValidator *service.ScheduleValidator) *chi.Mux {
 	dateRepository := repositories.NewDateRepository(db)
-	dateService := service.NewDateService(dateRepository, db)
+	sessionRepository := repositories.NewSessionRepository(db)
+	bookingRepository := repositories.NewLocationBookingRepository(db)
+	dateService := service.NewDateService(dateRepository, sessionRepository, bookingRepository, auditService, scheduleValidator, db)
 
 	return rest.NewDate(logger, dateService)
 }
 
-func createEventHandler(db *pg.DB, logger *slog.Logger) *chi.Mux {
+func createEventHandler(db *pg.DB, logger *slog.Logger, auditService *service.AuditService,
+	scheduleValidator *service.ScheduleValidator) *chi.Mux {
 	eventRepository := repositories.NewEventRepository(db)
 	eventLocationRepository := repositories.NewEventLocationRepository(db)
 	trackRepository := repositories.NewTrackRepository(db)
+	timelineRepository := repositories.NewTimelineRepository(db)
+	overrideRepository := repositories.NewTimelineOverrideRepository(db)
+	dateRepository := repositories.NewDateRepository(db)
+	sessionRepository := repositories.NewSessionRepository(db)
+	bookingRepository := repositories.NewLocationBookingRepository(db)
+	statusEventRepository := repositories.NewStatusEventRepository(db)
 
-	eventService := service.NewEventsService(eventRepository, trackRepository, eventLocationRepository, db)
+	eventService := service.NewEventsService(eventRepository, trackRepository, timelineRepository, overrideRepository,
+		eventLocationRepository, dateRepository, sessionRepository, bookingRepository, statusEventRepository, auditService, scheduleValidator, db)
 	go utils.ScheduleEvents(logger, eventService)
 
 	return rest.NewEvent(logger, eventService)
 }
 
-func createStatusHandler(db *pg.DB, logger *slog.Logger) *chi.Mux {
+func createStatusHandler(db *pg.DB, logger *slog.Logger, auditService *service.AuditService) *chi.Mux {
 	statusRepository := repositories.NewStatusRepository(db)
-	statusService := service.NewStatusService(statusRepository, db)
+	statusService := service.NewStatusService(statusRepository, auditService, db)
 
 	return rest.NewStatus(logger, statusService)
 }
 
-func createLocationHandler(db *pg.DB, logger *slog.Logger) *chi.Mux {
+func createLocationHandler(db *pg.DB, logger *slog.Logger, auditService *service.AuditService) *chi.Mux {
 	locationRepository := repositories.NewLocationRepository(db)
-	locationService := service.NewLocationService(locationRepository, db)
+	sessionRepository := repositories.NewSessionRepository(db)
+	bookingRepository := repositories.NewLocationBookingRepository(db)
+	eventLocationRepository := repositories.NewEventLocationRepository(db)
+	locationService := service.NewLocationService(locationRepository, sessionRepository, bookingRepository,
+		eventLocationRepository, auditService, db)
 
 	return rest.NewLocation(logger, locationService)
 }
 
-func createTrackHandler(db *pg.DB, logger *slog.Logger) *chi.Mux {
+func createTrackHandler(db *pg.DB, logger *slog.Logger, auditService *service.AuditService,
+	scheduleValidator *service.ScheduleValidator, teamsClient teams.Client, statisticsCache *service.StatisticsCache) *chi.Mux {
 	trackRepository := repositories.NewTrackRepository(db)
-
-	trackService := service.NewTrackService(trackRepository, db)
+	eventRepository := repositories.NewEventRepository(db)
+	timelineRepository := repositories.NewTimelineRepository(db)
+	locationTrackRepository := repositories.NewLocationTrackRepository(db)
+	trackTeamRepository := repositories.NewTrackTeamRepository(db)
+	bookingRepository := repositories.NewLocationBookingRepository(db)
+	statusTrackRepository := repositories.NewStatusTrackRepository(db)
+	eligibilityRepository := repositories.NewTrackEligibilityRepository(db)
+	trackRoleRepository := repositories.NewTrackRoleRepository(db)
+
+	trackService := service.NewTrackService(trackRepository, eventRepository, timelineRepository,
+		locationTrackRepository, trackTeamRepository, bookingRepository, statusTrackRepository, eligibilityRepository,
+		trackRoleRepository, auditService, scheduleValidator, teamsClient, statisticsCache, db)
 	go utils.ScheduleTracks(logger, trackService)
 
 	return rest.NewTrack(logger, trackService)
 }
 
-func createTimelineHandler(db *pg.DB, logger *slog.Logger) *chi.Mux {
+func createTimelineHandler(db *pg.DB, logger *slog.Logger, auditService *service.AuditService,
+	scheduleValidator *service.ScheduleValidator) *chi.Mux {
 	timelineRepository := repositories.NewTimelineRepository(db)
 	timelineStatusRepository := repositories.NewTimelineStatusRepository(db)
+	trackRepository := repositories.NewTrackRepository(db)
+	trackTeamRepository := repositories.NewTrackTeamRepository(db)
+	overrideRepository := repositories.NewTimelineOverrideRepository(db)
+
+	timelineService := service.NewTimelineService(timelineRepository, timelineStatusRepository, trackRepository,
+		trackTeamRepository, overrideRepository, auditService, scheduleValidator, db)
+	go utils.ScheduleTimelines(logger, timelineService)
 
-	timelineService := service.NewTimelineService(timelineRepository, timelineStatusRepository, db)
 	return rest.NewTimeline(logger, timelineService)
 }
 
-func createTeamActionStatusHandler(db *pg.DB, logger *slog.Logger) *chi.Mux {
+func createTeamActionStatusHandler(db *pg.DB, logger *slog.Logger, auditService *service.AuditService, teamsClient teams.Client,
+	blobStore blob.Store, cfg config.Submissions, statisticsCache *service.StatisticsCache) *chi.Mux {
 	teamActionStatusRepository := repositories.NewTeamActionStatusRepository(db)
-	teamActionStatusService := service.NewTeamActionStatusService(teamActionStatusRepository, db)
+	teamActionStatusRevisionRepository := repositories.NewTeamActionStatusRevisionRepository(db)
+	timelineRepository := repositories.NewTimelineRepository(db)
+	overrideRepository := repositories.NewTimelineOverrideRepository(db)
+	submissionFileRepository := repositories.NewSubmissionFileRepository(db)
+	trackTeamRepository := repositories.NewTrackTeamRepository(db)
+	trackRoleRepository := repositories.NewTrackRoleRepository(db)
+
+	linkSecret := cfg.LinkSecret
+	if linkSecret == "" {
+		logger.Warn("submission link secret is not set, download links will not survive a restart")
+
+		secret := make([]byte, 32)
+		_, _ = rand.Read(secret)
+		linkSecret = hex.EncodeToString(secret)
+	}
+
+	policy := service.SubmissionPolicy{
+		MaxSize:      cfg.MaxSize,
+		AllowedTypes: cfg.AllowedTypes,
+		LinkTTL:      cfg.LinkTTL,
+		DownloadURL:  "/team-action-status/files/%d/download",
+	}
+
+	teamActionStatusService := service.NewTeamActionStatusService(teamActionStatusRepository, teamActionStatusRevisionRepository, timelineRepository,
+		overrideRepository, submissionFileRepository, trackTeamRepository, trackRoleRepository, auditService, teamsClient, blobStore,
+		blob.NewSigner(linkSecret), policy, statisticsCache, db)
 
 	return rest.NewTeamActionStatus(logger, teamActionStatusService)
 }
 
-func createTrackWinnerHandler(db *pg.DB, logger *slog.Logger) *chi.Mux {
+func newBlobStore(cfg config.Submissions) (blob.Store, error) {
+	if cfg.Storage == "s3" {
+		return blob.NewS3Store(blob.S3Config{
+			Endpoint:  cfg.S3.Endpoint,
+			Region:    cfg.S3.Region,
+			Bucket:    cfg.S3.Bucket,
+			AccessKey: cfg.S3.AccessKey,
+			SecretKey: cfg.S3.SecretKey,
+		}), nil
+	}
+
+	return blob.NewLocalStore(cfg.LocalDir)
+}
+
+func createTrackWinnerHandler(db *pg.DB, logger *slog.Logger, auditService *service.AuditService) *chi.Mux {
 	trackWinnerRepository := repositories.NewTrackWinnerRepository(db)
+	teamActionStatusRepository := repositories.NewTeamActionStatusRepository(db)
 	trackRepository := repositories.NewTrackRepository(db)
 	timelineRepository := repositories.NewTimelineRepository(db)
 
-	trackWinnerService := service.NewTrackWinnerService(trackWinnerRepository, trackRepository, timelineRepository, db)
+	trackWinnerService := service.NewTrackWinnerService(trackWinnerRepository, teamActionStatusRepository, trackRepository,
+		timelineRepository, auditService, db)
 	return rest.NewTrackWinner(logger, trackWinnerService)
 }
+
+func createSessionHandler(db *pg.DB, logger *slog.Logger, auditService *service.AuditService,
+	scheduleValidator *service.ScheduleValidator) *chi.Mux {
+	sessionRepository := repositories.NewSessionRepository(db)
+	sessionSpeakerRepository := repositories.NewSessionSpeakerRepository(db)
+	locationRepository := repositories.NewLocationRepository(db)
+	bookingRepository := repositories.NewLocationBookingRepository(db)
+
+	sessionService := service.NewSessionService(sessionRepository, sessionSpeakerRepository, locationRepository,
+		bookingRepository, auditService, scheduleValidator, db)
+	return rest.NewSession(logger, sessionService)
+}
+
+func createSpeakerHandler(db *pg.DB, logger *slog.Logger, auditService *service.AuditService,
+	teamsClient teams.Client) *chi.Mux {
+	speakerRepository := repositories.NewSpeakerRepository(db)
+	speakerService := service.NewSpeakerService(speakerRepository, auditService, teamsClient, db)
+
+	return rest.NewSpeaker(logger, speakerService)
+}
+
+func createCalendarHandler(db *pg.DB, logger *slog.Logger) *chi.Mux {
+	eventRepository := repositories.NewEventRepository(db)
+	trackRepository := repositories.NewTrackRepository(db)
+	timelineRepository := repositories.NewTimelineRepository(db)
+	trackTeamRepository := repositories.NewTrackTeamRepository(db)
+	sessionRepository := repositories.NewSessionRepository(db)
+	overrideRepository := repositories.NewTimelineOverrideRepository(db)
+
+	calendarService := service.NewCalendarService(eventRepository, trackRepository, timelineRepository, trackTeamRepository,
+		sessionRepository, overrideRepository, db)
+	return rest.NewCalendar(logger, calendarService)
+}
+
+func startRetention(db *pg.DB, logger *slog.Logger, blobStore blob.Store, period time.Duration) {
+	eventRepository := repositories.NewEventRepository(db)
+	trackRepository := repositories.NewTrackRepository(db)
+	timelineRepository := repositories.NewTimelineRepository(db)
+	locationRepository := repositories.NewLocationRepository(db)
+	submissionFileRepository := repositories.NewSubmissionFileRepository(db)
+
+	retentionService := service.NewRetentionService(eventRepository, trackRepository, timelineRepository,
+		locationRepository, submissionFileRepository, blobStore, period, db)
+	go utils.ScheduleRetention(logger, retentionService)
+}
@@ -11,25 +11,125 @@ import (
 	"github.com/oapi-codegen/runtime"
 )
 
+// Defines values for LocationType.
+const (
+	Hybrid   LocationType = "hybrid"
+	Online   LocationType = "online"
+	Physical LocationType = "physical"
+)
+
 // Location defines model for Location.
 type Location struct {
-	Title string `json:"title"`
+	// Address Почтовый адрес
+	Address *string `json:"address,omitempty"`
+
+	// Capacity Вместимость в людях, 0 без ограничения
+	Capacity *int `json:"capacity,omitempty"`
+
+	// Latitude Широта в градусах
+	Latitude *float64 `json:"latitude,omitempty"`
+
+	// Longitude Долгота в градусах
+	Longitude *float64 `json:"longitude,omitempty"`
+
+	// ParentId Родительская локация, например площадка для этажа
+	ParentId *int `json:"parent_id,omitempty"`
+
+	// StreamUrl Ссылка на трансляцию для online и hybrid локаций
+	StreamUrl *string `json:"stream_url,omitempty"`
+
+	// TeamCapacity Сколько команд может работать в локации, 0 без ограничения
+	TeamCapacity *int `json:"team_capacity,omitempty"`
+
+	// TimeZone IANA time zone of the venue
+	TimeZone *string       `json:"time_zone,omitempty"`
+	Title    string        `json:"title"`
+	Type     *LocationType `json:"type,omitempty"`
 }
 
 // LocationResponse defines model for LocationResponse.
 type LocationResponse struct {
-	Id    *int   `json:"id,omitempty"`
-	Title string `json:"title"`
+	// Address Почтовый адрес
+	Address *string `json:"address,omitempty"`
+
+	// Capacity Вместимость в людях, 0 без ограничения
+	Capacity *int `json:"capacity,omitempty"`
+
+	// DistanceKm Расстояние до точки поиска near
+	DistanceKm *float64 `json:"distance_km,omitempty"`
+
+	// EventIds События, проходящие в локации, заполняется для поиска near
+	EventIds *[]int `json:"event_ids,omitempty"`
+	Id       *int   `json:"id,omitempty"`
+
+	// Latitude Широта в градусах
+	Latitude *float64 `json:"latitude,omitempty"`
+
+	// Longitude Долгота в градусах
+	Longitude *float64 `json:"longitude,omitempty"`
+
+	// ParentId Родительская локация, например площадка для этажа
+	ParentId *int `json:"parent_id,omitempty"`
+
+	// StreamUrl Ссылка на трансляцию для online и hybrid локаций
+	StreamUrl *string `json:"stream_url,omitempty"`
+
+	// TeamCapacity Сколько команд может работать в локации, 0 без ограничения
+	TeamCapacity *int `json:"team_capacity,omitempty"`
+
+	// TimeZone IANA time zone of the venue
+	TimeZone *string       `json:"time_zone,omitempty"`
+	Title    string        `json:"title"`
+	Type     *LocationType `json:"type,omitempty"`
 }
 
+// LocationType defines model for LocationType.
+type LocationType string
+
 // LocationUpdate defines model for LocationUpdate.
 type LocationUpdate struct {
-	Title *string `json:"title,omitempty"`
+	// Address Почтовый адрес
+	Address *string `json:"address,omitempty"`
+
+	// Capacity Вместимость в людях, 0 без ограничения
+	Capacity *int `json:"capacity,omitempty"`
+
+	// Latitude Широта в градусах
+	Latitude *float64 `json:"latitude,omitempty"`
+
+	// Longitude Долгота в градусах
+	Longitude *float64 `json:"longitude,omitempty"`
+
+	// ParentId Родительская локация, например площадка для этажа
+	ParentId *int `json:"parent_id,omitempty"`
+
+	// StreamUrl Ссылка на трансляцию для online и hybrid локаций
+	StreamUrl *string `json:"stream_url,omitempty"`
+
+	// TeamCapacity Сколько команд может работать в локации, 0 без ограничения
+	TeamCapacity *int `json:"team_capacity,omitempty"`
+
+	// TimeZone IANA time zone of the venue
+	TimeZone *string       `json:"time_zone,omitempty"`
+	Title    *string       `json:"title,omitempty"`
+	Type     *LocationType `json:"type,omitempty"`
 }
 
 // Id defines model for Id.
 type Id = int
 
+// GetLocationParams defines parameters for GetLocation.
+type GetLocationParams struct {
+	// Near Точка поиска в формате lat,lon
+	Near *string `form:"near,omitempty" json:"near,omitempty"`
+
+	// Radius Радиус поиска в километрах, по умолчанию 10
+	Radius *float64 `form:"radius,omitempty" json:"radius,omitempty"`
+
+	// ParentId Только дочерние локации
+	ParentId *int `form:"parent_id,omitempty" json:"parent_id,omitempty"`
+}
+
 // PostLocationJSONRequestBody defines body for PostLocation for application/json ContentType.
 type PostLocationJSONRequestBody = Location
 
@@ -40,7 +140,7 @@ type PutLocationIdJSONRequestBody = LocationUpdate
 type ServerInterface interface {
 	// Получить все локации
 	// (GET /location)
-	GetLocation(ctx echo.Context) error
+	GetLocation(ctx echo.Context, params GetLocationParams) error
 	// Создать локацию
 	// (POST /location)
 	PostLocation(ctx echo.Context) error
@@ -64,8 +164,31 @@ type ServerInterfaceWrapper struct {
 func (w *ServerInterfaceWrapper) GetLocation(ctx echo.Context) error {
 	var err error
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetLocationParams
+	// ------------- Optional query parameter "near" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "near", ctx.QueryParams(), &params.Near)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter near: %s", err))
+	}
+
+	// ------------- Optional query parameter "radius" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "radius", ctx.QueryParams(), &params.Radius)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter radius: %s", err))
+	}
+
+	// ------------- Optional query parameter "parent_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "parent_id", ctx.QueryParams(), &params.ParentId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter parent_id: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.GetLocation(ctx)
+	err = w.Handler.GetLocation(ctx, params)
 	return err
 }
 
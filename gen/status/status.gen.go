@@ -13,18 +13,24 @@ import (
 
 // Status defines model for Status.
 type Status struct {
-	Title string `json:"title"`
+	// Description Пояснение, что означает метка
+	Description *string `json:"description,omitempty"`
+	Title       string  `json:"title"`
 }
 
 // StatusResponse defines model for StatusResponse.
 type StatusResponse struct {
-	Id    *int   `json:"id,omitempty"`
-	Title string `json:"title"`
+	// Description Пояснение, что означает метка
+	Description *string `json:"description,omitempty"`
+	Id          *int    `json:"id,omitempty"`
+	Title       string  `json:"title"`
 }
 
 // StatusUpdate defines model for StatusUpdate.
 type StatusUpdate struct {
-	Title *string `json:"title,omitempty"`
+	// Description Пояснение, что означает метка
+	Description *string `json:"description,omitempty"`
+	Title       *string `json:"title,omitempty"`
 }
 
 // Id defines model for Id.
@@ -12,29 +12,126 @@ import (
 	"github.com/oapi-codegen/runtime"
 )
 
+// Defines values for TimelineCountedRevision.
+const (
+	TimelineCountedRevisionBest  TimelineCountedRevision = "best"
+	TimelineCountedRevisionFirst TimelineCountedRevision = "first"
+	TimelineCountedRevisionLast  TimelineCountedRevision = "last"
+)
+
+// Defines values for TimelineLatePolicy.
+const (
+	TimelineLatePolicyFlag     TimelineLatePolicy = "flag"
+	TimelineLatePolicyGrace    TimelineLatePolicy = "grace"
+	TimelineLatePolicyLinear   TimelineLatePolicy = "linear"
+	TimelineLatePolicyReject   TimelineLatePolicy = "reject"
+	TimelineLatePolicyStepwise TimelineLatePolicy = "stepwise"
+)
+
+// Defines values for TimelineResponseCountedRevision.
+const (
+	TimelineResponseCountedRevisionBest  TimelineResponseCountedRevision = "best"
+	TimelineResponseCountedRevisionFirst TimelineResponseCountedRevision = "first"
+	TimelineResponseCountedRevisionLast  TimelineResponseCountedRevision = "last"
+)
+
+// Defines values for TimelineResponseLatePolicy.
+const (
+	TimelineResponseLatePolicyFlag     TimelineResponseLatePolicy = "flag"
+	TimelineResponseLatePolicyGrace    TimelineResponseLatePolicy = "grace"
+	TimelineResponseLatePolicyLinear   TimelineResponseLatePolicy = "linear"
+	TimelineResponseLatePolicyReject   TimelineResponseLatePolicy = "reject"
+	TimelineResponseLatePolicyStepwise TimelineResponseLatePolicy = "stepwise"
+)
+
+// Defines values for TimelineUpdateCountedRevision.
+const (
+	TimelineUpdateCountedRevisionBest  TimelineUpdateCountedRevision = "best"
+	TimelineUpdateCountedRevisionFirst TimelineUpdateCountedRevision = "first"
+	TimelineUpdateCountedRevisionLast  TimelineUpdateCountedRevision = "last"
+)
+
+// Defines values for TimelineUpdateLatePolicy.
+const (
+	TimelineUpdateLatePolicyFlag     TimelineUpdateLatePolicy = "flag"
+	TimelineUpdateLatePolicyGrace    TimelineUpdateLatePolicy = "grace"
+	TimelineUpdateLatePolicyLinear   TimelineUpdateLatePolicy = "linear"
+	TimelineUpdateLatePolicyReject   TimelineUpdateLatePolicy = "reject"
+	TimelineUpdateLatePolicyStepwise TimelineUpdateLatePolicy = "stepwise"
+)
+
 // Timeline defines model for Timeline.
 type Timeline struct {
-	Deadline         time.Time `json:"deadline"`
-	Description      string    `json:"description"`
-	IsBlocking       bool      `json:"is_blocking"`
-	Status           string    `json:"status"`
-	TimelineStatusId int       `json:"timeline_status_id"`
-	Title            string    `json:"title"`
-	TrackId          int       `json:"track_id"`
+	// CountedRevision Which revision of a team's result counts toward the ranking
+	CountedRevision *TimelineCountedRevision `json:"counted_revision,omitempty"`
+	Deadline        time.Time                `json:"deadline"`
+	Description     string                   `json:"description"`
+	IsBlocking      bool                     `json:"is_blocking"`
+
+	// LateGraceMinutes Minutes after the deadline before a submission counts as late
+	LateGraceMinutes *int `json:"late_grace_minutes,omitempty"`
+
+	// LatePenalty Penalty in percent per hour for linear or per step for stepwise policies
+	LatePenalty *int `json:"late_penalty,omitempty"`
+
+	// LatePolicy How submissions after the deadline are treated
+	LatePolicy *TimelineLatePolicy `json:"late_policy,omitempty"`
+
+	// LateStepMinutes Length of a penalty step for the stepwise policy
+	LateStepMinutes  *int   `json:"late_step_minutes,omitempty"`
+	Status           string `json:"status"`
+	TimelineStatusId int    `json:"timeline_status_id"`
+	Title            string `json:"title"`
+	TrackId          int    `json:"track_id"`
 }
 
+// TimelineCountedRevision Which revision of a team's result counts toward the ranking
+type TimelineCountedRevision string
+
+// TimelineLatePolicy How submissions after the deadline are treated
+type TimelineLatePolicy string
+
 // TimelineResponse defines model for TimelineResponse.
 type TimelineResponse struct {
-	Deadline         time.Time `json:"deadline"`
-	Description      string    `json:"description"`
-	Id               *int      `json:"id,omitempty"`
-	IsBlocking       bool      `json:"is_blocking"`
-	Status           string    `json:"status"`
-	TimelineStatusId int       `json:"timeline_status_id"`
-	Title            string    `json:"title"`
-	TrackId          int       `json:"track_id"`
+	// CountedRevision Which revision of a team's result counts toward the ranking
+	CountedRevision *TimelineResponseCountedRevision `json:"counted_revision,omitempty"`
+	Deadline        time.Time                        `json:"deadline"`
+
+	// DeadlineLocal Deadline in the time zone of the event
+	DeadlineLocal *time.Time `json:"deadline_local,omitempty"`
+	Description   string     `json:"description"`
+	Id            *int       `json:"id,omitempty"`
+	IsBlocking    bool       `json:"is_blocking"`
+
+	// LateGraceMinutes Minutes after the deadline before a submission counts as late
+	LateGraceMinutes *int `json:"late_grace_minutes,omitempty"`
+
+	// LatePenalty Penalty in percent per hour for linear or per step for stepwise policies
+	LatePenalty *int `json:"late_penalty,omitempty"`
+
+	// LatePolicy How submissions after the deadline are treated
+	LatePolicy *TimelineResponseLatePolicy `json:"late_policy,omitempty"`
+
+	// LateStepMinutes Length of a penalty step for the stepwise policy
+	LateStepMinutes *int   `json:"late_step_minutes,omitempty"`
+	Status          string `json:"status"`
+
+	// TimeZone IANA time zone of the event
+	TimeZone         *string `json:"time_zone,omitempty"`
+	TimelineStatusId int     `json:"timeline_status_id"`
+	Title            string  `json:"title"`
+	TrackId          int     `json:"track_id"`
+
+	// Version Version of the timeline, also sent as its ETag
+	Version *int `json:"version,omitempty"`
 }
 
+// TimelineResponseCountedRevision Which revision of a team's result counts toward the ranking
+type TimelineResponseCountedRevision string
+
+// TimelineResponseLatePolicy How submissions after the deadline are treated
+type TimelineResponseLatePolicy string
+
 // TimelineStatusResponse defines model for TimelineStatusResponse.
 type TimelineStatusResponse struct {
 	CountNum int `json:"count_num"`
@@ -42,15 +139,35 @@ type TimelineStatusResponse struct {
 
 // TimelineUpdate defines model for TimelineUpdate.
 type TimelineUpdate struct {
-	Deadline         *time.Time `json:"deadline,omitempty"`
-	Description      *string    `json:"description,omitempty"`
-	IsBlocking       *string    `json:"is_blocking,omitempty"`
-	Status           *string    `json:"status,omitempty"`
-	TimelineStatusId *int       `json:"timeline_status_id,omitempty"`
-	Title            *string    `json:"title,omitempty"`
-	TrackId          *int       `json:"track_id,omitempty"`
+	// CountedRevision Which revision of a team's result counts toward the ranking
+	CountedRevision *TimelineUpdateCountedRevision `json:"counted_revision,omitempty"`
+	Deadline        *time.Time                     `json:"deadline,omitempty"`
+	Description     *string                        `json:"description,omitempty"`
+	IsBlocking      *string                        `json:"is_blocking,omitempty"`
+
+	// LateGraceMinutes Minutes after the deadline before a submission counts as late
+	LateGraceMinutes *int `json:"late_grace_minutes,omitempty"`
+
+	// LatePenalty Penalty in percent per hour for linear or per step for stepwise policies
+	LatePenalty *int `json:"late_penalty,omitempty"`
+
+	// LatePolicy How submissions after the deadline are treated
+	LatePolicy *TimelineUpdateLatePolicy `json:"late_policy,omitempty"`
+
+	// LateStepMinutes Length of a penalty step for the stepwise policy
+	LateStepMinutes  *int    `json:"late_step_minutes,omitempty"`
+	Status           *string `json:"status,omitempty"`
+	TimelineStatusId *int    `json:"timeline_status_id,omitempty"`
+	Title            *string `json:"title,omitempty"`
+	TrackId          *int    `json:"track_id,omitempty"`
 }
 
+// TimelineUpdateCountedRevision Which revision of a team's result counts toward the ranking
+type TimelineUpdateCountedRevision string
+
+// TimelineUpdateLatePolicy How submissions after the deadline are treated
+type TimelineUpdateLatePolicy string
+
 // Id defines model for Id.
 type Id = int
 
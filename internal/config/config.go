@@ -10,8 +10,14 @@ import (
 type Config struct {
 	Env         string `yaml:"env" env-default:"local"`
 	AuthUrl     string `yaml:"auth-url" env-default:"http://user_and_teams_service:8000/api/v0/auth/auth-check"`
+	Admins      []int  `yaml:"admins" env:"ADMIN_IDS"`
 	HTTPServer  `yaml:"http_server"`
 	SQLDatabase `yaml:"sql_database"`
+	Retention   `yaml:"retention"`
+	Teams       TeamsService `yaml:"teams_service"`
+	Submissions Submissions  `yaml:"submissions"`
+	Statistics  Statistics   `yaml:"statistics"`
+	Tracing     Tracing      `yaml:"tracing"`
 }
 
 type HTTPServer struct {
@@ -31,6 +37,59 @@ type SQLDatabase struct {
 	MaxOverflow int    `yaml:"max_overflow" env-default:"10"`
 }
 
+// TeamsService tunes the client of the user and teams service. An empty URL
+// means the API root next to AuthUrl.
+type TeamsService struct {
+	URL              string        `yaml:"url"`
+	Timeout          time.Duration `yaml:"timeout" env-default:"3s"`
+	Retries          int           `yaml:"retries" env-default:"2"`
+	RetryBackoff     time.Duration `yaml:"retry_backoff" env-default:"200ms"`
+	FailureThreshold int           `yaml:"failure_threshold" env-default:"5"`
+	OpenTimeout      time.Duration `yaml:"open_timeout" env-default:"30s"`
+}
+
+// Submissions configures uploaded submission files. Storage is either
+// "local" or "s3". Without a LinkSecret a random one is generated at start,
+// so download links do not survive a restart.
+type Submissions struct {
+	Storage      string        `yaml:"storage" env-default:"local"`
+	LocalDir     string        `yaml:"local_dir" env-default:"data/submissions"`
+	MaxSize      int64         `yaml:"max_size" env-default:"52428800"`
+	AllowedTypes []string      `yaml:"allowed_types" env-default:"application/pdf,application/zip,application/x-gzip,image/png,image/jpeg,video/mp4,text/plain"`
+	LinkTTL      time.Duration `yaml:"link_ttl" env-default:"15m"`
+	LinkSecret   string        `yaml:"link_secret" env:"SUBMISSION_LINK_SECRET"`
+	S3           S3Storage     `yaml:"s3"`
+}
+
+type S3Storage struct {
+	Endpoint  string `yaml:"endpoint"`
+	Region    string `yaml:"region" env-default:"us-east-1"`
+	Bucket    string `yaml:"bucket"`
+	AccessKey string `yaml:"access_key" env:"S3_ACCESS_KEY"`
+	SecretKey string `yaml:"secret_key" env:"S3_SECRET_KEY"`
+}
+
+// Statistics configures the track statistics cache. Submissions invalidate
+// it at once, CacheTTL bounds the staleness from any other change.
+type Statistics struct {
+	CacheTTL time.Duration `yaml:"cache_ttl" env-default:"5m"`
+}
+
+// Tracing configures span export. Exporter is "otlp", "stdout" or "none",
+// an empty Endpoint leaves the OTLP exporter to its OTEL_EXPORTER_OTLP_*
+// environment variables.
+type Tracing struct {
+	ServiceName string  `yaml:"service_name" env:"OTEL_SERVICE_NAME" env-default:"event_service"`
+	Exporter    string  `yaml:"exporter" env:"OTEL_TRACES_EXPORTER" env-default:"none"`
+	Endpoint    string  `yaml:"endpoint"`
+	Insecure    bool    `yaml:"insecure" env-default:"false"`
+	SampleRatio float64 `yaml:"sample_ratio" env-default:"1"`
+}
+
+type Retention struct {
+	Period time.Duration `yaml:"period" env-default:"720h"`
+}
+
 func MustLoad() *Config {
 	configPath := os.Getenv("CONFIG_PATH")
 	if configPath == "" {
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type AuditRecord struct {
+	tableName struct{} `pg:"audit_log"`
+
+	ID        int64           `pg:"id,pk" json:"id"`
+	Entity    string          `pg:"entity,notnull" json:"entity"`
+	EntityID  string          `pg:"entity_id,notnull" json:"entity_id"`
+	Action    string          `pg:"action,notnull" json:"action"`
+	ActorID   *int            `pg:"actor_id" json:"actor_id"`
+	RequestID string          `pg:"request_id" json:"request_id,omitempty"`
+	Before    json.RawMessage `pg:"before,type:jsonb" json:"before,omitempty"`
+	After     json.RawMessage `pg:"after,type:jsonb" json:"after,omitempty"`
+	Diff      json.RawMessage `pg:"diff,type:jsonb" json:"diff,omitempty"`
+	CreatedAt time.Time       `pg:"created_at,default:now()" json:"created_at"`
+}
@@ -11,6 +11,9 @@ type Event struct {
 	CreatedAt    time.Time `pg:"created_at,default:now()"`
 	UpdatedAt    time.Time `pg:"updated_at"`
 	Status       string    `pg:"status"`
+	TimeZone     string    `pg:"time_zone,default:'UTC'"`
+	Version      int       `pg:"version,default:1"`
+	DeletedAt    time.Time `pg:"deleted_at,soft_delete"`
 
 	DateID int   `pg:"date_id"`
 	Date   *Date `pg:"rel:has-one"`
@@ -18,4 +21,6 @@ type Event struct {
 	Locations   []Location   `pg:"many2many:event_location"`
 	Tracks      []Track      `pg:"rel:has-many"`
 	EventPrizes []EventPrize `pg:"rel:has-many"`
+
+	Local LocalTimes `pg:"-"`
 }
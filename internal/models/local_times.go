@@ -0,0 +1,7 @@
+package models
+
+import "time"
+
+// LocalTimes holds the time columns of a row rendered in the time zone of its
+// event, keyed by field name. It is filled in by services and never stored.
+type LocalTimes map[string]time.Time
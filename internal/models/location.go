@@ -2,12 +2,28 @@ package models
 
 import "time"
 
+const (
+	LocationTypePhysical = "physical"
+	LocationTypeOnline   = "online"
+	LocationTypeHybrid   = "hybrid"
+)
+
 type Location struct {
-	tableName struct{}  `pg:"location"`
-	ID        int       `pg:"id,pk"`
-	Title     string    `pg:"title,type:varchar(255),unique"`
-	CreatedAt time.Time `pg:"created_at,default:now()"`
-	UpdatedAt time.Time `pg:"updated_at"`
+	tableName    struct{}  `pg:"location"`
+	ID           int       `pg:"id,pk"`
+	Title        string    `pg:"title,type:varchar(255),unique"`
+	TimeZone     string    `pg:"time_zone"`
+	Address      string    `pg:"address"`
+	Latitude     *float64  `pg:"latitude"`
+	Longitude    *float64  `pg:"longitude"`
+	Capacity     int       `pg:"capacity"`
+	TeamCapacity int       `pg:"team_capacity"`
+	Type         string    `pg:"type,default:'physical'"`
+	StreamURL    string    `pg:"stream_url"`
+	ParentID     int       `pg:"parent_id"`
+	CreatedAt    time.Time `pg:"created_at,default:now()"`
+	UpdatedAt    time.Time `pg:"updated_at"`
+	DeletedAt    time.Time `pg:"deleted_at,soft_delete"`
 
 	Events []Event `pg:"many2many:event_location"`
 }
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+const (
+	BookingSourceManual = "manual"
+	BookingSourceTrack  = "track"
+)
+
+// LocationBooking reserves a location for a time range. Track bookings are
+// derived from the track's date and rewritten whenever it changes.
+type LocationBooking struct {
+	tableName struct{}  `pg:"location_booking"`
+	ID        int       `pg:"id,pk"`
+	Source    string    `pg:"source,default:'manual'"`
+	Title     string    `pg:"title,type:varchar(255),notnull"`
+	DateStart time.Time `pg:"date_start,notnull"`
+	DateEnd   time.Time `pg:"date_end,notnull"`
+	CreatedAt time.Time `pg:"created_at,default:now()"`
+
+	LocationID int       `pg:"location_id,notnull"`
+	Location   *Location `pg:"rel:has-one"`
+
+	EventID int    `pg:"event_id"`
+	Event   *Event `pg:"rel:has-one"`
+
+	TrackID int    `pg:"track_id"`
+	Track   *Track `pg:"rel:has-one"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+type Session struct {
+	tableName   struct{}  `pg:"session"`
+	ID          int       `pg:"id,pk"`
+	Title       string    `pg:"title,type:varchar(255),notnull"`
+	Description string    `pg:"description"`
+	Kind        string    `pg:"kind,default:'other'"`
+	Attendees   int       `pg:"attendees"`
+	DateStart   time.Time `pg:"date_start,notnull"`
+	DateEnd     time.Time `pg:"date_end,notnull"`
+	Version     int       `pg:"version,default:1"`
+	CreatedAt   time.Time `pg:"created_at,default:now()"`
+	UpdatedAt   time.Time `pg:"updated_at,default:now()"`
+
+	EventID int    `pg:"event_id,notnull"`
+	Event   *Event `pg:"rel:has-one"`
+
+	TrackID int    `pg:"track_id"`
+	Track   *Track `pg:"rel:has-one"`
+
+	LocationID int       `pg:"location_id"`
+	Location   *Location `pg:"rel:has-one"`
+
+	Speakers []Speaker `pg:"many2many:session_speaker"`
+}
@@ -0,0 +1,10 @@
+package models
+
+type SessionSpeaker struct {
+	tableName struct{} `pg:"session_speaker"`
+	SessionID int      `pg:"session_id,pk"`
+	Session   *Session `pg:"rel:has-one"`
+
+	SpeakerID int      `pg:"speaker_id,pk"`
+	Speaker   *Speaker `pg:"rel:has-one"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+type Speaker struct {
+	tableName struct{}  `pg:"speaker"`
+	ID        int       `pg:"id,pk"`
+	Name      string    `pg:"name,type:varchar(255),notnull"`
+	Bio       string    `pg:"bio"`
+	Company   string    `pg:"company"`
+	UserID    int       `pg:"user_id"`
+	CreatedAt time.Time `pg:"created_at,default:now()"`
+	UpdatedAt time.Time `pg:"updated_at,default:now()"`
+
+	Sessions []Session `pg:"many2many:session_speaker"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+type StatusEvent struct {
+	tableName struct{}  `pg:"status_event"`
+	CreatedAt time.Time `pg:"created_at,default:now()"`
+
+	StatusID int     `pg:"status_id,pk"`
+	Status   *Status `pg:"rel:has-one"`
+
+	EventID int    `pg:"event_id,pk"`
+	Event   *Event `pg:"rel:has-one"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+type StatusTrack struct {
+	tableName struct{}  `pg:"status_track"`
+	CreatedAt time.Time `pg:"created_at,default:now()"`
+
+	StatusID int     `pg:"status_id,pk"`
+	Status   *Status `pg:"rel:has-one"`
+
+	TrackID int    `pg:"track_id,pk"`
+	Track   *Track `pg:"rel:has-one"`
+}
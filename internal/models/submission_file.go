@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+type SubmissionFile struct {
+	tableName   struct{}  `pg:"submission_file"`
+	ID          int       `pg:"id,pk"`
+	TrackTeamID int       `pg:"track_team_id,notnull"`
+	TimelineID  int       `pg:"timeline_id,notnull"`
+	StorageKey  string    `pg:"storage_key,notnull" json:"-"`
+	FileName    string    `pg:"file_name,notnull"`
+	ContentType string    `pg:"content_type,notnull"`
+	Size        int64     `pg:"size,notnull,use_zero"`
+	SHA256      string    `pg:"sha256,notnull"`
+	MD5         string    `pg:"md5,notnull"`
+	UploadedBy  int       `pg:"uploaded_by"`
+	CreatedAt   time.Time `pg:"created_at,default:now()"`
+}
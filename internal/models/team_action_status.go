@@ -15,4 +15,9 @@ type TeamActionStatus struct {
 	ResolutionLink string    `pg:"resolution_link"`
 	CompletedAt    time.Time `pg:"completed_at"`
 	Notes          string    `pg:"notes"`
+
+	SubmittedAt    time.Time `pg:"submitted_at,default:now()"`
+	IsLate         bool      `pg:"is_late,use_zero"`
+	LateMinutes    int       `pg:"late_minutes,use_zero"`
+	PenaltyPercent int       `pg:"penalty_percent,use_zero"`
 }
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+type TeamActionStatusRevision struct {
+	tableName      struct{}  `pg:"team_action_status_revision"`
+	ID             int       `pg:"id,pk"`
+	TrackTeamID    int       `pg:"track_team_id,notnull"`
+	TimelineID     int       `pg:"timeline_id,notnull"`
+	Revision       int       `pg:"revision,notnull"`
+	ResultValue    int       `pg:"result_value"`
+	ResolutionLink string    `pg:"resolution_link"`
+	CompletedAt    time.Time `pg:"completed_at"`
+	Notes          string    `pg:"notes"`
+	ActorID        int       `pg:"actor_id"`
+	Deleted        bool      `pg:"deleted,use_zero"`
+	CreatedAt      time.Time `pg:"created_at,default:now()"`
+}
@@ -2,16 +2,37 @@ package models
 
 import "time"
 
+const (
+	CountedRevisionFirst = "first"
+	CountedRevisionLast  = "last"
+	CountedRevisionBest  = "best"
+
+	LatePolicyReject   = "reject"
+	LatePolicyGrace    = "grace"
+	LatePolicyLinear   = "linear"
+	LatePolicyStepwise = "stepwise"
+	LatePolicyFlag     = "flag"
+)
+
 type Timeline struct {
 	tableName struct{} `pg:"timeline"`
 
-	ID          int       `pg:"id,pk"`
-	Title       string    `pg:"title,type:varchar(255),notnull"`
-	Description string    `pg:"description"`
-	Deadline    time.Time `pg:"deadline"`
-	IsBlocking  bool      `pg:"is_blocking,notnull"`
-	IsScoring   bool      `pg:"is_scoring"`
-	Status      string    `pg:"status"`
+	ID               int       `pg:"id,pk"`
+	Title            string    `pg:"title,type:varchar(255),notnull"`
+	Description      string    `pg:"description"`
+	Deadline         time.Time `pg:"deadline"`
+	IsBlocking       bool      `pg:"is_blocking,notnull"`
+	IsScoring        bool      `pg:"is_scoring"`
+	Status           string    `pg:"status"`
+	CountedRevision  string    `pg:"counted_revision,default:'last'"`
+	LatePolicy       string    `pg:"late_policy,default:'flag'"`
+	LateGraceMinutes int       `pg:"late_grace_minutes,use_zero"`
+	LatePenalty      int       `pg:"late_penalty,use_zero"`
+	LateStepMinutes  int       `pg:"late_step_minutes"`
+	Version          int       `pg:"version,default:1"`
+	CreatedAt        time.Time `pg:"created_at,default:now()"`
+	UpdatedAt        time.Time `pg:"updated_at,default:now()"`
+	DeletedAt        time.Time `pg:"deleted_at,soft_delete"`
 
 	TrackID int    `pg:"track_id"`
 	Track   *Track `pg:"rel:has-one"`
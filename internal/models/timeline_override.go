@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// TimelineOverride replaces the deadline or blocking flag of a timeline for
+// a single registered team. Unset fields keep the timeline value.
+type TimelineOverride struct {
+	tableName   struct{}  `pg:"timeline_override"`
+	ID          int       `pg:"id,pk"`
+	TimelineID  int       `pg:"timeline_id,notnull"`
+	TrackTeamID int       `pg:"track_team_id,notnull"`
+	Deadline    time.Time `pg:"deadline"`
+	IsBlocking  *bool     `pg:"is_blocking"`
+	Reason      string    `pg:"reason,notnull"`
+	AuthorID    int       `pg:"author_id,notnull"`
+	CreatedAt   time.Time `pg:"created_at,default:now()"`
+	UpdatedAt   time.Time `pg:"updated_at,default:now()"`
+}
@@ -1,12 +1,22 @@
 package models
 
+import "time"
+
 type Track struct {
-	tableName    struct{} `pg:"track"`
-	ID           int      `pg:"id,pk"`
-	Title        string   `pg:"title,type:varchar(255),notnull"`
-	Description  string   `pg:"description"`
-	IsScoreBased bool     `pg:"is_score_based,notnull"`
-	Status       string   `pg:"status"`
+	tableName    struct{}  `pg:"track"`
+	ID           int       `pg:"id,pk"`
+	Title        string    `pg:"title,type:varchar(255),notnull"`
+	Description  string    `pg:"description"`
+	IsScoreBased bool      `pg:"is_score_based,notnull"`
+	Status       string    `pg:"status"`
+	MaxTeams     int       `pg:"max_teams"`
+	Version      int       `pg:"version,default:1"`
+	CreatedAt    time.Time `pg:"created_at,default:now()"`
+	UpdatedAt    time.Time `pg:"updated_at,default:now()"`
+	DeletedAt    time.Time `pg:"deleted_at,soft_delete"`
+
+	RegistrationOpensAt  time.Time `pg:"registration_opens_at"`
+	RegistrationClosesAt time.Time `pg:"registration_closes_at"`
 
 	EventID int    `pg:"event_id"`
 	Event   *Event `pg:"rel:has-one"`
@@ -20,4 +30,6 @@ type Track struct {
 	TrackJudges  []TrackJudge  `pg:"many2many:track_judge"`
 	TrackWinners []TrackWinner `pg:"many2many:track_winner"`
 	Locations    []Location    `pg:"many2many:location_track"`
+
+	Local LocalTimes `pg:"-"`
 }
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+type TrackEligibility struct {
+	tableName    struct{}  `pg:"track_eligibility"`
+	TrackID      int       `pg:"track_id,pk"`
+	MinTeamSize  int       `pg:"min_team_size"`
+	MaxTeamSize  int       `pg:"max_team_size"`
+	StudentsOnly bool      `pg:"students_only,notnull,use_zero"`
+	OnePerEvent  bool      `pg:"one_per_event,notnull,use_zero"`
+	NoOverlap    bool      `pg:"no_overlap,notnull,use_zero"`
+	UpdatedAt    time.Time `pg:"updated_at,default:now()"`
+}
@@ -1,6 +1,8 @@
 package models
 
 type TrackRole struct {
+	tableName struct{} `pg:"track_role"`
+
 	TrackID int    `pg:"track_id,pk"`
 	Track   *Track `pg:"rel:has-one"`
 
@@ -1,10 +1,23 @@
 package models
 
+import "time"
+
+const (
+	TrackTeamPending    = "pending"
+	TrackTeamApproved   = "approved"
+	TrackTeamRejected   = "rejected"
+	TrackTeamWithdrawn  = "withdrawn"
+	TrackTeamWaitlisted = "waitlisted"
+)
+
 type TrackTeam struct {
-	tableName struct{} `pg:"track_team"`
-	ID        int      `pg:"id,pk"`
-	TeamID    int      `pg:"team_id,notnull"`
-	IsActive  bool     `pg:"is_active,notnull"`
+	tableName struct{}  `pg:"track_team"`
+	ID        int       `pg:"id,pk"`
+	TeamID    int       `pg:"team_id,notnull"`
+	IsActive  bool      `pg:"is_active,notnull,use_zero"`
+	State     string    `pg:"state,default:'pending'"`
+	CreatedAt time.Time `pg:"created_at,default:now()"`
+	DecidedAt time.Time `pg:"decided_at"`
 
 	TrackID int    `pg:"track_id"`
 	Track   *Track `pg:"rel:has-one"`
@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"event_service/internal/models"
+	"event_service/internal/schemas"
+	"github.com/go-pg/pg/v10"
+)
+
+type AuditRepository struct {
+	DB *pg.DB
+}
+
+func NewAuditRepository(db *pg.DB) *AuditRepository {
+	return &AuditRepository{DB: db}
+}
+
+func (r *AuditRepository) Create(tx *pg.Tx, record *models.AuditRecord) (*models.AuditRecord, error) {
+	_, err := tx.Model(record).Insert()
+	return record, err
+}
+
+func (r *AuditRepository) GetAuditRecords(tx *pg.Tx, filter *schemas.AuditFilter) ([]*models.AuditRecord, error) {
+	records := make([]*models.AuditRecord, 0)
+	query := tx.Model(&records)
+
+	if filter.Entity != "" {
+		query = query.Where("entity = ?", filter.Entity)
+	}
+
+	if filter.EntityID != "" {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+
+	if filter.ActorID != 0 {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+
+	err := query.Order("created_at DESC", "id DESC").Limit(filter.Limit).Offset(filter.Offset).Select()
+	return records, err
+}
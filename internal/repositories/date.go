@@ -43,6 +43,18 @@ func (r *DateRepository) ChangeDateEnd(tx *pg.Tx, id int, dateEnd *time.Time) (*
 	return date, err
 }
 
+// BumpOwnerVersions counts a date change as a new version of the events and
+// tracks scheduled by it, which is what their ETags and calendar sequences
+// follow.
+func (r *DateRepository) BumpOwnerVersions(tx *pg.Tx, id int) error {
+	if _, err := tx.Exec("UPDATE event SET version = version + 1 WHERE date_id = ?", id); err != nil {
+		return err
+	}
+
+	_, err := tx.Exec("UPDATE track SET version = version + 1 WHERE date_id = ?", id)
+	return err
+}
+
 func (r *DateRepository) DeleteDate(tx *pg.Tx, id int) error {
 	date := &models.Date{ID: id}
 	_, err := tx.Model(date).WherePK().Delete()
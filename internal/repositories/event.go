@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"event_service/internal/models"
+	"event_service/internal/schemas"
 	"github.com/go-pg/pg/v10"
 	"time"
 )
@@ -19,9 +20,19 @@ func (r *EventRepository) Create(tx *pg.Tx, event *models.Event) (*models.Event,
 	return event, err
 }
 
-func (r *EventRepository) GetAllEvents(tx *pg.Tx) ([]*models.Event, error) {
+func (r *EventRepository) GetAllEvents(tx *pg.Tx, filter *schemas.EventFilter) ([]*models.Event, error) {
 	events := make([]*models.Event, 0)
-	err := tx.Model(&events).Relation("Date").Select()
+	query := tx.Model(&events).Relation("Date")
+
+	if filter.Status != "" {
+		query.Where("event.status = ?", filter.Status)
+	}
+
+	if filter.StatusID != 0 {
+		query.Where("EXISTS (SELECT 1 FROM status_event se WHERE se.event_id = event.id AND se.status_id = ?)", filter.StatusID)
+	}
+
+	err := query.Select()
 	return events, err
 }
 
@@ -37,6 +48,20 @@ func (r *EventRepository) GetEventByID(tx *pg.Tx, eventID int) (*models.Event, e
 	return event, err
 }
 
+func (r *EventRepository) GetEventWithDateByID(tx *pg.Tx, eventID int) (*models.Event, error) {
+	event := new(models.Event)
+	err := tx.Model(event).Relation("Date").Where("event.id = ?", eventID).Select()
+	return event, err
+}
+
+// GetEventWithDateByIDIncludingDeleted also finds a deleted event, calendar
+// feeds keep showing it as cancelled until it is purged.
+func (r *EventRepository) GetEventWithDateByIDIncludingDeleted(tx *pg.Tx, eventID int) (*models.Event, error) {
+	event := new(models.Event)
+	err := tx.Model(event).AllWithDeleted().Relation("Date").Where("event.id = ?", eventID).Select()
+	return event, err
+}
+
 func (r *EventRepository) GetAllEventsToStart(tx *pg.Tx) ([]*models.Event, error) {
 	events := make([]*models.Event, 0)
 	err := tx.Model(&events).Relation("Date").Where("date.date_start <= NOW() AND status = 'planned'").Select()
@@ -55,15 +80,62 @@ func (r *EventRepository) GetEventByStatus(tx *pg.Tx, status string) ([]*models.
 	return events, err
 }
 
-func (r *EventRepository) UpdateEvent(tx *pg.Tx, eventId int, newEvent *models.Event) (*models.Event, error) {
+func (r *EventRepository) UpdateEvent(tx *pg.Tx, eventId int, newEvent *models.Event, version int) (*models.Event, error) {
 	event := new(models.Event)
-	_, err := tx.Model(event).Set("title = ?, description = ?, redirect_link = ?, date_id = ?, status = ?", newEvent.Title,
-		newEvent.Description, newEvent.RedirectLink, newEvent.DateID, newEvent.Status).Where("id = ?", eventId).Returning("*").Update()
+	query := tx.Model(event).Set("title = ?, description = ?, redirect_link = ?, date_id = ?, status = ?, time_zone = ?", newEvent.Title,
+		newEvent.Description, newEvent.RedirectLink, newEvent.DateID, newEvent.Status, newEvent.TimeZone).
+		Set("version = version + 1").Where("id = ?", eventId)
+
+	if version > 0 {
+		query.Where("version = ?", version)
+	}
+
+	_, err := query.Returning("*").Update()
 	return event, err
 }
 
-func (r *EventRepository) DeleteEvent(tx *pg.Tx, eventID int) error {
-	event := new(models.Event)
-	_, err := tx.Model(event).Where("id = ?", eventID).Delete()
+func (r *EventRepository) DeleteEvent(tx *pg.Tx, eventID int, deletedAt time.Time) error {
+	_, err := tx.Model((*models.Event)(nil)).Set("deleted_at = ?, version = version + 1", deletedAt).Where("id = ?", eventID).Update()
 	return err
 }
+
+func (r *EventRepository) GetDeletedEvents(tx *pg.Tx) ([]*models.Event, error) {
+	events := make([]*models.Event, 0)
+	err := tx.Model(&events).Deleted().Order("deleted_at DESC").Select()
+	return events, err
+}
+
+func (r *EventRepository) GetDeletedEventByID(tx *pg.Tx, eventID int) (*models.Event, error) {
+	event := new(models.Event)
+	err := tx.Model(event).Deleted().Where("id = ?", eventID).Select()
+	return event, err
+}
+
+func (r *EventRepository) RestoreEvent(tx *pg.Tx, eventID int) (*models.Event, error) {
+	event := new(models.Event)
+	_, err := tx.Model(event).Deleted().Set("deleted_at = NULL, version = version + 1").Where("id = ?", eventID).Returning("*").Update()
+	return event, err
+}
+
+func (r *EventRepository) PurgeDeletedBefore(tx *pg.Tx, before time.Time) (int, error) {
+	deleted := "SELECT id FROM event WHERE deleted_at < ?"
+
+	if _, err := tx.Exec("DELETE FROM event_prize WHERE event_id IN ("+deleted+")", before); err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.Exec("DELETE FROM event_location WHERE event_id IN ("+deleted+")", before); err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.Exec("DELETE FROM session WHERE event_id IN ("+deleted+")", before); err != nil {
+		return 0, err
+	}
+
+	res, err := tx.Model((*models.Event)(nil)).Deleted().Where("deleted_at < ?", before).ForceDelete()
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected(), nil
+}
@@ -40,6 +40,36 @@ func (r *EventLocationRepository) GetAllLocationsEvents(tx *pg.Tx, LocationId in
 	return events, err
 }
 
+// GetEventIDsByLocationIDs maps each location to its live events.
+func (r *EventLocationRepository) GetEventIDsByLocationIDs(tx *pg.Tx, locationIDs []int) (map[int][]int, error) {
+	eventIDs := make(map[int][]int, len(locationIDs))
+	if len(locationIDs) == 0 {
+		return eventIDs, nil
+	}
+
+	var rows []struct {
+		LocationID int
+		EventID    int
+	}
+
+	_, err := tx.Query(&rows, `
+		SELECT el.location_id, el.event_id
+		FROM event_location el
+		JOIN event e ON e.id = el.event_id AND e.deleted_at IS NULL
+		WHERE el.location_id IN (?)
+		ORDER BY el.location_id, el.event_id
+	`, pg.In(locationIDs))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		eventIDs[row.LocationID] = append(eventIDs[row.LocationID], row.EventID)
+	}
+
+	return eventIDs, nil
+}
+
 func (r *EventLocationRepository) DeleteEventLocation(tx *pg.Tx, EventId int, LocationId int) error {
 	eventLocation := &models.EventLocation{EventID: EventId, LocationID: LocationId}
 	_, err := tx.Model(eventLocation).WherePK().Delete()
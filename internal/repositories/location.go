@@ -2,9 +2,16 @@ package repositories
 
 import (
 	"event_service/internal/models"
+	"event_service/internal/schemas"
 	"github.com/go-pg/pg/v10"
+	"time"
 )
 
+// distanceKm is the great-circle distance in kilometres between a location
+// and the point ?0, ?1. LEAST guards acos against rounding above one.
+const distanceKm = "6371 * acos(least(1, cos(radians(?0)) * cos(radians(latitude)) * " +
+	"cos(radians(longitude) - radians(?1)) + sin(radians(?0)) * sin(radians(latitude))))"
+
 type LocationRepository struct {
 	DB *pg.DB
 }
@@ -24,6 +31,41 @@ func (r *LocationRepository) GetAllLocations(tx *pg.Tx) ([]*models.Location, err
 	return locations, err
 }
 
+// GetLocations lists locations matching the filter. A near search only
+// returns locations with coordinates, ordered by distance.
+func (r *LocationRepository) GetLocations(tx *pg.Tx, filter *schemas.LocationFilter) ([]*models.Location, error) {
+	locations := make([]*models.Location, 0)
+	query := tx.Model(&locations)
+
+	if filter.ParentID != 0 {
+		query.Where("parent_id = ?", filter.ParentID)
+	}
+
+	if filter.Latitude != nil && filter.Longitude != nil {
+		query.Where("latitude IS NOT NULL AND longitude IS NOT NULL").
+			Where(distanceKm+" <= ?2", *filter.Latitude, *filter.Longitude, filter.RadiusKm).
+			OrderExpr(distanceKm, *filter.Latitude, *filter.Longitude)
+	} else {
+		query.Order("id")
+	}
+
+	err := query.Select()
+	return locations, err
+}
+
+// GetAncestorIDs walks up the hierarchy from the location, including it.
+func (r *LocationRepository) GetAncestorIDs(tx *pg.Tx, id int) ([]int, error) {
+	ids := make([]int, 0)
+	_, err := tx.QueryOne(pg.Scan(pg.Array(&ids)), `
+		WITH RECURSIVE ancestors AS (
+			SELECT id, parent_id FROM location WHERE id = ?0
+			UNION
+			SELECT l.id, l.parent_id FROM location l JOIN ancestors a ON l.id = a.parent_id
+		)
+		SELECT coalesce(array_agg(id), '{}') FROM ancestors`, id)
+	return ids, err
+}
+
 func (r *LocationRepository) GetLocationById(tx *pg.Tx, id int) (*models.Location, error) {
 	location := new(models.Location)
 	err := tx.Model(location).Where("id = ?", id).Select()
@@ -32,7 +74,12 @@ func (r *LocationRepository) GetLocationById(tx *pg.Tx, id int) (*models.Locatio
 
 func (r *LocationRepository) Update(tx *pg.Tx, locationId int, newLocation *models.Location) (*models.Location, error) {
 	location := new(models.Location)
-	_, err := tx.Model(location).Set("title = ?", newLocation.Title).Where("id = ?", locationId).Returning("*").Update()
+	_, err := tx.Model(location).
+		Set("title = ?, time_zone = NULLIF(?, '')", newLocation.Title, newLocation.TimeZone).
+		Set("address = NULLIF(?, ''), latitude = ?, longitude = ?", newLocation.Address, newLocation.Latitude, newLocation.Longitude).
+		Set("capacity = NULLIF(?, 0), team_capacity = NULLIF(?, 0)", newLocation.Capacity, newLocation.TeamCapacity).
+		Set("type = ?, stream_url = NULLIF(?, ''), parent_id = NULLIF(?, 0)", newLocation.Type, newLocation.StreamURL, newLocation.ParentID).
+		Where("id = ?", locationId).Returning("*").Update()
 	return location, err
 }
 
@@ -47,3 +94,42 @@ func (r *LocationRepository) DeleteLocation(tx *pg.Tx, id int) error {
 	_, err := tx.Model(location).WherePK().Delete()
 	return err
 }
+
+func (r *LocationRepository) GetDeletedLocations(tx *pg.Tx) ([]*models.Location, error) {
+	locations := make([]*models.Location, 0)
+	err := tx.Model(&locations).Deleted().Order("deleted_at DESC").Select()
+	return locations, err
+}
+
+func (r *LocationRepository) RestoreLocation(tx *pg.Tx, id int) (*models.Location, error) {
+	location := new(models.Location)
+	_, err := tx.Model(location).Deleted().Set("deleted_at = NULL").Where("id = ?", id).Returning("*").Update()
+	return location, err
+}
+
+func (r *LocationRepository) PurgeDeletedBefore(tx *pg.Tx, before time.Time) (int, error) {
+	deleted := "SELECT id FROM location WHERE deleted_at < ?"
+
+	if _, err := tx.Exec("DELETE FROM event_location WHERE location_id IN ("+deleted+")", before); err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.Exec("DELETE FROM location_track WHERE location_id IN ("+deleted+")", before); err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.Exec("UPDATE session SET location_id = NULL WHERE location_id IN ("+deleted+")", before); err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.Exec("UPDATE location SET parent_id = NULL WHERE parent_id IN ("+deleted+")", before); err != nil {
+		return 0, err
+	}
+
+	res, err := tx.Model((*models.Location)(nil)).Deleted().Where("deleted_at < ?", before).ForceDelete()
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected(), nil
+}
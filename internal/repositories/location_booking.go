@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"event_service/internal/models"
+	"github.com/go-pg/pg/v10"
+	"time"
+)
+
+type LocationBookingRepository struct {
+	DB *pg.DB
+}
+
+func NewLocationBookingRepository(db *pg.DB) *LocationBookingRepository {
+	return &LocationBookingRepository{DB: db}
+}
+
+func (r *LocationBookingRepository) Create(tx *pg.Tx, booking *models.LocationBooking) (*models.LocationBooking, error) {
+	_, err := tx.Model(booking).Insert()
+	return booking, err
+}
+
+func (r *LocationBookingRepository) GetBookingByID(tx *pg.Tx, locationID int, id int) (*models.LocationBooking, error) {
+	booking := new(models.LocationBooking)
+	err := tx.Model(booking).Where("id = ? AND location_id = ?", id, locationID).Select()
+	return booking, err
+}
+
+// GetBookings returns bookings of the location overlapping from..to, zero
+// bounds leave that side open.
+func (r *LocationBookingRepository) GetBookings(tx *pg.Tx, locationID int, from time.Time, to time.Time) ([]*models.LocationBooking, error) {
+	bookings := make([]*models.LocationBooking, 0)
+	query := tx.Model(&bookings).Where("location_id = ?", locationID)
+
+	if !from.IsZero() {
+		query.Where("date_end > ?", from)
+	}
+
+	if !to.IsZero() {
+		query.Where("date_start < ?", to)
+	}
+
+	err := query.Order("date_start", "id").Select()
+	return bookings, err
+}
+
+// GetConflicting returns bookings of the location that a session of the
+// event and track cannot share: anything booked by another event, and
+// bookings of another track of the same event.
+func (r *LocationBookingRepository) GetConflicting(tx *pg.Tx, locationID int, start time.Time, end time.Time,
+	eventID int, trackID int) ([]*models.LocationBooking, error) {
+	bookings := make([]*models.LocationBooking, 0)
+	err := tx.Model(&bookings).
+		Where("location_id = ?", locationID).
+		Where("date_start < ? AND date_end > ?", end, start).
+		Where("NOT (coalesce(event_id, 0) = ? AND coalesce(track_id, 0) IN (0, ?))", eventID, trackID).
+		Order("date_start").
+		Select()
+	return bookings, err
+}
+
+func (r *LocationBookingRepository) DeleteBooking(tx *pg.Tx, id int) error {
+	booking := &models.LocationBooking{ID: id}
+	_, err := tx.Model(booking).WherePK().Delete()
+	return err
+}
+
+// SyncTrackBookings rebuilds the bookings derived from the tracks' dates and
+// locations. Overlaps with other bookings fail on the exclusion constraint,
+// tracks whose date holds no time get no booking.
+func (r *LocationBookingRepository) SyncTrackBookings(tx *pg.Tx, trackIDs []int) error {
+	if len(trackIDs) == 0 {
+		return nil
+	}
+
+	return r.syncBookings(tx, "t.id IN (?)", pg.In(trackIDs))
+}
+
+// SyncDateBookings rebuilds the bookings of every track using the date.
+func (r *LocationBookingRepository) SyncDateBookings(tx *pg.Tx, dateID int) error {
+	return r.syncBookings(tx, "t.date_id = ?", dateID)
+}
+
+func (r *LocationBookingRepository) syncBookings(tx *pg.Tx, condition string, param interface{}) error {
+	_, err := tx.Exec(`
+		DELETE FROM location_booking
+		WHERE source = 'track' AND track_id IN (SELECT t.id FROM track t WHERE `+condition+`)`, param)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO location_booking (location_id, event_id, track_id, source, title, date_start, date_end)
+		SELECT lt.location_id, t.event_id, t.id, 'track', t.title, d.date_start, d.date_end
+		FROM location_track lt
+			JOIN track t ON t.id = lt.track_id AND t.deleted_at IS NULL
+			JOIN date d ON d.id = t.date_id AND d.date_end > d.date_start
+		WHERE `+condition, param)
+	return err
+}
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"event_service/internal/models"
+	"event_service/internal/schemas"
 	"github.com/go-pg/pg/v10"
 )
 
@@ -45,3 +46,22 @@ func (r *LocationTrackRepository) DeleteTrackLocation(tx *pg.Tx, TrackId int, Lo
 	_, err := tx.Model(tracksLocation).WherePK().Delete()
 	return err
 }
+
+// GetLocationLoads counts active teams of active tracks for every location
+// with a team capacity that the track is held in.
+func (r *LocationTrackRepository) GetLocationLoads(tx *pg.Tx, trackId int) ([]*schemas.LocationLoad, error) {
+	loads := make([]*schemas.LocationLoad, 0)
+
+	_, err := tx.Query(&loads, `
+		SELECT l.id AS location_id, l.team_capacity, count(DISTINCT tt.team_id) AS teams
+		FROM location l
+		JOIN location_track lt ON lt.location_id = l.id
+		JOIN track t ON t.id = lt.track_id AND t.deleted_at IS NULL
+		JOIN track_team tt ON tt.track_id = t.id AND tt.is_active
+		WHERE l.team_capacity IS NOT NULL
+			AND l.id IN (SELECT location_id FROM location_track WHERE track_id = ?0)
+		GROUP BY l.id, l.team_capacity
+	`, trackId)
+
+	return loads, err
+}
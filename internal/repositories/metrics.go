@@ -0,0 +1,38 @@
+package repositories
+
+import "github.com/go-pg/pg/v10"
+
+type StatusCount struct {
+	Status string
+	Count  int
+}
+
+type MetricsRepository struct {
+	DB *pg.DB
+}
+
+func NewMetricsRepository(db *pg.DB) *MetricsRepository {
+	return &MetricsRepository{DB: db}
+}
+
+func (r *MetricsRepository) CountEventsByStatus(tx *pg.Tx) ([]*StatusCount, error) {
+	return r.countBy(tx, `SELECT coalesce(status, '') AS status, count(*) AS count FROM event WHERE deleted_at IS NULL GROUP BY 1`)
+}
+
+func (r *MetricsRepository) CountTracksByStatus(tx *pg.Tx) ([]*StatusCount, error) {
+	return r.countBy(tx, `SELECT coalesce(status, '') AS status, count(*) AS count FROM track WHERE deleted_at IS NULL GROUP BY 1`)
+}
+
+func (r *MetricsRepository) CountRegistrationsByState(tx *pg.Tx) ([]*StatusCount, error) {
+	return r.countBy(tx, `
+		SELECT tt.state AS status, count(*) AS count
+		FROM track_team tt
+		JOIN track t ON t.id = tt.track_id AND t.deleted_at IS NULL
+		GROUP BY 1`)
+}
+
+func (r *MetricsRepository) countBy(tx *pg.Tx, query string) ([]*StatusCount, error) {
+	counts := make([]*StatusCount, 0)
+	_, err := tx.Query(&counts, query)
+	return counts, err
+}
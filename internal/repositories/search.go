@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"event_service/internal/schemas"
+	"github.com/go-pg/pg/v10"
+	"strings"
+)
+
+// searchQuery matches the expression indexes created for search_document,
+// so every branch must call it with the same columns. Snippets come from
+// search_snippet, which escapes the text it wraps in <b> tags.
+const searchQuery = `
+	WITH q AS (SELECT websearch_to_tsquery('russian', ?) || websearch_to_tsquery('english', ?) AS query)
+	SELECT * FROM (
+		SELECT 'event' AS type, e.id, e.id AS event_id, NULL AS track_id, e.title, e.status,
+			d.date_start, d.date_end,
+			ts_rank(search_document(e.title, e.description), q.query) AS rank,
+			search_snippet(e.title, e.description, q.query, ?) AS snippet
+		FROM event e
+			CROSS JOIN q
+			LEFT JOIN date d ON d.id = e.date_id
+		WHERE e.deleted_at IS NULL AND search_document(e.title, e.description) @@ q.query
+		UNION ALL
+		SELECT 'track', t.id, t.event_id, t.id, t.title, t.status,
+			d.date_start, d.date_end,
+			ts_rank(search_document(t.title, t.description), q.query),
+			search_snippet(t.title, t.description, q.query, ?)
+		FROM track t
+			CROSS JOIN q
+			LEFT JOIN date d ON d.id = t.date_id
+		WHERE t.deleted_at IS NULL AND search_document(t.title, t.description) @@ q.query
+		UNION ALL
+		SELECT 'timeline', tl.id, t.event_id, t.id, tl.title, tl.status,
+			tl.deadline, tl.deadline,
+			ts_rank(search_document(tl.title, tl.description), q.query),
+			search_snippet(tl.title, tl.description, q.query, ?)
+		FROM timeline tl
+			CROSS JOIN q
+			JOIN track t ON t.id = tl.track_id AND t.deleted_at IS NULL
+		WHERE tl.deleted_at IS NULL AND search_document(tl.title, tl.description) @@ q.query
+	) results
+	WHERE `
+
+const searchHeadlineOptions = "StartSel=<b>, StopSel=</b>, MaxWords=35, MinWords=15, MaxFragments=2"
+
+type SearchRepository struct {
+	DB *pg.DB
+}
+
+func NewSearchRepository(db *pg.DB) *SearchRepository {
+	return &SearchRepository{DB: db}
+}
+
+// Search ranks events, tracks and timelines matching the query in either
+// language. Date bounds keep results whose range overlaps From..To.
+func (r *SearchRepository) Search(tx *pg.Tx, filter *schemas.SearchFilter) ([]*schemas.SearchResult, error) {
+	results := make([]*schemas.SearchResult, 0)
+
+	conditions := []string{"type IN (?)"}
+	params := []interface{}{filter.Query, filter.Query, searchHeadlineOptions, searchHeadlineOptions,
+		searchHeadlineOptions, pg.In(filter.Types)}
+
+	if filter.Status != "" {
+		conditions = append(conditions, "status = ?")
+		params = append(params, filter.Status)
+	}
+
+	if !filter.From.IsZero() {
+		conditions = append(conditions, "date_end >= ?")
+		params = append(params, filter.From)
+	}
+
+	if !filter.To.IsZero() {
+		conditions = append(conditions, "date_start <= ?")
+		params = append(params, filter.To)
+	}
+
+	params = append(params, filter.Limit, filter.Offset)
+
+	_, err := tx.Query(&results, searchQuery+strings.Join(conditions, " AND ")+
+		" ORDER BY rank DESC, type, id LIMIT ? OFFSET ?", params...)
+	return results, err
+}
@@ -0,0 +1,142 @@
+package repositories
+
+import (
+	"event_service/internal/models"
+	"event_service/internal/schemas"
+	"fmt"
+	"github.com/go-pg/pg/v10"
+	"time"
+)
+
+// activeSession hides sessions of deleted events and tracks, they come back
+// together with their parent on restore.
+const activeSession = "%[1]s.event_id IN (SELECT id FROM event WHERE deleted_at IS NULL) AND " +
+	"(%[1]s.track_id IS NULL OR %[1]s.track_id IN (SELECT id FROM track WHERE deleted_at IS NULL))"
+
+type SessionRepository struct {
+	DB *pg.DB
+}
+
+func NewSessionRepository(db *pg.DB) *SessionRepository {
+	return &SessionRepository{DB: db}
+}
+
+func (r *SessionRepository) Create(tx *pg.Tx, session *models.Session) (*models.Session, error) {
+	_, err := tx.Model(session).Insert()
+	return session, err
+}
+
+func (r *SessionRepository) GetSessionByID(tx *pg.Tx, sessionID int) (*models.Session, error) {
+	session := new(models.Session)
+	err := tx.Model(session).Relation("Speakers").Where("session.id = ?", sessionID).Select()
+	return session, err
+}
+
+// GetSessions returns active sessions ordered by start time. From and To
+// select sessions overlapping the range rather than contained in it.
+func (r *SessionRepository) GetSessions(tx *pg.Tx, filter *schemas.SessionFilter) ([]*models.Session, error) {
+	sessions := make([]*models.Session, 0)
+	query := tx.Model(&sessions).Relation("Speakers").Where(sessionCondition("session"))
+
+	if filter.EventID != 0 {
+		query.Where("session.event_id = ?", filter.EventID)
+	}
+
+	if filter.TrackID != 0 {
+		query.Where("session.track_id = ?", filter.TrackID)
+	}
+
+	if filter.LocationID != 0 {
+		query.Where("session.location_id = ?", filter.LocationID)
+	}
+
+	if !filter.From.IsZero() {
+		query.Where("session.date_end > ?", filter.From)
+	}
+
+	if !filter.To.IsZero() {
+		query.Where("session.date_start < ?", filter.To)
+	}
+
+	err := query.Order("session.date_start", "session.id").Select()
+	return sessions, err
+}
+
+// GetSessionsByEventID returns every session of the event, including those
+// hidden by a deleted event or track.
+func (r *SessionRepository) GetSessionsByEventID(tx *pg.Tx, eventID int) ([]*models.Session, error) {
+	sessions := make([]*models.Session, 0)
+	err := tx.Model(&sessions).Where("event_id = ?", eventID).Order("date_start", "id").Select()
+	return sessions, err
+}
+
+func (r *SessionRepository) GetSessionsByTrackIDs(tx *pg.Tx, trackIDs []int) ([]*models.Session, error) {
+	sessions := make([]*models.Session, 0)
+	if len(trackIDs) == 0 {
+		return sessions, nil
+	}
+
+	err := tx.Model(&sessions).Where("track_id IN (?)", pg.In(trackIDs)).Order("date_start", "id").Select()
+	return sessions, err
+}
+
+// GetOverlapping returns active sessions of any event booked into the
+// location between start and end, except the session being checked.
+func (r *SessionRepository) GetOverlapping(tx *pg.Tx, locationID int, start time.Time, end time.Time,
+	exceptID int) ([]*models.Session, error) {
+	sessions := make([]*models.Session, 0)
+	err := tx.Model(&sessions).
+		Where(sessionCondition("session")).
+		Where("session.location_id = ?", locationID).
+		Where("session.date_start < ? AND session.date_end > ?", end, start).
+		Where("session.id != ?", exceptID).
+		Order("session.date_start").
+		Select()
+	return sessions, err
+}
+
+// GetConflicts lists every pair of active sessions that double-book a
+// location. With an event id only pairs involving that event are returned.
+func (r *SessionRepository) GetConflicts(tx *pg.Tx, filter *schemas.SessionFilter) ([]*schemas.SessionConflict, error) {
+	conflicts := make([]*schemas.SessionConflict, 0)
+
+	_, err := tx.Query(&conflicts, `
+		SELECT a.location_id,
+		       a.id AS session_id, a.event_id,
+		       b.id AS conflict_session_id, b.event_id AS conflict_event_id,
+		       GREATEST(a.date_start, b.date_start) AS overlap_start,
+		       LEAST(a.date_end, b.date_end) AS overlap_end
+		FROM session a
+		JOIN session b ON b.location_id = a.location_id AND b.id > a.id
+			AND b.date_start < a.date_end AND b.date_end > a.date_start
+		WHERE `+sessionCondition("a")+` AND `+sessionCondition("b")+`
+			AND (?0 = 0 OR a.location_id = ?0)
+			AND (?1 = 0 OR a.event_id = ?1 OR b.event_id = ?1)
+		ORDER BY a.location_id, overlap_start
+	`, filter.LocationID, filter.EventID)
+
+	return conflicts, err
+}
+
+func (r *SessionRepository) UpdateSession(tx *pg.Tx, sessionID int, newSession *models.Session) (*models.Session, error) {
+	session := new(models.Session)
+	_, err := tx.Model(session).
+		Set("title = ?, description = ?, kind = ?, date_start = ?, date_end = ?", newSession.Title,
+			newSession.Description, newSession.Kind, newSession.DateStart, newSession.DateEnd).
+		Set("track_id = NULLIF(?, 0), location_id = NULLIF(?, 0), attendees = NULLIF(?, 0)", newSession.TrackID,
+			newSession.LocationID, newSession.Attendees).
+		Set("updated_at = now(), version = version + 1").
+		Where("id = ?", sessionID).
+		Returning("*").
+		Update()
+	return session, err
+}
+
+func (r *SessionRepository) DeleteSession(tx *pg.Tx, sessionID int) error {
+	_, err := tx.Model((*models.Session)(nil)).Where("id = ?", sessionID).Delete()
+	return err
+}
+
+func sessionCondition(alias string) string {
+	return fmt.Sprintf(activeSession, alias)
+}
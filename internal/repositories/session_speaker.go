@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"event_service/internal/models"
+	"github.com/go-pg/pg/v10"
+)
+
+type SessionSpeakerRepository struct {
+	DB *pg.DB
+}
+
+func NewSessionSpeakerRepository(db *pg.DB) *SessionSpeakerRepository {
+	return &SessionSpeakerRepository{DB: db}
+}
+
+func (r *SessionSpeakerRepository) Create(tx *pg.Tx, sessionSpeaker *models.SessionSpeaker) (*models.SessionSpeaker, error) {
+	_, err := tx.Model(sessionSpeaker).Insert()
+	return sessionSpeaker, err
+}
+
+func (r *SessionSpeakerRepository) DeleteSessionSpeaker(tx *pg.Tx, sessionID int, speakerID int) error {
+	sessionSpeaker := &models.SessionSpeaker{SessionID: sessionID, SpeakerID: speakerID}
+	res, err := tx.Model(sessionSpeaker).WherePK().Delete()
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return pg.ErrNoRows
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package repositories
+
+import (
+	"event_service/internal/models"
+	"github.com/go-pg/pg/v10"
+)
+
+type SpeakerRepository struct {
+	DB *pg.DB
+}
+
+func NewSpeakerRepository(db *pg.DB) *SpeakerRepository {
+	return &SpeakerRepository{DB: db}
+}
+
+func (r *SpeakerRepository) Create(tx *pg.Tx, speaker *models.Speaker) (*models.Speaker, error) {
+	_, err := tx.Model(speaker).Insert()
+	return speaker, err
+}
+
+func (r *SpeakerRepository) GetAllSpeakers(tx *pg.Tx) ([]*models.Speaker, error) {
+	speakers := make([]*models.Speaker, 0)
+	err := tx.Model(&speakers).Order("name").Select()
+	return speakers, err
+}
+
+func (r *SpeakerRepository) GetSpeakerByID(tx *pg.Tx, speakerID int) (*models.Speaker, error) {
+	speaker := new(models.Speaker)
+	err := tx.Model(speaker).Where("id = ?", speakerID).Select()
+	return speaker, err
+}
+
+func (r *SpeakerRepository) UpdateSpeaker(tx *pg.Tx, speakerID int, newSpeaker *models.Speaker) (*models.Speaker, error) {
+	speaker := new(models.Speaker)
+	_, err := tx.Model(speaker).
+		Set("name = ?, bio = ?, company = ?, user_id = NULLIF(?, 0)", newSpeaker.Name, newSpeaker.Bio,
+			newSpeaker.Company, newSpeaker.UserID).
+		Set("updated_at = now()").
+		Where("id = ?", speakerID).
+		Returning("*").
+		Update()
+	return speaker, err
+}
+
+func (r *SpeakerRepository) DeleteSpeaker(tx *pg.Tx, speakerID int) error {
+	_, err := tx.Model((*models.Speaker)(nil)).Where("id = ?", speakerID).Delete()
+	return err
+}
@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"event_service/internal/models"
+	"github.com/go-pg/pg/v10"
+)
+
+type StatusEventRepository struct {
+	DB *pg.DB
+}
+
+func NewStatusEventRepository(db *pg.DB) *StatusEventRepository {
+	return &StatusEventRepository{DB: db}
+}
+
+func (r *StatusEventRepository) Create(tx *pg.Tx, statusEvent *models.StatusEvent) (*models.StatusEvent, error) {
+	_, err := tx.Model(statusEvent).Insert()
+	return statusEvent, err
+}
+
+func (r *StatusEventRepository) GetStatusesByEventID(tx *pg.Tx, eventID int) ([]*models.Status, error) {
+	statuses := make([]*models.Status, 0)
+
+	err := tx.Model(&statuses).
+		Join("JOIN status_event s ON s.status_id = status.id").
+		Where("s.event_id = ?", eventID).
+		Order("status.title").
+		Select()
+
+	return statuses, err
+}
+
+func (r *StatusEventRepository) DeleteStatusEvent(tx *pg.Tx, statusID int, eventID int) error {
+	statusEvent := &models.StatusEvent{StatusID: statusID, EventID: eventID}
+	res, err := tx.Model(statusEvent).WherePK().Delete()
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return pg.ErrNoRows
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"event_service/internal/models"
+	"github.com/go-pg/pg/v10"
+)
+
+type StatusTrackRepository struct {
+	DB *pg.DB
+}
+
+func NewStatusTrackRepository(db *pg.DB) *StatusTrackRepository {
+	return &StatusTrackRepository{DB: db}
+}
+
+func (r *StatusTrackRepository) Create(tx *pg.Tx, statusTrack *models.StatusTrack) (*models.StatusTrack, error) {
+	_, err := tx.Model(statusTrack).Insert()
+	return statusTrack, err
+}
+
+func (r *StatusTrackRepository) GetStatusesByTrackID(tx *pg.Tx, trackID int) ([]*models.Status, error) {
+	statuses := make([]*models.Status, 0)
+
+	err := tx.Model(&statuses).
+		Join("JOIN status_track s ON s.status_id = status.id").
+		Where("s.track_id = ?", trackID).
+		Order("status.title").
+		Select()
+
+	return statuses, err
+}
+
+func (r *StatusTrackRepository) DeleteStatusTrack(tx *pg.Tx, statusID int, trackID int) error {
+	statusTrack := &models.StatusTrack{StatusID: statusID, TrackID: trackID}
+	res, err := tx.Model(statusTrack).WherePK().Delete()
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return pg.ErrNoRows
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"event_service/internal/models"
+	"github.com/go-pg/pg/v10"
+	"time"
+)
+
+type SubmissionFileRepository struct {
+	DB *pg.DB
+}
+
+func NewSubmissionFileRepository(db *pg.DB) *SubmissionFileRepository {
+	return &SubmissionFileRepository{DB: db}
+}
+
+func (r *SubmissionFileRepository) Create(tx *pg.Tx, file *models.SubmissionFile) (*models.SubmissionFile, error) {
+	_, err := tx.Model(file).Returning("*").Insert()
+	return file, err
+}
+
+func (r *SubmissionFileRepository) GetFileByID(tx *pg.Tx, fileID int) (*models.SubmissionFile, error) {
+	file := new(models.SubmissionFile)
+	err := tx.Model(file).Where("id = ?", fileID).Select()
+	return file, err
+}
+
+func (r *SubmissionFileRepository) GetFile(tx *pg.Tx, teamID, timelineID, fileID int) (*models.SubmissionFile, error) {
+	file := new(models.SubmissionFile)
+	err := tx.Model(file).Where("id = ?", fileID).Where("track_team_id = ?", teamID).
+		Where("timeline_id = ?", timelineID).Select()
+	return file, err
+}
+
+func (r *SubmissionFileRepository) GetFiles(tx *pg.Tx, teamID, timelineID int) ([]*models.SubmissionFile, error) {
+	files := make([]*models.SubmissionFile, 0)
+	err := tx.Model(&files).Where("track_team_id = ?", teamID).Where("timeline_id = ?", timelineID).
+		Order("created_at", "id").Select()
+	return files, err
+}
+
+// GetPurgedStorageKeys returns the blobs of files that go together with
+// timelines and tracks deleted before the given time once they are purged.
+func (r *SubmissionFileRepository) GetPurgedStorageKeys(tx *pg.Tx, before time.Time) ([]string, error) {
+	keys := make([]string, 0)
+	_, err := tx.Query(&keys, `
+		SELECT storage_key
+		FROM submission_file
+		WHERE timeline_id IN (SELECT id FROM timeline WHERE deleted_at < ?0)
+		   OR track_team_id IN (SELECT track_team.id
+		                        FROM track_team
+		                        JOIN track ON track.id = track_team.track_id
+		                        WHERE track.deleted_at < ?0)
+	`, before)
+	return keys, err
+}
+
+func (r *SubmissionFileRepository) DeleteFile(tx *pg.Tx, fileID int) error {
+	_, err := tx.Model((*models.SubmissionFile)(nil)).Where("id = ?", fileID).Delete()
+	return err
+}
@@ -13,6 +13,18 @@ type TeamActionStatusRepository struct {
 type AggregateResult struct {
 	TeamId     int
 	TotalValue int
+	Breakdown  []*ResultBreakdown
+}
+
+// ResultBreakdown is the share of one timeline in a team's total, with the
+// late penalty already taken off Value.
+type ResultBreakdown struct {
+	TimelineId     int
+	ResultValue    int
+	IsLate         bool
+	LateMinutes    int
+	PenaltyPercent int
+	Value          int
 }
 
 func NewTeamActionStatusRepository(db *pg.DB) *TeamActionStatusRepository {
@@ -45,7 +57,9 @@ func (r *TeamActionStatusRepository) GetTeamActionStatusByTeamIDAndTimelineID(tx
 func (r *TeamActionStatusRepository) UpdateTeamActionStatus(tx *pg.Tx, teamID int, timelineID int, newTeamActionStatus *models.TeamActionStatus) (*models.TeamActionStatus, error) {
 	teamActionStatus := new(models.TeamActionStatus)
 	_, err := tx.Model(teamActionStatus).Set("result_value = ?, resolution_link = ?, completed_at = ?, notes = ?", newTeamActionStatus.ResultValue,
-		newTeamActionStatus.ResolutionLink, newTeamActionStatus.CompletedAt, newTeamActionStatus.Notes).Where("timeline_id = ? AND track_team_id = ?", timelineID, teamID).Returning("*").Update()
+		newTeamActionStatus.ResolutionLink, pg.NullTime{Time: newTeamActionStatus.CompletedAt}, newTeamActionStatus.Notes).
+		Set("submitted_at = ?, is_late = ?, late_minutes = ?, penalty_percent = ?", newTeamActionStatus.SubmittedAt,
+			newTeamActionStatus.IsLate, newTeamActionStatus.LateMinutes, newTeamActionStatus.PenaltyPercent).Where("timeline_id = ? AND track_team_id = ?", timelineID, teamID).Returning("*").Update()
 	return teamActionStatus, err
 }
 
@@ -55,13 +69,49 @@ func (r *TeamActionStatusRepository) DeleteTeamActionStatus(tx *pg.Tx, teamID in
 	return err
 }
 
+// countedResultValue is the result of an action as rankings count it, for
+// queries that select the action as tas and its timeline as t. Each timeline
+// decides which revision of a result counts. Revisions without a score are
+// skipped, so are revisions from before the action was last deleted, and
+// actions that predate revisions fall back to the stored value.
+const countedResultValue = `CASE t.counted_revision
+                WHEN 'first' THEN COALESCE((
+                    SELECT r.result_value
+                    FROM team_action_status_revision r
+                    WHERE r.track_team_id = tas.track_team_id AND r.timeline_id = tas.timeline_id
+                      AND r.revision > ` + lastDeletedRevision + ` AND r.result_value IS NOT NULL
+                    ORDER BY r.revision
+                    LIMIT 1
+                ), tas.result_value)
+                WHEN 'best' THEN COALESCE((
+                    SELECT MAX(r.result_value)
+                    FROM team_action_status_revision r
+                    WHERE r.track_team_id = tas.track_team_id AND r.timeline_id = tas.timeline_id
+                      AND r.revision > ` + lastDeletedRevision + `
+                ), tas.result_value)
+                ELSE tas.result_value
+            END`
+
+const lastDeletedRevision = `(
+                        SELECT COALESCE(MAX(d.revision), 0)
+                        FROM team_action_status_revision d
+                        WHERE d.track_team_id = tas.track_team_id AND d.timeline_id = tas.timeline_id AND d.deleted
+                    )`
+
 func (r *TeamActionStatusRepository) AggregateResults(tx *pg.Tx, trackId int, limit int, offset int) ([]*AggregateResult, error) {
-	var results []*AggregateResult
+	var rows []*struct {
+		TeamId int
+		ResultBreakdown
+	}
 
 	query := `
         SELECT 
             tas.track_team_id AS team_id,
-            SUM(tas.result_value) AS total_value
+            tas.timeline_id,
+            ` + countedResultValue + ` AS result_value,
+            tas.is_late,
+            tas.late_minutes,
+            tas.penalty_percent
         FROM 
             team_action_status tas
         JOIN 
@@ -69,20 +119,43 @@ func (r *TeamActionStatusRepository) AggregateResults(tx *pg.Tx, trackId int, li
         ON
         	t.id = tas.timeline_id
     	WHERE
-            t.track_id = ?
-        GROUP BY 
-            tas.track_team_id
-        LIMIT ? OFFSET ?
+            t.track_id = ? AND t.deleted_at IS NULL
+        ORDER BY 
+            tas.track_team_id, t.deadline, t.id
     `
 
-	_, err := tx.Query(&results, query, trackId, limit, offset)
+	_, err := tx.Query(&rows, query, trackId)
 	if err != nil {
 		return nil, err
 	}
 
-	sort.Slice(results, func(i, j int) bool {
+	results := make([]*AggregateResult, 0)
+	byTeam := make(map[int]*AggregateResult)
+
+	for _, row := range rows {
+		result, ok := byTeam[row.TeamId]
+		if !ok {
+			result = &AggregateResult{TeamId: row.TeamId, Breakdown: make([]*ResultBreakdown, 0)}
+			byTeam[row.TeamId] = result
+			results = append(results, result)
+		}
+
+		breakdown := row.ResultBreakdown
+		breakdown.Value = breakdown.ResultValue * (100 - breakdown.PenaltyPercent) / 100
+
+		result.TotalValue += breakdown.Value
+		result.Breakdown = append(result.Breakdown, &breakdown)
+	}
+
+	// Paging happens after sorting so that pages follow the ranking.
+	sort.SliceStable(results, func(i, j int) bool {
 		return results[i].TotalValue > results[j].TotalValue
 	})
 
-	return results, nil
+	offset = max(offset, 0)
+	if offset >= len(results) {
+		return make([]*AggregateResult, 0), nil
+	}
+
+	return results[offset:min(len(results), offset+max(limit, 0))], nil
 }
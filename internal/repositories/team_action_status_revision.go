@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"event_service/internal/models"
+	"github.com/go-pg/pg/v10"
+)
+
+type TeamActionStatusRevisionRepository struct {
+	DB *pg.DB
+}
+
+func NewTeamActionStatusRevisionRepository(db *pg.DB) *TeamActionStatusRevisionRepository {
+	return &TeamActionStatusRevisionRepository{DB: db}
+}
+
+// Create numbers the revision after the latest one of the same action. The
+// caller holds the team_action_status row, so numbers cannot race.
+func (r *TeamActionStatusRevisionRepository) Create(tx *pg.Tx, revision *models.TeamActionStatusRevision) (*models.TeamActionStatusRevision, error) {
+	query := `
+        SELECT COALESCE(MAX(revision), 0) + 1
+        FROM team_action_status_revision
+        WHERE track_team_id = ? AND timeline_id = ?
+    `
+
+	if _, err := tx.QueryOne(pg.Scan(&revision.Revision), query, revision.TrackTeamID, revision.TimelineID); err != nil {
+		return nil, err
+	}
+
+	_, err := tx.Model(revision).Returning("*").Insert()
+	return revision, err
+}
+
+func (r *TeamActionStatusRevisionRepository) GetRevisions(tx *pg.Tx, teamID, timelineID int) ([]*models.TeamActionStatusRevision, error) {
+	revisions := make([]*models.TeamActionStatusRevision, 0)
+	err := tx.Model(&revisions).Where("track_team_id = ?", teamID).Where("timeline_id = ?", timelineID).
+		Order("revision").Select()
+	return revisions, err
+}
+
+func (r *TeamActionStatusRevisionRepository) GetRevision(tx *pg.Tx, teamID, timelineID, revision int) (*models.TeamActionStatusRevision, error) {
+	model := new(models.TeamActionStatusRevision)
+	err := tx.Model(model).Where("track_team_id = ?", teamID).Where("timeline_id = ?", timelineID).
+		Where("revision = ?", revision).Select()
+	return model, err
+}
@@ -3,6 +3,7 @@ package repositories
 import (
 	"event_service/internal/models"
 	"github.com/go-pg/pg/v10"
+	"time"
 )
 
 type TimelineRepository struct {
@@ -30,6 +31,51 @@ func (r *TimelineRepository) GetTimelinesByTrackID(tx *pg.Tx, trackID int) ([]*m
 	return timelines, err
 }
 
+func (r *TimelineRepository) GetTimelinesByTrackIDs(tx *pg.Tx, trackIDs []int) ([]*models.Timeline, error) {
+	timelines := make([]*models.Timeline, 0)
+	if len(trackIDs) == 0 {
+		return timelines, nil
+	}
+
+	err := tx.Model(&timelines).
+		Where("timeline.track_id IN (?)", pg.In(trackIDs)).
+		Where("timeline.deadline IS NOT NULL").
+		Order("timeline.deadline").
+		Select()
+
+	return timelines, err
+}
+
+// GetTimelinesByTrackIDsIncludingDeleted also returns deleted timelines, for
+// calendar feeds that show them as cancelled.
+func (r *TimelineRepository) GetTimelinesByTrackIDsIncludingDeleted(tx *pg.Tx, trackIDs []int) ([]*models.Timeline, error) {
+	timelines := make([]*models.Timeline, 0)
+	if len(trackIDs) == 0 {
+		return timelines, nil
+	}
+
+	err := tx.Model(&timelines).
+		AllWithDeleted().
+		Where("timeline.track_id IN (?)", pg.In(trackIDs)).
+		Where("timeline.deadline IS NOT NULL").
+		Order("timeline.deadline").
+		Select()
+
+	return timelines, err
+}
+
+// GetAllTimelinesToExpire skips timelines that some team may still submit to
+// because of a later per-team deadline.
+func (r *TimelineRepository) GetAllTimelinesToExpire(tx *pg.Tx) ([]*models.Timeline, error) {
+	timelines := make([]*models.Timeline, 0)
+	err := tx.Model(&timelines).
+		Where("timeline.status = 'ready' AND timeline.deadline <= NOW()").
+		Where("NOT EXISTS (SELECT 1 FROM timeline_override o WHERE o.timeline_id = timeline.id AND o.deadline > NOW())").
+		Select()
+
+	return timelines, err
+}
+
 func (r *TimelineRepository) GetMaxNumOfTimeline(tx *pg.Tx, trackID int) (int, error) {
 	maxCountNum := 0
 
@@ -60,23 +106,82 @@ func (r *TimelineRepository) GetTimelineByID(tx *pg.Tx, timelineID int) (*models
 	return timeline, err
 }
 
-func (r *TimelineRepository) UpdateTimeline(tx *pg.Tx, timelineId int, newTimeline *models.Timeline) (*models.Timeline, error) {
+func (r *TimelineRepository) UpdateTimeline(tx *pg.Tx, timelineId int, newTimeline *models.Timeline, version int) (*models.Timeline, error) {
+	timeline := new(models.Timeline)
+	query := tx.Model(timeline).Set("title = ?, description = ?, deadline = ?, is_blocking = ?, status = ?, track_id = ?, timeline_status_id = ?, counted_revision = ?",
+		newTimeline.Title, newTimeline.Description, pg.NullTime{Time: newTimeline.Deadline}, newTimeline.IsBlocking, newTimeline.Status,
+		newTimeline.TrackID, newTimeline.TimelineStatusID, newTimeline.CountedRevision).
+		Set("late_policy = ?, late_grace_minutes = ?, late_penalty = ?, late_step_minutes = NULLIF(?, 0)",
+			newTimeline.LatePolicy, newTimeline.LateGraceMinutes, newTimeline.LatePenalty, newTimeline.LateStepMinutes).
+		Set("version = version + 1").Where("id = ?", timelineId)
+
+	if version > 0 {
+		query.Where("version = ?", version)
+	}
+
+	_, err := query.Returning("*").Update()
+	return timeline, err
+}
+
+func (r *TimelineRepository) DeleteTimeline(tx *pg.Tx, timelineID int, deletedAt time.Time) error {
+	_, err := tx.Model((*models.Timeline)(nil)).Set("deleted_at = ?, version = version + 1", deletedAt).Where("id = ?", timelineID).Update()
+	return err
+}
+
+func (r *TimelineRepository) DeleteTimelinesByTrackIDs(tx *pg.Tx, trackIDs []int, deletedAt time.Time) error {
+	if len(trackIDs) == 0 {
+		return nil
+	}
+
+	_, err := tx.Model((*models.Timeline)(nil)).Set("deleted_at = ?, version = version + 1", deletedAt).Where("track_id IN (?)", pg.In(trackIDs)).Update()
+	return err
+}
+
+func (r *TimelineRepository) GetDeletedTimelines(tx *pg.Tx) ([]*models.Timeline, error) {
+	timelines := make([]*models.Timeline, 0)
+	err := tx.Model(&timelines).Deleted().Order("deleted_at DESC").Select()
+	return timelines, err
+}
+
+func (r *TimelineRepository) RestoreTimeline(tx *pg.Tx, timelineID int) (*models.Timeline, error) {
 	timeline := new(models.Timeline)
-	_, err := tx.Model(timeline).Set("title = ?, description = ?, deadline = ?, is_blocking = ?, timeline_status_id", newTimeline.Title,
-		newTimeline.Description, newTimeline.Deadline, newTimeline.IsBlocking).Where("id = ?", timelineId).Returning("*").Update()
+	_, err := tx.Model(timeline).Deleted().Set("deleted_at = NULL, version = version + 1").Where("id = ?", timelineID).Returning("*").Update()
 	return timeline, err
 }
 
-func (r *TimelineRepository) DeleteTimeline(tx *pg.Tx, timelineID int) error {
-	_, err := tx.Model(&models.Timeline{}).Where("id = ?", timelineID).Delete()
+func (r *TimelineRepository) RestoreTimelinesByTrackIDs(tx *pg.Tx, trackIDs []int, deletedAt time.Time) error {
+	if len(trackIDs) == 0 {
+		return nil
+	}
+
+	_, err := tx.Model((*models.Timeline)(nil)).Deleted().Set("deleted_at = NULL, version = version + 1").Where("track_id IN (?) AND deleted_at = ?", pg.In(trackIDs), deletedAt).Update()
 	return err
 }
 
+func (r *TimelineRepository) PurgeDeletedBefore(tx *pg.Tx, before time.Time) (int, error) {
+	deleted := "SELECT id FROM timeline WHERE deleted_at < ?"
+
+	if _, err := tx.Exec("DELETE FROM team_action_status_revision WHERE timeline_id IN ("+deleted+")", before); err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.Exec("DELETE FROM team_action_status WHERE timeline_id IN ("+deleted+")", before); err != nil {
+		return 0, err
+	}
+
+	res, err := tx.Model((*models.Timeline)(nil)).Deleted().Where("deleted_at < ?", before).ForceDelete()
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected(), nil
+}
+
 func (r *TimelineRepository) GetMaxValue(tx *pg.Tx, trackId int) (int, error) {
 	query := `
         SELECT SUM(CASE WHEN is_scoring THEN 100 ELSE 0 END) AS total_value
         FROM timeline
-        WHERE track_id = ?
+        WHERE track_id = ? AND deleted_at IS NULL
     `
 
 	var result int
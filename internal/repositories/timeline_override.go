@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"event_service/internal/models"
+	"github.com/go-pg/pg/v10"
+	"time"
+)
+
+type TimelineOverrideRepository struct {
+	DB *pg.DB
+}
+
+func NewTimelineOverrideRepository(db *pg.DB) *TimelineOverrideRepository {
+	return &TimelineOverrideRepository{DB: db}
+}
+
+func (r *TimelineOverrideRepository) GetOverride(tx *pg.Tx, timelineID, trackTeamID int) (*models.TimelineOverride, error) {
+	override := new(models.TimelineOverride)
+	err := tx.Model(override).Where("timeline_id = ?", timelineID).Where("track_team_id = ?", trackTeamID).Select()
+	return override, err
+}
+
+func (r *TimelineOverrideRepository) GetOverridesByTimelineID(tx *pg.Tx, timelineID int) ([]*models.TimelineOverride, error) {
+	overrides := make([]*models.TimelineOverride, 0)
+	err := tx.Model(&overrides).Where("timeline_id = ?", timelineID).Order("track_team_id").Select()
+	return overrides, err
+}
+
+func (r *TimelineOverrideRepository) GetOverridesByTrackTeamIDs(tx *pg.Tx, trackTeamIDs []int) ([]*models.TimelineOverride, error) {
+	overrides := make([]*models.TimelineOverride, 0)
+	if len(trackTeamIDs) == 0 {
+		return overrides, nil
+	}
+
+	err := tx.Model(&overrides).Where("track_team_id IN (?)", pg.In(trackTeamIDs)).Select()
+	return overrides, err
+}
+
+// GetDeadlineOverridesByTimelineIDs skips overrides that only change the
+// blocking flag.
+func (r *TimelineOverrideRepository) GetDeadlineOverridesByTimelineIDs(tx *pg.Tx, timelineIDs []int) ([]*models.TimelineOverride, error) {
+	overrides := make([]*models.TimelineOverride, 0)
+	if len(timelineIDs) == 0 {
+		return overrides, nil
+	}
+
+	err := tx.Model(&overrides).Where("timeline_id IN (?)", pg.In(timelineIDs)).Where("deadline IS NOT NULL").
+		Order("timeline_id", "track_team_id").Select()
+	return overrides, err
+}
+
+func (r *TimelineOverrideRepository) Upsert(tx *pg.Tx, override *models.TimelineOverride) (*models.TimelineOverride, error) {
+	_, err := tx.Model(override).
+		OnConflict("(timeline_id, track_team_id) DO UPDATE").
+		Set("deadline = EXCLUDED.deadline, is_blocking = EXCLUDED.is_blocking").
+		Set("reason = EXCLUDED.reason, author_id = EXCLUDED.author_id, updated_at = now()").
+		Returning("*").
+		Insert()
+	return override, err
+}
+
+func (r *TimelineOverrideRepository) UpdateDeadline(tx *pg.Tx, id int, deadline time.Time) (*models.TimelineOverride, error) {
+	override := new(models.TimelineOverride)
+	_, err := tx.Model(override).Set("deadline = ?, updated_at = now()", deadline).Where("id = ?", id).Returning("*").Update()
+	return override, err
+}
+
+func (r *TimelineOverrideRepository) DeleteOverride(tx *pg.Tx, timelineID, trackTeamID int) error {
+	res, err := tx.Model((*models.TimelineOverride)(nil)).Where("timeline_id = ?", timelineID).
+		Where("track_team_id = ?", trackTeamID).Delete()
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return pg.ErrNoRows
+	}
+
+	return nil
+}
@@ -2,7 +2,9 @@ package repositories
 
 import (
 	"event_service/internal/models"
+	"event_service/internal/schemas"
 	"github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
 	"time"
 )
 
@@ -19,9 +21,23 @@ func (r *TrackRepository) Create(tx *pg.Tx, track *models.Track) (*models.Track,
 	return track, err
 }
 
-func (r *TrackRepository) GetAllTracks(tx *pg.Tx) ([]*models.Track, error) {
+func (r *TrackRepository) GetAllTracks(tx *pg.Tx, filter *schemas.TrackFilter) ([]*models.Track, error) {
 	tracks := make([]*models.Track, 0)
-	err := tx.Model(&tracks).Select()
+	query := tx.Model(&tracks)
+
+	if filter.EventID != 0 {
+		query.Where("track.event_id = ?", filter.EventID)
+	}
+
+	if filter.Status != "" {
+		query.Where("track.status = ?", filter.Status)
+	}
+
+	if filter.StatusID != 0 {
+		query.Where("EXISTS (SELECT 1 FROM status_track st WHERE st.track_id = track.id AND st.status_id = ?)", filter.StatusID)
+	}
+
+	err := query.Select()
 	return tracks, err
 }
 
@@ -43,25 +59,179 @@ func (r *TrackRepository) GetTrackByID(tx *pg.Tx, trackID int) (*models.Track, e
 	return track, err
 }
 
+// LockTrackByID selects the track for update, which serializes team
+// registrations of one track while its slots are counted.
+func (r *TrackRepository) LockTrackByID(tx *pg.Tx, trackID int) (*models.Track, error) {
+	track := new(models.Track)
+	err := tx.Model(track).Where("id = ?", trackID).For("UPDATE").Select()
+	return track, err
+}
+
+func (r *TrackRepository) GetTracksWithDateByEventID(tx *pg.Tx, eventID int) ([]*models.Track, error) {
+	tracks := make([]*models.Track, 0)
+	err := tx.Model(&tracks).Relation("Date").Where("track.event_id = ?", eventID).Order("track.id").Select()
+	return tracks, err
+}
+
+func (r *TrackRepository) GetTracksWithDateByIDs(tx *pg.Tx, trackIDs []int) ([]*models.Track, error) {
+	tracks := make([]*models.Track, 0)
+	if len(trackIDs) == 0 {
+		return tracks, nil
+	}
+
+	err := tx.Model(&tracks).Relation("Date").Relation("Event").Where("track.id IN (?)", pg.In(trackIDs)).Order("track.id").Select()
+	return tracks, err
+}
+
+// GetTracksWithDateByEventIDIncludingDeleted and
+// GetTracksWithDateByIDsIncludingDeleted also return deleted tracks, for
+// calendar feeds that show them as cancelled.
+func (r *TrackRepository) GetTracksWithDateByEventIDIncludingDeleted(tx *pg.Tx, eventID int) ([]*models.Track, error) {
+	tracks := make([]*models.Track, 0)
+	err := tx.Model(&tracks).AllWithDeleted().Relation("Date").Where("track.event_id = ?", eventID).Order("track.id").Select()
+	return tracks, err
+}
+
+func (r *TrackRepository) GetTracksWithDateByIDsIncludingDeleted(tx *pg.Tx, trackIDs []int) ([]*models.Track, error) {
+	tracks := make([]*models.Track, 0)
+	if len(trackIDs) == 0 {
+		return tracks, nil
+	}
+
+	err := tx.Model(&tracks).AllWithDeleted().Relation("Date").Relation("Event").Where("track.id IN (?)", pg.In(trackIDs)).Order("track.id").Select()
+	return tracks, err
+}
+
+func (r *TrackRepository) GetTrackIDsByDateID(tx *pg.Tx, dateID int) ([]int, error) {
+	trackIDs := make([]int, 0)
+	err := tx.Model((*models.Track)(nil)).
+		Column("id").
+		WhereGroup(func(q *orm.Query) (*orm.Query, error) {
+			return q.Where("date_id = ?", dateID).
+				WhereOr("event_id IN (SELECT id FROM event WHERE date_id = ? AND deleted_at IS NULL)", dateID), nil
+		}).
+		Order("id").
+		Select(&trackIDs)
+	return trackIDs, err
+}
+
+// GetTimeZones returns the time zone of the event of every track, including
+// deleted ones.
+func (r *TrackRepository) GetTimeZones(tx *pg.Tx, trackIDs []int) (map[int]string, error) {
+	zones := make(map[int]string, len(trackIDs))
+	if len(trackIDs) == 0 {
+		return zones, nil
+	}
+
+	var rows []struct {
+		ID       int
+		TimeZone string
+	}
+
+	_, err := tx.Query(&rows, `
+		SELECT track.id, event.time_zone
+		FROM track
+		JOIN event ON event.id = track.event_id
+		WHERE track.id IN (?)
+	`, pg.In(trackIDs))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		zones[row.ID] = row.TimeZone
+	}
+
+	return zones, nil
+}
+
 func (r *TrackRepository) GetTracksWithAllRelations(tx *pg.Tx) ([]*models.Track, error) {
 	tracks := make([]*models.Track, 0)
 	err := tx.Model(&tracks).Relation("Event").Relation("Date").Relation("TrackTeams").Relation("Participants").Relation("Timelines").Relation("TrackJudges").Relation("TrackWinners").Relation("Statuses").Select()
 	return tracks, err
 }
 
-func (r *TrackRepository) UpdateTrack(tx *pg.Tx, trackId int, newTrack *models.Track) (*models.Track, error) {
+func (r *TrackRepository) UpdateTrack(tx *pg.Tx, trackId int, newTrack *models.Track, version int) (*models.Track, error) {
 	track := new(models.Track)
-	_, err := tx.Model(track).Set("title = ?, description = ?, event_id = ?, is_score_based = ?, date_id = ?",
-		newTrack.Title, newTrack.Description, newTrack.EventID, newTrack.IsScoreBased, newTrack.DateID).Where("id = ?", trackId).Returning("*").Update()
+	query := tx.Model(track).Set("title = ?, description = ?, event_id = ?, is_score_based = ?, date_id = ?, status = ?",
+		newTrack.Title, newTrack.Description, newTrack.EventID, newTrack.IsScoreBased, newTrack.DateID, newTrack.Status).
+		Set("registration_opens_at = ?, registration_closes_at = ?, max_teams = NULLIF(?, 0)",
+			pg.NullTime{Time: newTrack.RegistrationOpensAt}, pg.NullTime{Time: newTrack.RegistrationClosesAt}, newTrack.MaxTeams).
+		Set("version = version + 1").Where("id = ?", trackId)
+
+	if version > 0 {
+		query.Where("version = ?", version)
+	}
+
+	_, err := query.Returning("*").Update()
 	return track, err
 }
 
-func (r *TrackRepository) DeleteTrack(tx *pg.Tx, trackID int) error {
-	track := new(models.Track)
-	_, err := tx.Model(track).Where("id = ?", trackID).Delete()
+func (r *TrackRepository) DeleteTrack(tx *pg.Tx, trackID int, deletedAt time.Time) error {
+	_, err := tx.Model((*models.Track)(nil)).Set("deleted_at = ?, version = version + 1", deletedAt).Where("id = ?", trackID).Update()
 	return err
 }
 
+func (r *TrackRepository) DeleteTracksByEventID(tx *pg.Tx, eventID int, deletedAt time.Time) ([]int, error) {
+	trackIDs := make([]int, 0)
+	_, err := tx.Model((*models.Track)(nil)).Set("deleted_at = ?, version = version + 1", deletedAt).Where("event_id = ?", eventID).Returning("id").Update(&trackIDs)
+	return trackIDs, err
+}
+
+func (r *TrackRepository) GetDeletedTracks(tx *pg.Tx) ([]*models.Track, error) {
+	tracks := make([]*models.Track, 0)
+	err := tx.Model(&tracks).Deleted().Order("deleted_at DESC").Select()
+	return tracks, err
+}
+
+func (r *TrackRepository) GetDeletedTrackByID(tx *pg.Tx, trackID int) (*models.Track, error) {
+	track := new(models.Track)
+	err := tx.Model(track).Deleted().Where("id = ?", trackID).Select()
+	return track, err
+}
+
+func (r *TrackRepository) RestoreTrack(tx *pg.Tx, trackID int) (*models.Track, error) {
+	track := new(models.Track)
+	_, err := tx.Model(track).Deleted().Set("deleted_at = NULL, version = version + 1").Where("id = ?", trackID).Returning("*").Update()
+	return track, err
+}
+
+func (r *TrackRepository) RestoreTracksByEventID(tx *pg.Tx, eventID int, deletedAt time.Time) ([]int, error) {
+	trackIDs := make([]int, 0)
+	_, err := tx.Model((*models.Track)(nil)).Deleted().Set("deleted_at = NULL, version = version + 1").Where("event_id = ? AND deleted_at = ?", eventID, deletedAt).Returning("id").Update(&trackIDs)
+	return trackIDs, err
+}
+
+func (r *TrackRepository) PurgeDeletedBefore(tx *pg.Tx, before time.Time) (int, error) {
+	deleted := "SELECT id FROM track WHERE deleted_at < ?"
+	deletedTeams := "SELECT id FROM track_team WHERE track_id IN (" + deleted + ")"
+
+	queries := []string{
+		"DELETE FROM team_action_status_revision WHERE track_team_id IN (" + deletedTeams + ")",
+		"DELETE FROM team_action_status WHERE track_team_id IN (" + deletedTeams + ")",
+		"DELETE FROM track_winner WHERE track_id IN (" + deleted + ")",
+		"DELETE FROM track_team WHERE track_id IN (" + deleted + ")",
+		"DELETE FROM track_judge WHERE track_id IN (" + deleted + ")",
+		"DELETE FROM track_role WHERE track_id IN (" + deleted + ")",
+		"DELETE FROM location_track WHERE track_id IN (" + deleted + ")",
+		"DELETE FROM session WHERE track_id IN (" + deleted + ")",
+		"DELETE FROM timeline WHERE track_id IN (" + deleted + ")",
+	}
+
+	for _, query := range queries {
+		if _, err := tx.Exec(query, before); err != nil {
+			return 0, err
+		}
+	}
+
+	res, err := tx.Model((*models.Track)(nil)).Deleted().Where("deleted_at < ?", before).ForceDelete()
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected(), nil
+}
+
 func (r *TrackRepository) GetAllTracksToStart(tx *pg.Tx) ([]*models.Track, error) {
 	tracks := make([]*models.Track, 0)
 	err := tx.Model(&tracks).Relation("Date").Where("date.date_start <= NOW() AND status = 'planned'").Select()
@@ -0,0 +1,31 @@
+package repositories
+
+import (
+	"event_service/internal/models"
+	"github.com/go-pg/pg/v10"
+)
+
+type TrackEligibilityRepository struct {
+	DB *pg.DB
+}
+
+func NewTrackEligibilityRepository(db *pg.DB) *TrackEligibilityRepository {
+	return &TrackEligibilityRepository{DB: db}
+}
+
+func (r *TrackEligibilityRepository) GetByTrackID(tx *pg.Tx, trackID int) (*models.TrackEligibility, error) {
+	eligibility := new(models.TrackEligibility)
+	err := tx.Model(eligibility).Where("track_id = ?", trackID).Select()
+	return eligibility, err
+}
+
+func (r *TrackEligibilityRepository) Upsert(tx *pg.Tx, eligibility *models.TrackEligibility) (*models.TrackEligibility, error) {
+	_, err := tx.Model(eligibility).
+		OnConflict("(track_id) DO UPDATE").
+		Set("min_team_size = EXCLUDED.min_team_size, max_team_size = EXCLUDED.max_team_size").
+		Set("students_only = EXCLUDED.students_only, one_per_event = EXCLUDED.one_per_event").
+		Set("no_overlap = EXCLUDED.no_overlap, updated_at = now()").
+		Returning("*").
+		Insert()
+	return eligibility, err
+}
@@ -18,6 +18,12 @@ func (r *TrackRoleRepository) Create(tx *pg.Tx, trackRole *models.TrackRole) err
 	return err
 }
 
+func (r *TrackRoleRepository) GetRole(tx *pg.Tx, trackID, userID int) (*models.TrackRole, error) {
+	trackRole := new(models.TrackRole)
+	err := tx.Model(trackRole).Where("track_id = ?", trackID).Where("user_id = ?", userID).Select()
+	return trackRole, err
+}
+
 func (r *TrackTeamRepository) GetUsersByTrackID(tx *pg.Tx, trackID int) ([]models.TrackTeam, error) {
 	var trackTeams []models.TrackTeam
 	err := tx.Model(&trackTeams).Where("track_id = ?", trackID).Select()
@@ -2,9 +2,38 @@ package repositories
 
 import (
 	"event_service/internal/models"
+	"event_service/internal/schemas"
 	"github.com/go-pg/pg/v10"
+	"time"
 )
 
+// teamLockSpace keeps advisory locks on teams apart from any other advisory
+// lock keyed by an id.
+const teamLockSpace = 1
+
+// ProgressRow is one team at one timeline of a track. Overridden values are
+// zero when the team has no override.
+type ProgressRow struct {
+	TrackTeamID      int
+	TeamID           int
+	State            string
+	IsActive         bool
+	TimelineID       int
+	Title            string
+	CountNum         int
+	TimelineStatus   string
+	Deadline         time.Time
+	IsBlocking       bool
+	OverrideDeadline time.Time
+	OverrideBlocking *bool
+	IsSubmitted      bool
+	IsScored         bool
+	CountedValue     int
+	SubmittedAt      time.Time
+	IsLate           bool
+	PenaltyPercent   int
+}
+
 type TrackTeamRepository struct {
 	DB *pg.DB
 }
@@ -14,13 +43,19 @@ func NewTrackTeamRepository(db *pg.DB) *TrackTeamRepository {
 }
 
 func (r *TrackTeamRepository) Create(tx *pg.Tx, trackTeam *models.TrackTeam) (*models.TrackTeam, error) {
-	_, err := tx.Model(trackTeam).Insert()
+	_, err := tx.Model(trackTeam).Returning("*").Insert()
 	return trackTeam, err
 }
 
-func (r *TrackTeamRepository) GetTeamsByTrackID(tx *pg.Tx, trackID int) ([]*models.TrackTeam, error) {
+func (r *TrackTeamRepository) GetTeamsByTrackID(tx *pg.Tx, trackID int, state string) ([]*models.TrackTeam, error) {
 	trackTeams := make([]*models.TrackTeam, 0)
-	err := tx.Model(&trackTeams).Where("track_id = ?", trackID).Select()
+	query := tx.Model(&trackTeams).Where("track_id = ?", trackID)
+
+	if state != "" {
+		query.Where("state = ?", state)
+	}
+
+	err := query.Order("created_at", "id").Select()
 	return trackTeams, err
 }
 
@@ -30,14 +65,111 @@ func (r *TrackTeamRepository) GetTracksByTeamID(tx *pg.Tx, teamID int) ([]*model
 	return trackTeams, err
 }
 
+func (r *TrackTeamRepository) GetTrackTeamByID(tx *pg.Tx, id int) (*models.TrackTeam, error) {
+	trackTeam := new(models.TrackTeam)
+	err := tx.Model(trackTeam).Where("id = ?", id).Select()
+	return trackTeam, err
+}
+
 func (r *TrackTeamRepository) GetByTrackIDAndTeamID(tx *pg.Tx, trackID, teamID int) (*models.TrackTeam, error) {
 	trackTeam := new(models.TrackTeam)
 	err := tx.Model(trackTeam).Where("track_id = ?", trackID).Where("team_id = ?", teamID).Select()
 	return trackTeam, err
 }
 
+func (r *TrackTeamRepository) GetByTrackIDAndTeamIDs(tx *pg.Tx, trackID int, teamIDs []int) ([]*models.TrackTeam, error) {
+	trackTeams := make([]*models.TrackTeam, 0)
+	err := tx.Model(&trackTeams).Where("track_id = ?", trackID).Where("team_id IN (?)", pg.In(teamIDs)).Select()
+	return trackTeams, err
+}
+
+// LockTeam takes a transaction-scoped advisory lock on a team, which
+// serializes its registrations across tracks while the others are counted.
+func (r *TrackTeamRepository) LockTeam(tx *pg.Tx, teamID int) error {
+	_, err := tx.Exec("SELECT pg_advisory_xact_lock(?, ?)", teamLockSpace, teamID)
+	return err
+}
+
+// CountHeldSlots counts registrations that occupy a place in the track,
+// which are pending and approved ones.
+func (r *TrackTeamRepository) CountHeldSlots(tx *pg.Tx, trackID int) (int, error) {
+	return tx.Model((*models.TrackTeam)(nil)).Where("track_id = ?", trackID).
+		Where("state IN (?)", pg.In([]string{models.TrackTeamPending, models.TrackTeamApproved})).Count()
+}
+
+func (r *TrackTeamRepository) GetWaitlisted(tx *pg.Tx, trackID int, limit int) ([]*models.TrackTeam, error) {
+	trackTeams := make([]*models.TrackTeam, 0)
+	err := tx.Model(&trackTeams).Where("track_id = ?", trackID).Where("state = ?", models.TrackTeamWaitlisted).
+		Order("created_at", "id").Limit(limit).Select()
+	return trackTeams, err
+}
+
+// GetOtherRegistrations lists the live registrations of a team on other
+// tracks, telling which share the event and which overlap by dates with the
+// given track.
+func (r *TrackTeamRepository) GetOtherRegistrations(tx *pg.Tx, teamID, trackID int) ([]*schemas.TeamRegistration, error) {
+	registrations := make([]*schemas.TeamRegistration, 0)
+
+	_, err := tx.Query(&registrations, `
+		SELECT other.id                                                              AS track_id,
+		       other.event_id = t.event_id                                           AS same_event,
+		       coalesce(od.date_start < d.date_end AND d.date_start < od.date_end, false) AS overlaps
+		FROM track_team tt
+		JOIN track other ON other.id = tt.track_id AND other.deleted_at IS NULL
+		JOIN track t ON t.id = ?1
+		LEFT JOIN date d ON d.id = t.date_id
+		LEFT JOIN date od ON od.id = other.date_id
+		WHERE tt.team_id = ?0
+		  AND tt.track_id <> ?1
+		  AND tt.state IN (?2)`,
+		teamID, trackID, pg.In([]string{models.TrackTeamPending, models.TrackTeamApproved, models.TrackTeamWaitlisted}))
+
+	return registrations, err
+}
+
+// GetProgress joins every live timeline of a track with the registrations
+// and their results, ordered by stage. A zero teamID returns all teams. The
+// counted value is the one AggregateResults ranks by.
+func (r *TrackTeamRepository) GetProgress(tx *pg.Tx, trackID, teamID int) ([]*ProgressRow, error) {
+	rows := make([]*ProgressRow, 0)
+
+	_, err := tx.Query(&rows, `
+		SELECT tt.id                          AS track_team_id,
+		       tt.team_id,
+		       tt.state,
+		       tt.is_active,
+		       t.id                           AS timeline_id,
+		       t.title,
+		       coalesce(ts.count_num, 0)      AS count_num,
+		       t.status                       AS timeline_status,
+		       t.deadline,
+		       t.is_blocking,
+		       o.deadline                     AS override_deadline,
+		       o.is_blocking                  AS override_blocking,
+		       tas.track_team_id IS NOT NULL  AS is_submitted,
+		       tas.result_value IS NOT NULL   AS is_scored,
+		       counted.result_value           AS counted_value,
+		       tas.submitted_at,
+		       coalesce(tas.is_late, false)   AS is_late,
+		       coalesce(tas.penalty_percent, 0) AS penalty_percent
+		FROM track_team tt
+		JOIN timeline t ON t.track_id = tt.track_id AND t.deleted_at IS NULL
+		LEFT JOIN timeline_status ts ON ts.id = t.timeline_status_id
+		LEFT JOIN team_action_status tas ON tas.timeline_id = t.id AND tas.track_team_id = tt.id
+		LEFT JOIN LATERAL (SELECT `+countedResultValue+` AS result_value) counted ON TRUE
+		LEFT JOIN timeline_override o ON o.timeline_id = t.id AND o.track_team_id = tt.id
+		WHERE tt.track_id = ?0
+		  AND (?1 = 0 OR tt.team_id = ?1)
+		ORDER BY tt.team_id, count_num, t.deadline, t.id`,
+		trackID, teamID)
+
+	return rows, err
+}
+
 func (r *TrackTeamRepository) UpdateTrackTeam(tx *pg.Tx, trackID, teamID int, trackTeam *models.TrackTeam) (*models.TrackTeam, error) {
-	_, err := tx.Model(trackTeam).Set("is_active = ?", trackTeam.IsActive).Where("track_id = ?", trackID).Where("team_id = ?", teamID).Update()
+	_, err := tx.Model(trackTeam).Set("is_active = ?, state = ?, decided_at = ?",
+		trackTeam.IsActive, trackTeam.State, pg.NullTime{Time: trackTeam.DecidedAt}).
+		Where("track_id = ?", trackID).Where("team_id = ?", teamID).Returning("*").Update()
 	return trackTeam, err
 }
 
@@ -0,0 +1,19 @@
+package rest
+
+import (
+	"event_service/pkg/utils"
+	"net/http"
+)
+
+// requireAdmin keeps routes that reach past normal visibility, deleted rows
+// and the audit log, to the administrators listed in the config.
+func requireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !utils.IsAdmin(r.Context()) {
+			writeStatus(w, r, http.StatusForbidden, "Administrator access required")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,121 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"event_service/internal/models"
+	"event_service/internal/schemas"
+	"event_service/internal/service"
+	"fmt"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+type AuditService interface {
+	GetAuditRecords(context.Context, *schemas.AuditFilter) ([]*models.AuditRecord, error)
+}
+
+func NewAudit(log *slog.Logger, service *service.AuditService) *chi.Mux {
+	r := chi.NewRouter()
+
+	r.Use(middleware.RequestID)
+	r.Use(middleware.Recoverer)
+	r.Use(middleware.URLFormat)
+	r.Use(middleware.Logger)
+
+	r.Route("/", func(r chi.Router) {
+		r.With(requireAdmin).Get("/", getAuditRecordsHandler(log, service))
+	})
+
+	return r
+}
+
+func getAuditRecordsHandler(log *slog.Logger, service AuditService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "rest.Audit.getAll"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		filter, err := parseAuditFilter(r.URL.Query())
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid audit filter:", slog.String("error", err.Error()))
+
+			writeBadRequest(w, r, err)
+			return
+		}
+
+		records, err := service.GetAuditRecords(r.Context(), filter)
+		if err != nil {
+			log.ErrorContext(r.Context(), "Failed to get audit records:", slog.String("error", err.Error()))
+
+			writeError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(records); err != nil {
+			log.ErrorContext(r.Context(), "Failed to encode response:", slog.String("error", err.Error()))
+			return
+		}
+
+		log.InfoContext(r.Context(), "Audit records fetched successfully")
+	}
+}
+
+func parseAuditFilter(query url.Values) (*schemas.AuditFilter, error) {
+	filter := &schemas.AuditFilter{
+		Entity:   query.Get("entity"),
+		EntityID: query.Get("entity_id"),
+		Action:   query.Get("action"),
+	}
+
+	ints := map[string]*int{
+		"actor_id": &filter.ActorID,
+		"limit":    &filter.Limit,
+		"offset":   &filter.Offset,
+	}
+
+	for name, dst := range ints {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+
+		converted, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", name, err)
+		}
+
+		*dst = converted
+	}
+
+	times := map[string]*time.Time{
+		"from": &filter.From,
+		"to":   &filter.To,
+	}
+
+	for name, dst := range times {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+
+		converted, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", name, err)
+		}
+
+		*dst = converted
+	}
+
+	return filter, nil
+}
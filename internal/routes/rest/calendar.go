@@ -0,0 +1,74 @@
+package rest
+
+import (
+	"context"
+	"event_service/internal/service"
+	"event_service/pkg/ical"
+	"fmt"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type CalendarService interface {
+	GetEventCalendar(context.Context, int) (*ical.Calendar, error)
+	GetTrackCalendar(context.Context, int) (*ical.Calendar, error)
+	GetTeamCalendar(context.Context, int) (*ical.Calendar, error)
+}
+
+func NewCalendar(log *slog.Logger, service *service.CalendarService) *chi.Mux {
+	r := chi.NewRouter()
+
+	r.Use(middleware.RequestID)
+	r.Use(middleware.Recoverer)
+	r.Use(middleware.URLFormat)
+	r.Use(middleware.Logger)
+
+	r.Route("/", func(r chi.Router) {
+		r.Get("/event/{id}", calendarHandler(log, "event", service.GetEventCalendar))
+		r.Get("/track/{id}", calendarHandler(log, "track", service.GetTrackCalendar))
+		r.Get("/team/{id}", calendarHandler(log, "team", service.GetTeamCalendar))
+	})
+
+	return r
+}
+
+func calendarHandler(log *slog.Logger, kind string, getCalendar func(context.Context, int) (*ical.Calendar, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "rest.Calendar.get"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("kind", kind),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid id:", slog.String("error", err.Error()))
+
+			writeStatus(w, r, http.StatusBadRequest, "Invalid id")
+			return
+		}
+
+		calendar, err := getCalendar(r.Context(), id)
+		if err != nil {
+			log.ErrorContext(r.Context(), "Failed to build calendar:", slog.String("error", err.Error()))
+
+			writeError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=\"%s-%d.ics\"", kind, id))
+		w.WriteHeader(http.StatusOK)
+
+		if err := calendar.Encode(w); err != nil {
+			log.ErrorContext(r.Context(), "Failed to encode calendar:", slog.String("error", err.Error()))
+		}
+
+		log.InfoContext(r.Context(), "Calendar fetched successfully")
+	}
+}
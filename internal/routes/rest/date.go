@@ -1,7 +1,10 @@
 package rest
 
 import (
+	"context"
 	api "event_service/gen/date"
+	"event_service/internal/models"
+	"event_service/internal/schemas"
 	"event_service/internal/service"
 	"fmt"
 	"github.com/go-chi/chi/v5"
@@ -15,11 +18,13 @@ import (
 )
 
 type DateService interface {
-	GetAllDates() ([]*api.DateResponse, error)
-	GetDateByID(id int) (*api.DateResponse, error)
-	CreateDate(date api.Date) (*api.DateResponse, error)
-	UpdateDate(id int, date api.DateUpdate) (*api.DateResponse, error)
-	DeleteDate(id int) error
+	GetAllDates(context.Context) ([]*api.DateResponse, error)
+	GetDateByID(ctx context.Context, id int) (*api.DateResponse, error)
+	CreateDate(ctx context.Context, date api.Date) (*api.DateResponse, error)
+	UpdateDate(ctx context.Context, id int, date api.DateUpdate) (*api.DateResponse, error)
+	PatchDate(ctx context.Context, id int, patch schemas.DatePatch) (*api.DateResponse, error)
+	DeleteDate(ctx context.Context, id int) error
+	GetDateAgenda(ctx context.Context, id int) ([]*models.Session, error)
 }
 
 type DateHandler struct {
@@ -55,16 +60,14 @@ func (h *DateHandler) GetDates(ctx echo.Context) error {
 		slog.String("op", op),
 	)
 
-	dates, err := h.service.GetAllDates()
+	dates, err := h.service.GetAllDates(ctx.Request().Context())
 	if err != nil {
-		log.Error("Failed to get dates:", slog.String("error", err.Error()))
+		log.ErrorContext(ctx.Request().Context(), "Failed to get dates:", slog.String("error", err.Error()))
 
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to get dates",
-		})
+		return echoError(ctx, err)
 	}
 
-	log.Info("Dates fetched successfully")
+	log.InfoContext(ctx.Request().Context(), "Dates fetched successfully")
 
 	return ctx.JSON(http.StatusOK, dates)
 }
@@ -78,19 +81,19 @@ func (h *DateHandler) PostDates(ctx echo.Context) error {
 
 	var date api.Date
 	if err := decodeAndValidateEcho(ctx, &date, h.validator); err != nil {
-		log.Error("Failed to decode and validate request:", slog.String("error", err.Error()))
+		log.ErrorContext(ctx.Request().Context(), "Failed to decode and validate request:", slog.String("error", err.Error()))
 
-		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return echoBadRequest(ctx, err)
 	}
 
-	resp, err := h.service.CreateDate(date)
+	resp, err := h.service.CreateDate(ctx.Request().Context(), date)
 	if err != nil {
-		log.Error("Failed to create date:", slog.String("error", err.Error()))
+		log.ErrorContext(ctx.Request().Context(), "Failed to create date:", slog.String("error", err.Error()))
 
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to create date"})
+		return echoError(ctx, err)
 	}
 
-	log.Info("Date created successfully")
+	log.InfoContext(ctx.Request().Context(), "Date created successfully")
 
 	return ctx.JSON(http.StatusCreated, resp)
 }
@@ -104,19 +107,19 @@ func (h *DateHandler) PutDatesId(ctx echo.Context, id api.Id) error {
 
 	var date api.DateUpdate
 	if err := decodeAndValidateEcho(ctx, &date, h.validator); err != nil {
-		log.Error("Failed to decode and validate request:", slog.String("error", err.Error()))
+		log.ErrorContext(ctx.Request().Context(), "Failed to decode and validate request:", slog.String("error", err.Error()))
 
-		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return echoBadRequest(ctx, err)
 	}
 
-	resp, err := h.service.UpdateDate(int(id), date)
+	resp, err := h.service.UpdateDate(ctx.Request().Context(), int(id), date)
 	if err != nil {
-		log.Error("Failed to update date:", slog.String("error", err.Error()))
+		log.ErrorContext(ctx.Request().Context(), "Failed to update date:", slog.String("error", err.Error()))
 
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to update date"})
+		return echoError(ctx, err)
 	}
 
-	log.Info("Date updated successfully")
+	log.InfoContext(ctx.Request().Context(), "Date updated successfully")
 
 	return ctx.JSON(http.StatusOK, resp)
 }
@@ -128,18 +131,37 @@ func (h *DateHandler) GetDatesId(ctx echo.Context, id api.Id) error {
 		slog.String("op", op),
 	)
 
-	date, err := h.service.GetDateByID(int(id))
+	date, err := h.service.GetDateByID(ctx.Request().Context(), int(id))
 	if err != nil {
-		log.Error("Failed to get date:", slog.String("error", err.Error()))
+		log.ErrorContext(ctx.Request().Context(), "Failed to get date:", slog.String("error", err.Error()))
 
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get date"})
+		return echoError(ctx, err)
 	}
 
-	log.Info("Date fetched successfully")
+	log.InfoContext(ctx.Request().Context(), "Date fetched successfully")
 
 	return ctx.JSON(http.StatusOK, date)
 }
 
+func (h *DateHandler) GetDatesIdAgenda(ctx echo.Context, id api.Id) error {
+	const op = "rest.Date.getAgenda"
+
+	log := h.log.With(
+		slog.String("op", op),
+	)
+
+	sessions, err := h.service.GetDateAgenda(ctx.Request().Context(), int(id))
+	if err != nil {
+		log.ErrorContext(ctx.Request().Context(), "Failed to get date agenda:", slog.String("error", err.Error()))
+
+		return echoError(ctx, err)
+	}
+
+	log.InfoContext(ctx.Request().Context(), "Date agenda fetched successfully")
+
+	return ctx.JSON(http.StatusOK, sessions)
+}
+
 func (h *DateHandler) DeleteDatesId(ctx echo.Context, id api.Id) error {
 	const op = "rest.Date.delete"
 
@@ -147,18 +169,44 @@ func (h *DateHandler) DeleteDatesId(ctx echo.Context, id api.Id) error {
 		slog.String("op", op),
 	)
 
-	err := h.service.DeleteDate(id)
+	err := h.service.DeleteDate(ctx.Request().Context(), id)
 	if err != nil {
-		log.Error("Failed to delete date:", slog.String("error", err.Error()))
+		log.ErrorContext(ctx.Request().Context(), "Failed to delete date:", slog.String("error", err.Error()))
 
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to delete date"})
+		return echoError(ctx, err)
 	}
 
-	log.Info("Date deleted successfully")
+	log.InfoContext(ctx.Request().Context(), "Date deleted successfully")
 
 	return ctx.NoContent(http.StatusOK)
 }
 
+func (h *DateHandler) PatchDatesId(ctx echo.Context, id api.Id) error {
+	const op = "rest.Date.patch"
+
+	log := h.log.With(
+		slog.String("op", op),
+	)
+
+	var patch schemas.DatePatch
+	if err := decodeMergePatch(ctx.Request(), &patch); err != nil {
+		log.ErrorContext(ctx.Request().Context(), "Failed to decode patch:", slog.String("error", err.Error()))
+
+		return echoBadRequest(ctx, err)
+	}
+
+	resp, err := h.service.PatchDate(ctx.Request().Context(), int(id), patch)
+	if err != nil {
+		log.ErrorContext(ctx.Request().Context(), "Failed to patch date:", slog.String("error", err.Error()))
+
+		return echoError(ctx, err)
+	}
+
+	log.InfoContext(ctx.Request().Context(), "Date patched successfully")
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
 type echoResponseWriter struct {
 	http.ResponseWriter
 }
@@ -172,10 +220,8 @@ func HandlerAdapter(echoHandler func(echo.Context) error) http.HandlerFunc {
 		echoContext := echo.New().NewContext(r, &echoResponseWriter{w})
 
 		params := chi.RouteContext(r.Context()).URLParams
-		for idx, key := range params.Keys {
-			echoContext.SetParamNames(key)
-			echoContext.SetParamValues(params.Values[idx])
-		}
+		echoContext.SetParamNames(params.Keys...)
+		echoContext.SetParamValues(params.Values...)
 
 		_ = echoHandler(echoContext)
 	}
@@ -189,9 +235,9 @@ func NewDate(log *slog.Logger, service *service.DateService) *chi.Mux {
 	r.Use(middleware.URLFormat)
 	r.Use(middleware.Logger)
 
-	validate := validator.Validate{}
+	validate := newValidator()
 
-	handler := NewDateHandler(log, service, &validate)
+	handler := NewDateHandler(log, service, validate)
 
 	r.Route("/", func(r chi.Router) {
 		r.Get("/", HandlerAdapter(handler.GetDates))
@@ -208,10 +254,20 @@ func NewDate(log *slog.Logger, service *service.DateService) *chi.Mux {
 				return handler.PutDatesId(ctx, api.Id(id))
 			}))
 
+			r.Patch("/", HandlerAdapter(func(ctx echo.Context) error {
+				id, _ := strconv.Atoi(ctx.Param("Id"))
+				return handler.PatchDatesId(ctx, api.Id(id))
+			}))
+
 			r.Delete("/", HandlerAdapter(func(ctx echo.Context) error {
 				id, _ := strconv.Atoi(ctx.Param("Id"))
 				return handler.DeleteDatesId(ctx, api.Id(id))
 			}))
+
+			r.Get("/agenda", HandlerAdapter(func(ctx echo.Context) error {
+				id, _ := strconv.Atoi(ctx.Param("Id"))
+				return handler.GetDatesIdAgenda(ctx, api.Id(id))
+			}))
 		})
 	})
 
@@ -0,0 +1,144 @@
+package rest
+
+import (
+	"errors"
+	"event_service/internal/service"
+	"event_service/pkg/http/utils"
+	"fmt"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+func newValidator() *validator.Validate {
+	validate := validator.New()
+
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+
+		if name == "" {
+			return field.Name
+		}
+
+		return name
+	})
+
+	return validate
+}
+
+// problemFromError picks the response for an error coming out of a service.
+// Server errors keep their text out of the response, it is logged instead.
+func problemFromError(err error) *utils.Problem {
+	err = service.TranslateError(err)
+
+	var domain *service.Error
+	if errors.As(err, &domain) {
+		problem := utils.NewProblem(domainStatus(domain.Kind), domain.Detail)
+		for _, violation := range domain.Violations {
+			problem.Errors = append(problem.Errors, utils.FieldError(violation))
+		}
+
+		return problem
+	}
+
+	switch {
+	case errors.Is(err, service.ErrStaleVersion), errors.Is(err, utils.ErrInvalidIfMatch):
+		return utils.NewProblem(http.StatusPreconditionFailed, err.Error())
+	case errors.Is(err, utils.ErrMissingIfMatch):
+		return utils.NewProblem(http.StatusPreconditionRequired, err.Error())
+	}
+
+	return utils.NewProblem(http.StatusInternalServerError, "")
+}
+
+func domainStatus(kind error) int {
+	switch kind {
+	case service.ErrNotFound:
+		return http.StatusNotFound
+	case service.ErrConflict:
+		return http.StatusConflict
+	case service.ErrValidation:
+		return http.StatusUnprocessableEntity
+	case service.ErrForbidden:
+		return http.StatusForbidden
+	case service.ErrUnavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// badRequestProblem describes a request that could not be decoded or failed
+// struct validation, listing every offending field.
+func badRequestProblem(err error) *utils.Problem {
+	if errors.Is(err, errUnsupportedPatchType) {
+		return utils.NewProblem(http.StatusUnsupportedMediaType, err.Error())
+	}
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return utils.NewProblem(http.StatusBadRequest, err.Error())
+	}
+
+	problem := utils.NewProblem(http.StatusBadRequest, "request body failed validation")
+	for _, fieldErr := range validationErrors {
+		problem.Errors = append(problem.Errors, fieldError(fieldErr))
+	}
+
+	return problem
+}
+
+func fieldError(fieldErr validator.FieldError) utils.FieldError {
+	rule := fieldErr.Tag()
+	if fieldErr.Param() != "" {
+		rule += "=" + fieldErr.Param()
+	}
+
+	field := fieldErr.Namespace()
+	if idx := strings.Index(field, "."); idx >= 0 {
+		field = field[idx+1:]
+	}
+
+	return utils.FieldError{
+		Field:   field,
+		Rule:    rule,
+		Message: fmt.Sprintf("%s must satisfy %s", field, rule),
+	}
+}
+
+func writeProblem(w http.ResponseWriter, r *http.Request, problem *utils.Problem) error {
+	problem.Instance = r.URL.Path
+	problem.RequestID = middleware.GetReqID(r.Context())
+
+	return utils.WriteProblem(w, problem)
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	_ = writeProblem(w, r, problemFromError(err))
+}
+
+func writeBadRequest(w http.ResponseWriter, r *http.Request, err error) {
+	_ = writeProblem(w, r, badRequestProblem(err))
+}
+
+func writeStatus(w http.ResponseWriter, r *http.Request, status int, detail string) {
+	_ = writeProblem(w, r, utils.NewProblem(status, detail))
+}
+
+func echoError(ctx echo.Context, err error) error {
+	return writeProblem(ctx.Response(), ctx.Request(), problemFromError(err))
+}
+
+func echoBadRequest(ctx echo.Context, err error) error {
+	return writeProblem(ctx.Response(), ctx.Request(), badRequestProblem(err))
+}
+
+func echoStatus(ctx echo.Context, status int, detail string) error {
+	return writeProblem(ctx.Response(), ctx.Request(), utils.NewProblem(status, detail))
+}
@@ -1,7 +1,10 @@
 package rest
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	status_api "event_service/gen/status"
 	"event_service/internal/models"
 	"event_service/internal/schemas"
 	"event_service/internal/service"
@@ -16,16 +19,25 @@ import (
 )
 
 type EventService interface {
-	GetAllEvents() ([]*models.Event, error)
-	GetEventByID(eventId int) (*models.Event, error)
-	GetEventByStatus(status string) ([]*models.Event, error)
-	CreateEvent(event schemas.Event) (*models.Event, error)
-	UpdateEvent(eventId int, newEvent schemas.EventUpdate) (*models.Event, error)
-	DeleteEvent(eventID int) error
-
-	GetAllEventLocations(eventId int) ([]*models.Location, error)
-	AddLocationToEvent(locationEventSchema *schemas.EventLocation) (*models.EventLocation, error)
-	RemoveLocationFromEvent(statusEventSchema *schemas.EventLocation) error
+	GetAllEvents(ctx context.Context, filter *schemas.EventFilter) ([]*models.Event, error)
+	GetEventByID(ctx context.Context, eventId int) (*models.Event, error)
+	GetEventByStatus(ctx context.Context, status string) ([]*models.Event, error)
+	CreateEvent(ctx context.Context, event schemas.Event) (*models.Event, error)
+	UpdateEvent(ctx context.Context, eventId int, newEvent schemas.EventUpdate, version int) (*models.Event, error)
+	PatchEvent(ctx context.Context, eventId int, patch schemas.EventPatch, version int) (*models.Event, error)
+	DeleteEvent(ctx context.Context, eventID int) error
+	GetDeletedEvents(context.Context) ([]*models.Event, error)
+	RestoreEvent(ctx context.Context, eventID int) (*models.Event, error)
+	RescheduleEvent(ctx context.Context, eventId int, request schemas.EventReschedule, version int, preview bool) (*schemas.ReschedulePlan, error)
+	GetEventAgenda(ctx context.Context, eventId int) ([]*models.Session, error)
+
+	GetAllEventLocations(ctx context.Context, eventId int) ([]*models.Location, error)
+	AddLocationToEvent(ctx context.Context, locationEventSchema *schemas.EventLocation) (*models.EventLocation, error)
+	RemoveLocationFromEvent(ctx context.Context, statusEventSchema *schemas.EventLocation) error
+
+	GetEventStatuses(ctx context.Context, eventId int) ([]*status_api.StatusResponse, error)
+	AddStatusToEvent(ctx context.Context, statusEvent *schemas.StatusEvent) (*models.StatusEvent, error)
+	RemoveStatusFromEvent(ctx context.Context, statusEvent *schemas.StatusEvent) error
 }
 
 func DecodeAndValidate(r *http.Request, dst interface{}, validate *validator.Validate) error {
@@ -44,11 +56,12 @@ func NewEvent(log *slog.Logger, service *service.EventService) *chi.Mux {
 	r.Use(middleware.URLFormat)
 	r.Use(middleware.Logger)
 
-	validate := validator.New()
+	validate := newValidator()
 
 	r.Route("/", func(r chi.Router) {
 		r.Get("/", getAllEventsHandler(log, service))
 		r.Post("/", createEventHandler(log, service, validate))
+		r.With(requireAdmin).Get("/deleted", getDeletedEventsHandler(log, service))
 
 		r.Route("/location", func(r chi.Router) {
 			r.Post("/", addLocationToEventHandler(log, service))
@@ -62,7 +75,21 @@ func NewEvent(log *slog.Logger, service *service.EventService) *chi.Mux {
 		r.Route("/{id}", func(r chi.Router) {
 			r.Get("/", getEventByIDHandler(log, service))
 			r.Put("/", updateEventHandler(log, service, validate))
+			r.Patch("/", patchEventHandler(log, service))
 			r.Delete("/", deleteEventHandler(log, service))
+			r.With(requireAdmin).Post("/restore", restoreEventHandler(log, service))
+			r.Post("/reschedule", rescheduleEventHandler(log, service, validate))
+			r.Get("/agenda", agendaHandler(log, "event", service.GetEventAgenda))
+
+			r.Route("/status", func(r chi.Router) {
+				r.Get("/", getStatusesHandler(log, "Event", service.GetEventStatuses))
+				r.Post("/", addStatusHandler(log, "Event", validate, func(ctx context.Context, id int, statusId int) (interface{}, error) {
+					return service.AddStatusToEvent(ctx, &schemas.StatusEvent{EventID: id, StatusID: statusId})
+				}))
+				r.Delete("/{statusId}", removeStatusHandler(log, "Event", func(ctx context.Context, id int, statusId int) error {
+					return service.RemoveStatusFromEvent(ctx, &schemas.StatusEvent{EventID: id, StatusID: statusId})
+				}))
+			})
 		})
 	})
 
@@ -78,22 +105,30 @@ func getAllEventsHandler(log *slog.Logger, service EventService) http.HandlerFun
 			slog.With("request_id", middleware.GetReqID(r.Context())),
 		)
 
-		events, err := service.GetAllEvents()
+		status, statusId, err := parseStatusFilter(r)
 		if err != nil {
-			log.Error("error getting all events:", err)
+			log.ErrorContext(r.Context(), "Invalid event filter:", slog.String("error", err.Error()))
 
-			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			writeBadRequest(w, r, err)
+			return
+		}
+
+		events, err := service.GetAllEvents(r.Context(), &schemas.EventFilter{Status: status, StatusID: statusId})
+		if err != nil {
+			log.ErrorContext(r.Context(), "error getting all events:", err)
+
+			writeError(w, r, err)
 			return
 		}
 
 		w.WriteHeader(http.StatusOK)
 		if err := json.NewEncoder(w).Encode(events); err != nil {
-			log.Error("error encoding events:", err)
+			log.ErrorContext(r.Context(), "error encoding events:", err)
 
 			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 		}
 
-		log.Info("events successfully fetched")
+		log.InfoContext(r.Context(), "events successfully fetched")
 	}
 }
 
@@ -107,22 +142,30 @@ func getEventByIDHandler(log *slog.Logger, service EventService) http.HandlerFun
 		)
 
 		eventId, err := strconv.Atoi(chi.URLParam(r, "id"))
-		event, err := service.GetEventByID(eventId)
+		event, err := service.GetEventByID(r.Context(), eventId)
 		if err != nil {
-			log.Error("Failed to get event:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to get event:", err.Error())
 
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, r, err)
+			return
+		}
+
+		etag := utils.ETag(event.Version)
+		w.Header().Set("ETag", etag)
+
+		if utils.IfNoneMatch(r, etag) {
+			w.WriteHeader(http.StatusNotModified)
 			return
 		}
 
 		w.WriteHeader(http.StatusOK)
 		if err := json.NewEncoder(w).Encode(event); err != nil {
-			log.Error("Failed to encode response:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to encode response:", err.Error())
 
 			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 		}
 
-		log.Info("Event fetched successfully")
+		log.InfoContext(r.Context(), "Event fetched successfully")
 	}
 }
 
@@ -137,28 +180,28 @@ func createEventHandler(log *slog.Logger, service EventService, validate *valida
 
 		var event schemas.Event
 		if err := DecodeAndValidate(r, &event, validate); err != nil {
-			log.Error("Failed to decode request:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to decode request:", err.Error())
 
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			writeBadRequest(w, r, err)
 			return
 		}
 
-		resp, err := service.CreateEvent(event)
+		resp, err := service.CreateEvent(r.Context(), event)
 		if err != nil {
-			log.Error("Failed to create event:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to create event:", err.Error())
 
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, r, err)
 			return
 		}
 
 		w.WriteHeader(http.StatusCreated)
 		if err := json.NewEncoder(w).Encode(resp); err != nil {
-			log.Error("Failed to encode response:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to encode response:", err.Error())
 
 			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 		}
 
-		log.Info("Event created successfully")
+		log.InfoContext(r.Context(), "Event created successfully")
 	}
 }
 
@@ -173,30 +216,90 @@ func updateEventHandler(log *slog.Logger, service EventService, validate *valida
 
 		eventId, err := strconv.Atoi(chi.URLParam(r, "id"))
 
+		version, err := utils.IfMatchVersion(r)
+		if err != nil {
+			log.ErrorContext(r.Context(), "Precondition check failed:", slog.String("error", err.Error()))
+
+			writeError(w, r, err)
+			return
+		}
+
 		var event schemas.EventUpdate
 		if err := DecodeAndValidate(r, &event, validate); err != nil {
-			log.Error("Failed to decode request:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to decode request:", err.Error())
 
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			writeBadRequest(w, r, err)
 			return
 		}
 
-		resp, err := service.UpdateEvent(eventId, event)
+		resp, err := service.UpdateEvent(r.Context(), eventId, event, version)
 		if err != nil {
-			log.Error("Failed to update event:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to update event:", err.Error())
 
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, r, err)
 			return
 		}
 
+		w.Header().Set("ETag", utils.ETag(resp.Version))
 		w.WriteHeader(http.StatusOK)
 		if err := json.NewEncoder(w).Encode(resp); err != nil {
-			log.Error("Failed to encode response:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to encode response:", err.Error())
 
 			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 		}
 
-		log.Info("Event updated successfully")
+		log.InfoContext(r.Context(), "Event updated successfully")
+	}
+}
+
+func patchEventHandler(log *slog.Logger, service EventService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "rest.Event.patch"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		eventId, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid event id:", slog.String("error", err.Error()))
+
+			writeStatus(w, r, http.StatusBadRequest, "Invalid event id")
+			return
+		}
+
+		version, err := utils.IfMatchVersion(r)
+		if err != nil {
+			log.ErrorContext(r.Context(), "Precondition check failed:", slog.String("error", err.Error()))
+
+			writeError(w, r, err)
+			return
+		}
+
+		var patch schemas.EventPatch
+		if err := decodeMergePatch(r, &patch); err != nil {
+			log.ErrorContext(r.Context(), "Failed to decode patch:", slog.String("error", err.Error()))
+
+			writeBadRequest(w, r, err)
+			return
+		}
+
+		resp, err := service.PatchEvent(r.Context(), eventId, patch, version)
+		if err != nil {
+			log.ErrorContext(r.Context(), "Failed to patch event:", slog.String("error", err.Error()))
+
+			writeError(w, r, err)
+			return
+		}
+
+		w.Header().Set("ETag", utils.ETag(resp.Version))
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			log.ErrorContext(r.Context(), "Failed to encode response:", slog.String("error", err.Error()))
+		}
+
+		log.InfoContext(r.Context(), "Event patched successfully")
 	}
 }
 
@@ -210,17 +313,143 @@ func deleteEventHandler(log *slog.Logger, service EventService) http.HandlerFunc
 		)
 
 		trackId, err := strconv.Atoi(chi.URLParam(r, "id"))
-		err = service.DeleteEvent(trackId)
+		err = service.DeleteEvent(r.Context(), trackId)
+
+		if err != nil {
+			log.ErrorContext(r.Context(), "Failed to delete event:", err.Error())
+
+			writeError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		log.InfoContext(r.Context(), "Event deleted successfully")
+	}
+}
+
+func getDeletedEventsHandler(log *slog.Logger, service EventService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "rest.Event.getDeleted"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		events, err := service.GetDeletedEvents(r.Context())
+		if err != nil {
+			log.ErrorContext(r.Context(), "Failed to get deleted events:", slog.String("error", err.Error()))
+
+			writeError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(events); err != nil {
+			log.ErrorContext(r.Context(), "Failed to encode response:", slog.String("error", err.Error()))
+
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		}
+
+		log.InfoContext(r.Context(), "Deleted events fetched successfully")
+	}
+}
+
+func restoreEventHandler(log *slog.Logger, service EventService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "rest.Event.restore"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		eventId, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid event id:", slog.String("error", err.Error()))
+
+			writeStatus(w, r, http.StatusBadRequest, "Invalid event id")
+			return
+		}
+
+		event, err := service.RestoreEvent(r.Context(), eventId)
+		if err != nil {
+			log.ErrorContext(r.Context(), "Failed to restore event:", slog.String("error", err.Error()))
+
+			writeError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(event); err != nil {
+			log.ErrorContext(r.Context(), "Failed to encode response:", slog.String("error", err.Error()))
+
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		}
+
+		log.InfoContext(r.Context(), "Event restored successfully")
+	}
+}
+
+func rescheduleEventHandler(log *slog.Logger, service EventService, validate *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "rest.Event.reschedule"
 
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		eventId, err := strconv.Atoi(chi.URLParam(r, "id"))
 		if err != nil {
-			log.Error("Failed to delete event:", err.Error())
+			log.ErrorContext(r.Context(), "Invalid event id:", slog.String("error", err.Error()))
 
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeStatus(w, r, http.StatusBadRequest, "Invalid event id")
+			return
+		}
+
+		preview := false
+		if value := r.URL.Query().Get("preview"); value != "" {
+			if preview, err = strconv.ParseBool(value); err != nil {
+				log.ErrorContext(r.Context(), "Invalid preview flag:", slog.String("error", err.Error()))
+
+				writeStatus(w, r, http.StatusBadRequest, "Invalid preview flag")
+				return
+			}
+		}
+
+		// If-Match is optional: a reschedule only moves dates, clients may still
+		// send it to guard against concurrent edits.
+		version, err := utils.IfMatchVersion(r)
+		if err != nil && !errors.Is(err, utils.ErrMissingIfMatch) {
+			log.ErrorContext(r.Context(), "Precondition check failed:", slog.String("error", err.Error()))
+
+			writeError(w, r, err)
+			return
+		}
+
+		var request schemas.EventReschedule
+		if err := DecodeAndValidate(r, &request, validate); err != nil {
+			log.ErrorContext(r.Context(), "Failed to decode request:", slog.String("error", err.Error()))
+
+			writeBadRequest(w, r, err)
+			return
+		}
+
+		plan, err := service.RescheduleEvent(r.Context(), eventId, request, version, preview)
+		if err != nil {
+			log.ErrorContext(r.Context(), "Failed to reschedule event:", slog.String("error", err.Error()))
+
+			writeError(w, r, err)
 			return
 		}
 
 		w.WriteHeader(http.StatusOK)
-		log.Info("Event deleted successfully")
+		if err := json.NewEncoder(w).Encode(plan); err != nil {
+			log.ErrorContext(r.Context(), "Failed to encode response:", slog.String("error", err.Error()))
+		}
+
+		log.InfoContext(r.Context(), "Event rescheduled successfully", slog.Bool("preview", preview))
 	}
 }
 
@@ -234,23 +463,23 @@ func getAllEventLocationsHandler(log *slog.Logger, service EventService) http.Ha
 		)
 
 		locationId, err := strconv.Atoi(chi.URLParam(r, "id"))
-		locations, err := service.GetAllEventLocations(locationId)
+		locations, err := service.GetAllEventLocations(r.Context(), locationId)
 
 		if err != nil {
-			log.Error("Failed to get locations by event:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to get locations by event:", err.Error())
 
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, r, err)
 			return
 		}
 
 		w.WriteHeader(http.StatusOK)
 		if err := json.NewEncoder(w).Encode(locations); err != nil {
-			log.Error("Failed to encode response:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to encode response:", err.Error())
 
 			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 		}
 
-		log.Info("Locations by event fetched successfully")
+		log.InfoContext(r.Context(), "Locations by event fetched successfully")
 	}
 }
 
@@ -270,32 +499,32 @@ func addLocationToEventHandler(log *slog.Logger, service EventService) http.Hand
 
 		convertedHeaders, err := utils.ValidateHeaders(headersList, log, r)
 		if err != nil {
-			log.Error("Failed to validate headers:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to validate headers:", err.Error())
 
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			writeBadRequest(w, r, err)
 			return
 		}
 
-		newLocation, err := service.AddLocationToEvent(&schemas.EventLocation{
+		newLocation, err := service.AddLocationToEvent(r.Context(), &schemas.EventLocation{
 			LocationID: convertedHeaders["LocationId"].(int),
 			EventID:    convertedHeaders["EventId"].(int),
 		})
 
 		if err != nil {
-			log.Error("Failed to add location to event:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to add location to event:", err.Error())
 
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, r, err)
 			return
 		}
 
 		w.WriteHeader(http.StatusOK)
 		if err := json.NewEncoder(w).Encode(newLocation); err != nil {
-			log.Error("Failed to encode response:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to encode response:", err.Error())
 
 			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 		}
 
-		log.Info("Location added to event successfully")
+		log.InfoContext(r.Context(), "Location added to event successfully")
 	}
 }
 
@@ -311,34 +540,34 @@ func removeLocationsFromEventHandler(log *slog.Logger, service EventService) htt
 		queryParams := r.URL.Query()
 
 		if queryParams.Get("location_id") == "" {
-			log.Error("Missing 'location_id' in query")
+			log.ErrorContext(r.Context(), "Missing 'location_id' in query")
 
-			http.Error(w, "Missing 'location_id' in query", http.StatusBadRequest)
+			writeStatus(w, r, http.StatusBadRequest, "Missing 'location_id' in query")
 			return
 		}
 
 		eventId, err := strconv.Atoi(chi.URLParam(r, "id"))
 		locationId, err := strconv.Atoi(queryParams.Get("location_id"))
 		if err != nil {
-			log.Error("Invalid format of location_id:", err.Error())
+			log.ErrorContext(r.Context(), "Invalid format of location_id:", err.Error())
 
-			http.Error(w, fmt.Sprintf("Invalid format of location_id query, expected number, got %s", queryParams.Get("status_id")), http.StatusBadRequest)
+			writeStatus(w, r, http.StatusBadRequest, fmt.Sprintf("Invalid format of location_id query, expected number, got %s", queryParams.Get("status_id")))
 			return
 		}
 
-		err = service.RemoveLocationFromEvent(&schemas.EventLocation{
+		err = service.RemoveLocationFromEvent(r.Context(), &schemas.EventLocation{
 			EventID:    eventId,
 			LocationID: locationId,
 		})
 
 		if err != nil {
-			log.Error("Failed to remove location from event:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to remove location from event:", err.Error())
 
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, r, err)
 			return
 		}
 
 		w.WriteHeader(http.StatusOK)
-		log.Info("Location deleted from event successfully")
+		log.InfoContext(r.Context(), "Location deleted from event successfully")
 	}
 }
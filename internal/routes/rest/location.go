@@ -1,23 +1,39 @@
 package rest
 
 import (
+	"context"
+	"errors"
 	locationapi "event_service/gen/location"
+	"event_service/internal/models"
+	"event_service/internal/schemas"
 	"event_service/internal/service"
+	"fmt"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 	"log/slog"
+	"math"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type LocationService interface {
-	GetAllLocations() ([]*locationapi.LocationResponse, error)
-	GetLocationById(locationId int) (*locationapi.LocationResponse, error)
-	CreateLocation(date locationapi.Location) (*locationapi.LocationResponse, error)
-	UpdateLocation(locationId int, date locationapi.LocationUpdate) (*locationapi.LocationResponse, error)
-	DeleteLocation(locationId int) error
+	GetAllLocations(ctx context.Context, filter *schemas.LocationFilter) ([]*locationapi.LocationResponse, error)
+	GetLocationById(ctx context.Context, locationId int) (*locationapi.LocationResponse, error)
+	CreateLocation(ctx context.Context, date locationapi.Location) (*locationapi.LocationResponse, error)
+	UpdateLocation(ctx context.Context, locationId int, date locationapi.LocationUpdate) (*locationapi.LocationResponse, error)
+	PatchLocation(ctx context.Context, id int, patch schemas.LocationPatch) (*locationapi.LocationResponse, error)
+	DeleteLocation(ctx context.Context, locationId int) error
+	GetDeletedLocations(context.Context) ([]*locationapi.LocationResponse, error)
+	RestoreLocation(ctx context.Context, locationId int) (*locationapi.LocationResponse, error)
+	GetLocationAgenda(ctx context.Context, locationId int, from time.Time, to time.Time) ([]*models.Session, error)
+	GetLocationBookings(ctx context.Context, locationId int, from time.Time, to time.Time) ([]*models.LocationBooking, error)
+	CreateLocationBooking(ctx context.Context, locationId int, booking schemas.LocationBooking) (*models.LocationBooking, error)
+	DeleteLocationBooking(ctx context.Context, locationId int, bookingId int) error
+	GetLocationAvailability(ctx context.Context, locationId int, from time.Time, to time.Time, minDuration time.Duration) (*schemas.LocationAvailability, error)
 }
 
 type LocationHandler struct {
@@ -34,23 +50,28 @@ func NewLocationHandler(log *slog.Logger, service LocationService, validator *va
 	}
 }
 
-func (h *LocationHandler) GetLocation(ctx echo.Context) error {
+func (h *LocationHandler) GetLocation(ctx echo.Context, params locationapi.GetLocationParams) error {
 	const op = "rest.Location.getAll"
 
 	log := h.log.With(
 		slog.String("op", op),
 	)
 
-	locations, err := h.service.GetAllLocations()
+	filter, err := parseLocationFilter(params)
 	if err != nil {
-		log.Error("Failed to get locations:", slog.String("error", err.Error()))
+		log.ErrorContext(ctx.Request().Context(), "Invalid location filter:", slog.String("error", err.Error()))
 
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to get locations",
-		})
+		return echoBadRequest(ctx, err)
+	}
+
+	locations, err := h.service.GetAllLocations(ctx.Request().Context(), filter)
+	if err != nil {
+		log.ErrorContext(ctx.Request().Context(), "Failed to get locations:", slog.String("error", err.Error()))
+
+		return echoError(ctx, err)
 	}
 
-	log.Info("Locations fetched successfully")
+	log.InfoContext(ctx.Request().Context(), "Locations fetched successfully")
 
 	return ctx.JSON(http.StatusOK, locations)
 }
@@ -62,20 +83,150 @@ func (h *LocationHandler) GetLocationId(ctx echo.Context, id locationapi.Id) err
 		slog.String("op", op),
 	)
 
-	location, err := h.service.GetLocationById(int(id))
+	location, err := h.service.GetLocationById(ctx.Request().Context(), int(id))
 	if err != nil {
-		log.Error("Failed to get location:", slog.String("error", err.Error()))
+		log.ErrorContext(ctx.Request().Context(), "Failed to get location:", slog.String("error", err.Error()))
 
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to get location",
-		})
+		return echoError(ctx, err)
 	}
 
-	log.Info("Location fetched successfully")
+	log.InfoContext(ctx.Request().Context(), "Location fetched successfully")
 
 	return ctx.JSON(http.StatusOK, location)
 }
 
+func (h *LocationHandler) GetLocationIdAgenda(ctx echo.Context, id locationapi.Id) error {
+	const op = "rest.Location.getAgenda"
+
+	log := h.log.With(
+		slog.String("op", op),
+	)
+
+	from, to, err := parseTimeRange(ctx)
+	if err != nil {
+		log.ErrorContext(ctx.Request().Context(), "Invalid agenda range:", slog.String("error", err.Error()))
+
+		return echoBadRequest(ctx, err)
+	}
+
+	sessions, err := h.service.GetLocationAgenda(ctx.Request().Context(), int(id), from, to)
+	if err != nil {
+		log.ErrorContext(ctx.Request().Context(), "Failed to get location agenda:", slog.String("error", err.Error()))
+
+		return echoError(ctx, err)
+	}
+
+	log.InfoContext(ctx.Request().Context(), "Location agenda fetched successfully")
+
+	return ctx.JSON(http.StatusOK, sessions)
+}
+
+func (h *LocationHandler) GetLocationIdBookings(ctx echo.Context, id locationapi.Id) error {
+	const op = "rest.Location.getBookings"
+
+	log := h.log.With(
+		slog.String("op", op),
+	)
+
+	from, to, err := parseTimeRange(ctx)
+	if err != nil {
+		log.ErrorContext(ctx.Request().Context(), "Invalid booking range:", slog.String("error", err.Error()))
+
+		return echoBadRequest(ctx, err)
+	}
+
+	bookings, err := h.service.GetLocationBookings(ctx.Request().Context(), int(id), from, to)
+	if err != nil {
+		log.ErrorContext(ctx.Request().Context(), "Failed to get location bookings:", slog.String("error", err.Error()))
+
+		return echoError(ctx, err)
+	}
+
+	log.InfoContext(ctx.Request().Context(), "Location bookings fetched successfully")
+
+	return ctx.JSON(http.StatusOK, bookings)
+}
+
+func (h *LocationHandler) PostLocationIdBookings(ctx echo.Context, id locationapi.Id) error {
+	const op = "rest.Location.createBooking"
+
+	log := h.log.With(
+		slog.String("op", op),
+	)
+
+	var booking schemas.LocationBooking
+	if err := decodeAndValidateEcho(ctx, &booking, h.validator); err != nil {
+		log.ErrorContext(ctx.Request().Context(), "Failed to decode and validate request:", slog.String("error", err.Error()))
+
+		return echoBadRequest(ctx, err)
+	}
+
+	created, err := h.service.CreateLocationBooking(ctx.Request().Context(), int(id), booking)
+	if err != nil {
+		log.ErrorContext(ctx.Request().Context(), "Failed to create location booking:", slog.String("error", err.Error()))
+
+		return echoError(ctx, err)
+	}
+
+	log.InfoContext(ctx.Request().Context(), "Location booking created successfully")
+
+	return ctx.JSON(http.StatusCreated, created)
+}
+
+func (h *LocationHandler) DeleteLocationIdBookingsBookingId(ctx echo.Context, id locationapi.Id, bookingId int) error {
+	const op = "rest.Location.deleteBooking"
+
+	log := h.log.With(
+		slog.String("op", op),
+	)
+
+	if err := h.service.DeleteLocationBooking(ctx.Request().Context(), int(id), bookingId); err != nil {
+		log.ErrorContext(ctx.Request().Context(), "Failed to delete location booking:", slog.String("error", err.Error()))
+
+		return echoError(ctx, err)
+	}
+
+	log.InfoContext(ctx.Request().Context(), "Location booking deleted successfully")
+
+	return ctx.NoContent(http.StatusOK)
+}
+
+func (h *LocationHandler) GetLocationIdAvailability(ctx echo.Context, id locationapi.Id) error {
+	const op = "rest.Location.getAvailability"
+
+	log := h.log.With(
+		slog.String("op", op),
+	)
+
+	from, to, err := parseTimeRange(ctx)
+	if err != nil {
+		log.ErrorContext(ctx.Request().Context(), "Invalid availability range:", slog.String("error", err.Error()))
+
+		return echoBadRequest(ctx, err)
+	}
+
+	var minDuration time.Duration
+	if value := ctx.QueryParam("min_duration"); value != "" {
+		minDuration, err = time.ParseDuration(value)
+		if err != nil || minDuration < 0 {
+			log.ErrorContext(ctx.Request().Context(), "Invalid min_duration:", slog.String("value", value))
+
+			return echoStatus(ctx, http.StatusBadRequest, "Invalid min_duration, expected a duration such as 90m")
+		}
+	}
+
+	availability, err := h.service.GetLocationAvailability(ctx.Request().Context(), int(id), from, to, minDuration)
+	if err != nil {
+		log.ErrorContext(ctx.Request().Context(), "Failed to get location availability:", slog.String("error", err.Error()))
+
+		return echoError(ctx, err)
+	}
+
+	log.InfoContext(ctx.Request().Context(), "Location availability fetched successfully")
+
+	return ctx.JSON(http.StatusOK, availability)
+}
+
 func (h *LocationHandler) PostLocation(ctx echo.Context) error {
 	const op = "rest.Location.create"
 
@@ -85,23 +236,19 @@ func (h *LocationHandler) PostLocation(ctx echo.Context) error {
 
 	var location locationapi.Location
 	if err := decodeAndValidateEcho(ctx, &location, h.validator); err != nil {
-		log.Error("Failed to decode and validate request:", slog.String("error", err.Error()))
+		log.ErrorContext(ctx.Request().Context(), "Failed to decode and validate request:", slog.String("error", err.Error()))
 
-		return ctx.JSON(http.StatusBadRequest, map[string]string{
-			"error": err.Error(),
-		})
+		return echoBadRequest(ctx, err)
 	}
 
-	resp, err := h.service.CreateLocation(location)
+	resp, err := h.service.CreateLocation(ctx.Request().Context(), location)
 	if err != nil {
-		log.Error("Failed to create location:", slog.String("error", err.Error()))
+		log.ErrorContext(ctx.Request().Context(), "Failed to create location:", slog.String("error", err.Error()))
 
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to create location",
-		})
+		return echoError(ctx, err)
 	}
 
-	log.Info("Location created successfully")
+	log.InfoContext(ctx.Request().Context(), "Location created successfully")
 
 	return ctx.JSON(http.StatusCreated, resp)
 }
@@ -115,23 +262,19 @@ func (h *LocationHandler) PutLocationId(ctx echo.Context, id locationapi.Id) err
 
 	var location locationapi.LocationUpdate
 	if err := decodeAndValidateEcho(ctx, &location, h.validator); err != nil {
-		log.Error("Failed to decode and validate request:", slog.String("error", err.Error()))
+		log.ErrorContext(ctx.Request().Context(), "Failed to decode and validate request:", slog.String("error", err.Error()))
 
-		return ctx.JSON(http.StatusBadRequest, map[string]string{
-			"error": err.Error(),
-		})
+		return echoBadRequest(ctx, err)
 	}
 
-	resp, err := h.service.UpdateLocation(int(id), location)
+	resp, err := h.service.UpdateLocation(ctx.Request().Context(), int(id), location)
 	if err != nil {
-		log.Error("Failed to update location:", slog.String("error", err.Error()))
+		log.ErrorContext(ctx.Request().Context(), "Failed to update location:", slog.String("error", err.Error()))
 
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to update location",
-		})
+		return echoError(ctx, err)
 	}
 
-	log.Info("Location updated successfully")
+	log.InfoContext(ctx.Request().Context(), "Location updated successfully")
 
 	return ctx.JSON(http.StatusOK, resp)
 }
@@ -143,20 +286,145 @@ func (h *LocationHandler) DeleteLocationId(ctx echo.Context, id locationapi.Id)
 		slog.String("op", op),
 	)
 
-	err := h.service.DeleteLocation(int(id))
+	err := h.service.DeleteLocation(ctx.Request().Context(), int(id))
 	if err != nil {
-		log.Error("Failed to delete location:", slog.String("error", err.Error()))
+		log.ErrorContext(ctx.Request().Context(), "Failed to delete location:", slog.String("error", err.Error()))
 
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to delete location",
-		})
+		return echoError(ctx, err)
 	}
 
-	log.Info("Location deleted successfully")
+	log.InfoContext(ctx.Request().Context(), "Location deleted successfully")
 
 	return ctx.NoContent(http.StatusOK)
 }
 
+func (h *LocationHandler) GetDeletedLocations(ctx echo.Context) error {
+	const op = "rest.Location.getDeleted"
+
+	log := h.log.With(
+		slog.String("op", op),
+	)
+
+	locations, err := h.service.GetDeletedLocations(ctx.Request().Context())
+	if err != nil {
+		log.ErrorContext(ctx.Request().Context(), "Failed to get deleted locations:", slog.String("error", err.Error()))
+
+		return echoError(ctx, err)
+	}
+
+	log.InfoContext(ctx.Request().Context(), "Deleted locations fetched successfully")
+
+	return ctx.JSON(http.StatusOK, locations)
+}
+
+func (h *LocationHandler) PostLocationIdRestore(ctx echo.Context, id locationapi.Id) error {
+	const op = "rest.Location.restore"
+
+	log := h.log.With(
+		slog.String("op", op),
+	)
+
+	location, err := h.service.RestoreLocation(ctx.Request().Context(), int(id))
+	if err != nil {
+		log.ErrorContext(ctx.Request().Context(), "Failed to restore location:", slog.String("error", err.Error()))
+
+		return echoError(ctx, err)
+	}
+
+	log.InfoContext(ctx.Request().Context(), "Location restored successfully")
+
+	return ctx.JSON(http.StatusOK, location)
+}
+
+func (h *LocationHandler) PatchLocationId(ctx echo.Context, id locationapi.Id) error {
+	const op = "rest.Location.patch"
+
+	log := h.log.With(
+		slog.String("op", op),
+	)
+
+	var patch schemas.LocationPatch
+	if err := decodeMergePatch(ctx.Request(), &patch); err != nil {
+		log.ErrorContext(ctx.Request().Context(), "Failed to decode patch:", slog.String("error", err.Error()))
+
+		return echoBadRequest(ctx, err)
+	}
+
+	resp, err := h.service.PatchLocation(ctx.Request().Context(), int(id), patch)
+	if err != nil {
+		log.ErrorContext(ctx.Request().Context(), "Failed to patch location:", slog.String("error", err.Error()))
+
+		return echoError(ctx, err)
+	}
+
+	log.InfoContext(ctx.Request().Context(), "Location patched successfully")
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// parseTimeRange reads the optional RFC 3339 from and to query parameters.
+func parseTimeRange(ctx echo.Context) (time.Time, time.Time, error) {
+	var from, to time.Time
+	for name, dst := range map[string]*time.Time{"from": &from, "to": &to} {
+		value := ctx.QueryParam(name)
+		if value == "" {
+			continue
+		}
+
+		converted, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid %s: %w", name, err)
+		}
+
+		*dst = converted
+	}
+
+	return from, to, nil
+}
+
+// parseLocationFilter reads near as "lat,lon". The radius is only
+// meaningful together with near. Comparisons are negated so that NaN, which
+// fails every comparison, is rejected as well.
+func parseLocationFilter(params locationapi.GetLocationParams) (*schemas.LocationFilter, error) {
+	filter := &schemas.LocationFilter{}
+
+	if params.ParentId != nil {
+		filter.ParentID = *params.ParentId
+	}
+
+	if params.Radius != nil {
+		if params.Near == nil || !(*params.Radius > 0) || math.IsInf(*params.Radius, 1) {
+			return nil, errors.New("radius requires near and must be a positive number")
+		}
+
+		filter.RadiusKm = *params.Radius
+	}
+
+	if params.Near == nil {
+		return filter, nil
+	}
+
+	parts := strings.Split(*params.Near, ",")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid near %q, expected lat,lon", *params.Near)
+	}
+
+	latitude, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil || !(math.Abs(latitude) <= 90) {
+		return nil, fmt.Errorf("invalid near latitude %q", parts[0])
+	}
+
+	longitude, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil || !(math.Abs(longitude) <= 180) {
+		return nil, fmt.Errorf("invalid near longitude %q", parts[1])
+	}
+
+	filter.Latitude = &latitude
+	filter.Longitude = &longitude
+
+	return filter, nil
+}
+
 func NewLocation(log *slog.Logger, service *service.LocationService) *chi.Mux {
 	r := chi.NewRouter()
 
@@ -165,13 +433,39 @@ func NewLocation(log *slog.Logger, service *service.LocationService) *chi.Mux {
 	r.Use(middleware.URLFormat)
 	r.Use(middleware.Logger)
 
-	validate := validator.New()
+	validate := newValidator()
 
 	handler := NewLocationHandler(log, service, validate)
 
 	r.Route("/", func(r chi.Router) {
-		r.Get("/", HandlerAdapter(handler.GetLocation))
+		r.Get("/", HandlerAdapter(func(ctx echo.Context) error {
+			queryParams := ctx.QueryParams()
+
+			params := locationapi.GetLocationParams{}
+			if near := queryParams.Get("near"); near != "" {
+				params.Near = &near
+			}
+
+			if value := queryParams.Get("radius"); value != "" {
+				radius, err := strconv.ParseFloat(value, 64)
+				if err != nil {
+					return echoStatus(ctx, http.StatusBadRequest, "Invalid radius")
+				}
+				params.Radius = &radius
+			}
+
+			if value := queryParams.Get("parent_id"); value != "" {
+				parentId, err := strconv.Atoi(value)
+				if err != nil {
+					return echoStatus(ctx, http.StatusBadRequest, "Invalid parent_id")
+				}
+				params.ParentId = &parentId
+			}
+
+			return handler.GetLocation(ctx, params)
+		}))
 		r.Post("/", HandlerAdapter(handler.PostLocation))
+		r.With(requireAdmin).Get("/deleted", HandlerAdapter(handler.GetDeletedLocations))
 
 		r.Route("/{Id}", func(r chi.Router) {
 			r.Get("/", HandlerAdapter(func(ctx echo.Context) error {
@@ -184,10 +478,51 @@ func NewLocation(log *slog.Logger, service *service.LocationService) *chi.Mux {
 				return handler.PutLocationId(ctx, locationapi.Id(id))
 			}))
 
+			r.Patch("/", HandlerAdapter(func(ctx echo.Context) error {
+				id, _ := strconv.Atoi(ctx.Param("Id"))
+				return handler.PatchLocationId(ctx, locationapi.Id(id))
+			}))
+
 			r.Delete("/", HandlerAdapter(func(ctx echo.Context) error {
 				id, _ := strconv.Atoi(ctx.Param("Id"))
 				return handler.DeleteLocationId(ctx, locationapi.Id(id))
 			}))
+
+			r.With(requireAdmin).Post("/restore", HandlerAdapter(func(ctx echo.Context) error {
+				id, _ := strconv.Atoi(ctx.Param("Id"))
+				return handler.PostLocationIdRestore(ctx, locationapi.Id(id))
+			}))
+
+			r.Get("/agenda", HandlerAdapter(func(ctx echo.Context) error {
+				id, _ := strconv.Atoi(ctx.Param("Id"))
+				return handler.GetLocationIdAgenda(ctx, locationapi.Id(id))
+			}))
+
+			r.Get("/availability", HandlerAdapter(func(ctx echo.Context) error {
+				id, _ := strconv.Atoi(ctx.Param("Id"))
+				return handler.GetLocationIdAvailability(ctx, locationapi.Id(id))
+			}))
+
+			r.Route("/bookings", func(r chi.Router) {
+				r.Get("/", HandlerAdapter(func(ctx echo.Context) error {
+					id, _ := strconv.Atoi(ctx.Param("Id"))
+					return handler.GetLocationIdBookings(ctx, locationapi.Id(id))
+				}))
+
+				r.Post("/", HandlerAdapter(func(ctx echo.Context) error {
+					id, _ := strconv.Atoi(ctx.Param("Id"))
+					return handler.PostLocationIdBookings(ctx, locationapi.Id(id))
+				}))
+
+				r.Delete("/{bookingId}", HandlerAdapter(func(ctx echo.Context) error {
+					id, _ := strconv.Atoi(ctx.Param("Id"))
+					bookingId, err := strconv.Atoi(ctx.Param("bookingId"))
+					if err != nil {
+						return echoStatus(ctx, http.StatusBadRequest, "Invalid booking ID")
+					}
+					return handler.DeleteLocationIdBookingsBookingId(ctx, locationapi.Id(id), bookingId)
+				}))
+			})
 		})
 	})
 
@@ -0,0 +1,52 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"event_service/internal/schemas"
+	"github.com/go-playground/validator/v10"
+	"mime"
+	"net/http"
+	"reflect"
+	"time"
+)
+
+const mergePatchContentType = "application/merge-patch+json"
+
+var errUnsupportedPatchType = errors.New("PATCH expects " + mergePatchContentType)
+
+var patchValidator = newPatchValidator()
+
+func newPatchValidator() *validator.Validate {
+	validate := newValidator()
+
+	validate.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		if value, ok := field.Interface().(schemas.PatchField); ok {
+			return value.ValidationValue()
+		}
+
+		return nil
+	},
+		schemas.Optional[string]{}, schemas.Optional[int]{}, schemas.Optional[bool]{}, schemas.Optional[time.Time]{},
+		schemas.Nullable[string]{}, schemas.Nullable[int]{}, schemas.Nullable[float64]{}, schemas.Nullable[time.Time]{},
+	)
+
+	return validate
+}
+
+// decodeMergePatch reads an RFC 7396 document. Plain application/json is
+// accepted as well for clients that cannot set a custom content type.
+func decodeMergePatch(r *http.Request, dst interface{}) error {
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || (mediaType != mergePatchContentType && mediaType != "application/json") {
+			return errUnsupportedPatchType
+		}
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(dst); err != nil {
+		return err
+	}
+
+	return patchValidator.Struct(dst)
+}
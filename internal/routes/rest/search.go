@@ -0,0 +1,130 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"event_service/internal/schemas"
+	"event_service/internal/service"
+	"fmt"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type SearchService interface {
+	Search(ctx context.Context, filter *schemas.SearchFilter) ([]*schemas.SearchResult, error)
+}
+
+func NewSearch(log *slog.Logger, service *service.SearchService) *chi.Mux {
+	r := chi.NewRouter()
+
+	r.Use(middleware.RequestID)
+	r.Use(middleware.Recoverer)
+	r.Use(middleware.URLFormat)
+	r.Use(middleware.Logger)
+
+	r.Route("/", func(r chi.Router) {
+		r.Get("/", searchHandler(log, service))
+	})
+
+	return r
+}
+
+func searchHandler(log *slog.Logger, service SearchService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "rest.Search.search"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		filter, err := parseSearchFilter(r.URL.Query())
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid search filter:", slog.String("error", err.Error()))
+
+			writeBadRequest(w, r, err)
+			return
+		}
+
+		results, err := service.Search(r.Context(), filter)
+		if err != nil {
+			log.ErrorContext(r.Context(), "Failed to search:", slog.String("error", err.Error()))
+
+			writeError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(results); err != nil {
+			log.ErrorContext(r.Context(), "Failed to encode response:", slog.String("error", err.Error()))
+			return
+		}
+
+		log.InfoContext(r.Context(), "Search completed successfully", slog.Int("results", len(results)))
+	}
+}
+
+// parseSearchFilter reads q, comma-separated type, status, from, to, limit
+// and offset.
+func parseSearchFilter(query url.Values) (*schemas.SearchFilter, error) {
+	filter := &schemas.SearchFilter{
+		Query:  strings.TrimSpace(query.Get("q")),
+		Status: query.Get("status"),
+	}
+
+	if filter.Query == "" {
+		return nil, errors.New("missing q")
+	}
+
+	if types := query.Get("type"); types != "" {
+		filter.Types = strings.Split(types, ",")
+	}
+
+	ints := map[string]*int{
+		"limit":  &filter.Limit,
+		"offset": &filter.Offset,
+	}
+
+	for name, dst := range ints {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+
+		converted, err := strconv.Atoi(value)
+		if err != nil || converted < 0 {
+			return nil, fmt.Errorf("invalid %s: %q", name, value)
+		}
+
+		*dst = converted
+	}
+
+	times := map[string]*time.Time{
+		"from": &filter.From,
+		"to":   &filter.To,
+	}
+
+	for name, dst := range times {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+
+		converted, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", name, err)
+		}
+
+		*dst = converted
+	}
+
+	return filter, nil
+}
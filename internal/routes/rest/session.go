@@ -0,0 +1,478 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"event_service/internal/models"
+	"event_service/internal/schemas"
+	"event_service/internal/service"
+	"fmt"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+type SessionService interface {
+	GetSessions(context.Context, *schemas.SessionFilter) ([]*models.Session, error)
+	GetSessionById(context.Context, int) (*models.Session, error)
+	GetConflicts(context.Context, *schemas.SessionFilter) ([]*schemas.SessionConflict, error)
+	CreateSession(context.Context, schemas.Session) (*models.Session, error)
+	UpdateSession(context.Context, int, schemas.SessionUpdate) (*models.Session, error)
+	PatchSession(context.Context, int, schemas.SessionPatch) (*models.Session, error)
+	DeleteSession(context.Context, int) error
+
+	AddSpeaker(context.Context, int, int) (*models.Session, error)
+	RemoveSpeaker(context.Context, int, int) error
+}
+
+func NewSession(log *slog.Logger, service *service.SessionService) *chi.Mux {
+	r := chi.NewRouter()
+
+	r.Use(middleware.RequestID)
+	r.Use(middleware.Recoverer)
+	r.Use(middleware.URLFormat)
+	r.Use(middleware.Logger)
+
+	validate := newValidator()
+
+	r.Route("/", func(r chi.Router) {
+		r.Get("/", getSessionsHandler(log, service))
+		r.Post("/", createSessionHandler(log, service, validate))
+		r.Get("/conflicts", getSessionConflictsHandler(log, service))
+
+		r.Route("/{id}", func(r chi.Router) {
+			r.Get("/", getSessionByIDHandler(log, service))
+			r.Put("/", updateSessionHandler(log, service, validate))
+			r.Patch("/", patchSessionHandler(log, service))
+			r.Delete("/", deleteSessionHandler(log, service))
+
+			r.Post("/speaker", addSpeakerToSessionHandler(log, service, validate))
+			r.Delete("/speaker/{speakerId}", removeSpeakerFromSessionHandler(log, service))
+		})
+	})
+
+	return r
+}
+
+func getSessionsHandler(log *slog.Logger, service SessionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "rest.Session.getAll"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		filter, err := parseSessionFilter(r.URL.Query())
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid session filter:", slog.String("error", err.Error()))
+
+			writeBadRequest(w, r, err)
+			return
+		}
+
+		sessions, err := service.GetSessions(r.Context(), filter)
+		if err != nil {
+			log.ErrorContext(r.Context(), "Failed to get sessions:", slog.String("error", err.Error()))
+
+			writeError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(sessions); err != nil {
+			log.ErrorContext(r.Context(), "Failed to encode response:", slog.String("error", err.Error()))
+		}
+
+		log.InfoContext(r.Context(), "Sessions fetched successfully")
+	}
+}
+
+func getSessionConflictsHandler(log *slog.Logger, service SessionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "rest.Session.getConflicts"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		filter, err := parseSessionFilter(r.URL.Query())
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid session filter:", slog.String("error", err.Error()))
+
+			writeBadRequest(w, r, err)
+			return
+		}
+
+		conflicts, err := service.GetConflicts(r.Context(), filter)
+		if err != nil {
+			log.ErrorContext(r.Context(), "Failed to get session conflicts:", slog.String("error", err.Error()))
+
+			writeError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(conflicts); err != nil {
+			log.ErrorContext(r.Context(), "Failed to encode response:", slog.String("error", err.Error()))
+		}
+
+		log.InfoContext(r.Context(), "Session conflicts fetched successfully", slog.Int("count", len(conflicts)))
+	}
+}
+
+func getSessionByIDHandler(log *slog.Logger, service SessionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "rest.Session.getByID"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		sessionId, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid session id:", slog.String("error", err.Error()))
+
+			writeStatus(w, r, http.StatusBadRequest, "Invalid session id")
+			return
+		}
+
+		session, err := service.GetSessionById(r.Context(), sessionId)
+		if err != nil {
+			log.ErrorContext(r.Context(), "Failed to get session:", slog.String("error", err.Error()))
+
+			writeError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(session); err != nil {
+			log.ErrorContext(r.Context(), "Failed to encode response:", slog.String("error", err.Error()))
+		}
+
+		log.InfoContext(r.Context(), "Session fetched successfully")
+	}
+}
+
+func createSessionHandler(log *slog.Logger, service SessionService, validate *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "rest.Session.create"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var session schemas.Session
+		if err := DecodeAndValidate(r, &session, validate); err != nil {
+			log.ErrorContext(r.Context(), "Failed to decode request:", slog.String("error", err.Error()))
+
+			writeBadRequest(w, r, err)
+			return
+		}
+
+		resp, err := service.CreateSession(r.Context(), session)
+		if err != nil {
+			log.ErrorContext(r.Context(), "Failed to create session:", slog.String("error", err.Error()))
+
+			writeError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			log.ErrorContext(r.Context(), "Failed to encode response:", slog.String("error", err.Error()))
+		}
+
+		log.InfoContext(r.Context(), "Session created successfully")
+	}
+}
+
+func updateSessionHandler(log *slog.Logger, service SessionService, validate *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "rest.Session.update"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		sessionId, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid session id:", slog.String("error", err.Error()))
+
+			writeStatus(w, r, http.StatusBadRequest, "Invalid session id")
+			return
+		}
+
+		var session schemas.SessionUpdate
+		if err := DecodeAndValidate(r, &session, validate); err != nil {
+			log.ErrorContext(r.Context(), "Failed to decode request:", slog.String("error", err.Error()))
+
+			writeBadRequest(w, r, err)
+			return
+		}
+
+		resp, err := service.UpdateSession(r.Context(), sessionId, session)
+		if err != nil {
+			log.ErrorContext(r.Context(), "Failed to update session:", slog.String("error", err.Error()))
+
+			writeError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			log.ErrorContext(r.Context(), "Failed to encode response:", slog.String("error", err.Error()))
+		}
+
+		log.InfoContext(r.Context(), "Session updated successfully")
+	}
+}
+
+func patchSessionHandler(log *slog.Logger, service SessionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "rest.Session.patch"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		sessionId, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid session id:", slog.String("error", err.Error()))
+
+			writeStatus(w, r, http.StatusBadRequest, "Invalid session id")
+			return
+		}
+
+		var patch schemas.SessionPatch
+		if err := decodeMergePatch(r, &patch); err != nil {
+			log.ErrorContext(r.Context(), "Failed to decode patch:", slog.String("error", err.Error()))
+
+			writeBadRequest(w, r, err)
+			return
+		}
+
+		resp, err := service.PatchSession(r.Context(), sessionId, patch)
+		if err != nil {
+			log.ErrorContext(r.Context(), "Failed to patch session:", slog.String("error", err.Error()))
+
+			writeError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			log.ErrorContext(r.Context(), "Failed to encode response:", slog.String("error", err.Error()))
+		}
+
+		log.InfoContext(r.Context(), "Session patched successfully")
+	}
+}
+
+func deleteSessionHandler(log *slog.Logger, service SessionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "rest.Session.delete"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		sessionId, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid session id:", slog.String("error", err.Error()))
+
+			writeStatus(w, r, http.StatusBadRequest, "Invalid session id")
+			return
+		}
+
+		if err := service.DeleteSession(r.Context(), sessionId); err != nil {
+			log.ErrorContext(r.Context(), "Failed to delete session:", slog.String("error", err.Error()))
+
+			writeError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		log.InfoContext(r.Context(), "Session deleted successfully")
+	}
+}
+
+func addSpeakerToSessionHandler(log *slog.Logger, service SessionService, validate *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "rest.Session.addSpeaker"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		sessionId, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid session id:", slog.String("error", err.Error()))
+
+			writeStatus(w, r, http.StatusBadRequest, "Invalid session id")
+			return
+		}
+
+		var speaker schemas.SessionSpeaker
+		if err := DecodeAndValidate(r, &speaker, validate); err != nil {
+			log.ErrorContext(r.Context(), "Failed to decode request:", slog.String("error", err.Error()))
+
+			writeBadRequest(w, r, err)
+			return
+		}
+
+		resp, err := service.AddSpeaker(r.Context(), sessionId, speaker.SpeakerID)
+		if err != nil {
+			log.ErrorContext(r.Context(), "Failed to add speaker to session:", slog.String("error", err.Error()))
+
+			writeError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			log.ErrorContext(r.Context(), "Failed to encode response:", slog.String("error", err.Error()))
+		}
+
+		log.InfoContext(r.Context(), "Speaker added to session successfully")
+	}
+}
+
+func removeSpeakerFromSessionHandler(log *slog.Logger, service SessionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "rest.Session.removeSpeaker"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		sessionId, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid session id:", slog.String("error", err.Error()))
+
+			writeStatus(w, r, http.StatusBadRequest, "Invalid session id")
+			return
+		}
+
+		speakerId, err := strconv.Atoi(chi.URLParam(r, "speakerId"))
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid speaker id:", slog.String("error", err.Error()))
+
+			writeStatus(w, r, http.StatusBadRequest, "Invalid speaker id")
+			return
+		}
+
+		if err := service.RemoveSpeaker(r.Context(), sessionId, speakerId); err != nil {
+			log.ErrorContext(r.Context(), "Failed to remove speaker from session:", slog.String("error", err.Error()))
+
+			writeError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		log.InfoContext(r.Context(), "Speaker removed from session successfully")
+	}
+}
+
+// agendaHandler serves the sessions of a parent entity, it is mounted by the
+// routers of the entities that expose an agenda.
+func agendaHandler(log *slog.Logger, kind string, getAgenda func(context.Context, int) ([]*models.Session, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "rest.Agenda.get"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("kind", kind),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid id:", slog.String("error", err.Error()))
+
+			writeStatus(w, r, http.StatusBadRequest, "Invalid id")
+			return
+		}
+
+		sessions, err := getAgenda(r.Context(), id)
+		if err != nil {
+			log.ErrorContext(r.Context(), "Failed to get agenda:", slog.String("error", err.Error()))
+
+			writeError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(sessions); err != nil {
+			log.ErrorContext(r.Context(), "Failed to encode response:", slog.String("error", err.Error()))
+		}
+
+		log.InfoContext(r.Context(), "Agenda fetched successfully")
+	}
+}
+
+func parseSessionFilter(query url.Values) (*schemas.SessionFilter, error) {
+	filter := &schemas.SessionFilter{}
+
+	ints := map[string]*int{
+		"event_id":    &filter.EventID,
+		"track_id":    &filter.TrackID,
+		"location_id": &filter.LocationID,
+	}
+
+	for name, dst := range ints {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+
+		converted, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", name, err)
+		}
+
+		*dst = converted
+	}
+
+	times := map[string]*time.Time{
+		"from": &filter.From,
+		"to":   &filter.To,
+	}
+
+	for name, dst := range times {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+
+		converted, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", name, err)
+		}
+
+		*dst = converted
+	}
+
+	return filter, nil
+}
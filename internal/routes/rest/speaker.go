@@ -0,0 +1,261 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"event_service/internal/models"
+	"event_service/internal/schemas"
+	"event_service/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type SpeakerService interface {
+	GetAllSpeakers(context.Context) ([]*models.Speaker, error)
+	GetSpeakerById(context.Context, int) (*models.Speaker, error)
+	CreateSpeaker(context.Context, schemas.Speaker) (*models.Speaker, error)
+	UpdateSpeaker(context.Context, int, schemas.SpeakerUpdate) (*models.Speaker, error)
+	PatchSpeaker(context.Context, int, schemas.SpeakerPatch) (*models.Speaker, error)
+	DeleteSpeaker(context.Context, int) error
+}
+
+func NewSpeaker(log *slog.Logger, service *service.SpeakerService) *chi.Mux {
+	r := chi.NewRouter()
+
+	r.Use(middleware.RequestID)
+	r.Use(middleware.Recoverer)
+	r.Use(middleware.URLFormat)
+	r.Use(middleware.Logger)
+
+	validate := newValidator()
+
+	r.Route("/", func(r chi.Router) {
+		r.Get("/", getAllSpeakersHandler(log, service))
+		r.Post("/", createSpeakerHandler(log, service, validate))
+
+		r.Route("/{id}", func(r chi.Router) {
+			r.Get("/", getSpeakerByIDHandler(log, service))
+			r.Put("/", updateSpeakerHandler(log, service, validate))
+			r.Patch("/", patchSpeakerHandler(log, service))
+			r.Delete("/", deleteSpeakerHandler(log, service))
+		})
+	})
+
+	return r
+}
+
+func getAllSpeakersHandler(log *slog.Logger, service SpeakerService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "rest.Speaker.getAll"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		speakers, err := service.GetAllSpeakers(r.Context())
+		if err != nil {
+			log.ErrorContext(r.Context(), "Failed to get speakers:", slog.String("error", err.Error()))
+
+			writeError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(speakers); err != nil {
+			log.ErrorContext(r.Context(), "Failed to encode response:", slog.String("error", err.Error()))
+		}
+
+		log.InfoContext(r.Context(), "Speakers fetched successfully")
+	}
+}
+
+func getSpeakerByIDHandler(log *slog.Logger, service SpeakerService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "rest.Speaker.getByID"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		speakerId, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid speaker id:", slog.String("error", err.Error()))
+
+			writeStatus(w, r, http.StatusBadRequest, "Invalid speaker id")
+			return
+		}
+
+		speaker, err := service.GetSpeakerById(r.Context(), speakerId)
+		if err != nil {
+			log.ErrorContext(r.Context(), "Failed to get speaker:", slog.String("error", err.Error()))
+
+			writeError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(speaker); err != nil {
+			log.ErrorContext(r.Context(), "Failed to encode response:", slog.String("error", err.Error()))
+		}
+
+		log.InfoContext(r.Context(), "Speaker fetched successfully")
+	}
+}
+
+func createSpeakerHandler(log *slog.Logger, service SpeakerService, validate *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "rest.Speaker.create"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var speaker schemas.Speaker
+		if err := DecodeAndValidate(r, &speaker, validate); err != nil {
+			log.ErrorContext(r.Context(), "Failed to decode request:", slog.String("error", err.Error()))
+
+			writeBadRequest(w, r, err)
+			return
+		}
+
+		resp, err := service.CreateSpeaker(r.Context(), speaker)
+		if err != nil {
+			log.ErrorContext(r.Context(), "Failed to create speaker:", slog.String("error", err.Error()))
+
+			writeError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			log.ErrorContext(r.Context(), "Failed to encode response:", slog.String("error", err.Error()))
+		}
+
+		log.InfoContext(r.Context(), "Speaker created successfully")
+	}
+}
+
+func updateSpeakerHandler(log *slog.Logger, service SpeakerService, validate *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "rest.Speaker.update"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		speakerId, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid speaker id:", slog.String("error", err.Error()))
+
+			writeStatus(w, r, http.StatusBadRequest, "Invalid speaker id")
+			return
+		}
+
+		var speaker schemas.SpeakerUpdate
+		if err := DecodeAndValidate(r, &speaker, validate); err != nil {
+			log.ErrorContext(r.Context(), "Failed to decode request:", slog.String("error", err.Error()))
+
+			writeBadRequest(w, r, err)
+			return
+		}
+
+		resp, err := service.UpdateSpeaker(r.Context(), speakerId, speaker)
+		if err != nil {
+			log.ErrorContext(r.Context(), "Failed to update speaker:", slog.String("error", err.Error()))
+
+			writeError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			log.ErrorContext(r.Context(), "Failed to encode response:", slog.String("error", err.Error()))
+		}
+
+		log.InfoContext(r.Context(), "Speaker updated successfully")
+	}
+}
+
+func patchSpeakerHandler(log *slog.Logger, service SpeakerService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "rest.Speaker.patch"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		speakerId, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid speaker id:", slog.String("error", err.Error()))
+
+			writeStatus(w, r, http.StatusBadRequest, "Invalid speaker id")
+			return
+		}
+
+		var patch schemas.SpeakerPatch
+		if err := decodeMergePatch(r, &patch); err != nil {
+			log.ErrorContext(r.Context(), "Failed to decode patch:", slog.String("error", err.Error()))
+
+			writeBadRequest(w, r, err)
+			return
+		}
+
+		resp, err := service.PatchSpeaker(r.Context(), speakerId, patch)
+		if err != nil {
+			log.ErrorContext(r.Context(), "Failed to patch speaker:", slog.String("error", err.Error()))
+
+			writeError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			log.ErrorContext(r.Context(), "Failed to encode response:", slog.String("error", err.Error()))
+		}
+
+		log.InfoContext(r.Context(), "Speaker patched successfully")
+	}
+}
+
+func deleteSpeakerHandler(log *slog.Logger, service SpeakerService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "rest.Speaker.delete"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		speakerId, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid speaker id:", slog.String("error", err.Error()))
+
+			writeStatus(w, r, http.StatusBadRequest, "Invalid speaker id")
+			return
+		}
+
+		if err := service.DeleteSpeaker(r.Context(), speakerId); err != nil {
+			log.ErrorContext(r.Context(), "Failed to delete speaker:", slog.String("error", err.Error()))
+
+			writeError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		log.InfoContext(r.Context(), "Speaker deleted successfully")
+	}
+}
@@ -1,7 +1,9 @@
 package rest
 
 import (
+	"context"
 	status_api "event_service/gen/status"
+	"event_service/internal/schemas"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-playground/validator/v10"
@@ -12,11 +14,12 @@ import (
 )
 
 type StatusService interface {
-	GetAllStatuses() ([]*status_api.StatusResponse, error)
-	GetStatusById(id int) (*status_api.StatusResponse, error)
-	CreateStatus(date status_api.Status) (*status_api.StatusResponse, error)
-	UpdateStatus(id int, date status_api.StatusUpdate) (*status_api.StatusResponse, error)
-	DeleteStatus(id int) error
+	GetAllStatuses(context.Context) ([]*status_api.StatusResponse, error)
+	GetStatusById(ctx context.Context, id int) (*status_api.StatusResponse, error)
+	CreateStatus(ctx context.Context, date status_api.Status) (*status_api.StatusResponse, error)
+	UpdateStatus(ctx context.Context, id int, date status_api.StatusUpdate) (*status_api.StatusResponse, error)
+	PatchStatus(ctx context.Context, id int, patch schemas.StatusPatch) (*status_api.StatusResponse, error)
+	DeleteStatus(ctx context.Context, id int) error
 }
 
 type StatusHandler struct {
@@ -40,16 +43,14 @@ func (h *StatusHandler) GetStatus(ctx echo.Context) error {
 		slog.String("op", op),
 	)
 
-	statuses, err := h.service.GetAllStatuses()
+	statuses, err := h.service.GetAllStatuses(ctx.Request().Context())
 	if err != nil {
-		log.Error("Failed to get statuses:", slog.String("error", err.Error()))
+		log.ErrorContext(ctx.Request().Context(), "Failed to get statuses:", slog.String("error", err.Error()))
 
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to get statuses",
-		})
+		return echoError(ctx, err)
 	}
 
-	log.Info("Statuses fetched successfully")
+	log.InfoContext(ctx.Request().Context(), "Statuses fetched successfully")
 
 	return ctx.JSON(http.StatusOK, statuses)
 }
@@ -62,16 +63,14 @@ func (h *StatusHandler) GetStatusId(ctx echo.Context, id status_api.Id) error {
 		slog.String("id", strconv.Itoa(id)),
 	)
 
-	status, err := h.service.GetStatusById(int(id))
+	status, err := h.service.GetStatusById(ctx.Request().Context(), int(id))
 	if err != nil {
-		log.Error("Failed to get status:", slog.String("error", err.Error()))
+		log.ErrorContext(ctx.Request().Context(), "Failed to get status:", slog.String("error", err.Error()))
 
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to get status",
-		})
+		return echoError(ctx, err)
 	}
 
-	log.Info("Status fetched successfully")
+	log.InfoContext(ctx.Request().Context(), "Status fetched successfully")
 
 	return ctx.JSON(http.StatusOK, status)
 }
@@ -85,23 +84,19 @@ func (h *StatusHandler) PostStatus(ctx echo.Context) error {
 
 	var status status_api.Status
 	if err := decodeAndValidateEcho(ctx, &status, h.validator); err != nil {
-		log.Error("Failed to decode and validate request:", slog.String("error", err.Error()))
+		log.ErrorContext(ctx.Request().Context(), "Failed to decode and validate request:", slog.String("error", err.Error()))
 
-		return ctx.JSON(http.StatusBadRequest, map[string]string{
-			"error": err.Error(),
-		})
+		return echoBadRequest(ctx, err)
 	}
 
-	resp, err := h.service.CreateStatus(status)
+	resp, err := h.service.CreateStatus(ctx.Request().Context(), status)
 	if err != nil {
-		log.Error("Failed to create status:", slog.String("error", err.Error()))
+		log.ErrorContext(ctx.Request().Context(), "Failed to create status:", slog.String("error", err.Error()))
 
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to create status",
-		})
+		return echoError(ctx, err)
 	}
 
-	log.Info("Status created successfully")
+	log.InfoContext(ctx.Request().Context(), "Status created successfully")
 
 	return ctx.JSON(http.StatusCreated, resp)
 }
@@ -115,23 +110,19 @@ func (h *StatusHandler) PutStatusId(ctx echo.Context, id status_api.Id) error {
 
 	var status status_api.StatusUpdate
 	if err := decodeAndValidateEcho(ctx, &status, h.validator); err != nil {
-		log.Error("Failed to decode and validate request:", slog.String("error", err.Error()))
+		log.ErrorContext(ctx.Request().Context(), "Failed to decode and validate request:", slog.String("error", err.Error()))
 
-		return ctx.JSON(http.StatusBadRequest, map[string]string{
-			"error": err.Error(),
-		})
+		return echoBadRequest(ctx, err)
 	}
 
-	resp, err := h.service.UpdateStatus(int(id), status)
+	resp, err := h.service.UpdateStatus(ctx.Request().Context(), int(id), status)
 	if err != nil {
-		log.Error("Failed to update status:", slog.String("error", err.Error()))
+		log.ErrorContext(ctx.Request().Context(), "Failed to update status:", slog.String("error", err.Error()))
 
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to update status",
-		})
+		return echoError(ctx, err)
 	}
 
-	log.Info("Status updated successfully")
+	log.InfoContext(ctx.Request().Context(), "Status updated successfully")
 
 	return ctx.JSON(http.StatusOK, resp)
 }
@@ -143,20 +134,44 @@ func (h *StatusHandler) DeleteStatusId(ctx echo.Context, id status_api.Id) error
 		slog.String("op", op),
 	)
 
-	err := h.service.DeleteStatus(int(id))
+	err := h.service.DeleteStatus(ctx.Request().Context(), int(id))
 	if err != nil {
-		log.Error("Failed to delete status:", slog.String("error", err.Error()))
+		log.ErrorContext(ctx.Request().Context(), "Failed to delete status:", slog.String("error", err.Error()))
 
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to delete status",
-		})
+		return echoError(ctx, err)
 	}
 
-	log.Info("Status deleted successfully")
+	log.InfoContext(ctx.Request().Context(), "Status deleted successfully")
 
 	return ctx.NoContent(http.StatusOK)
 }
 
+func (h *StatusHandler) PatchStatusId(ctx echo.Context, id status_api.Id) error {
+	const op = "rest.Status.patch"
+
+	log := h.log.With(
+		slog.String("op", op),
+	)
+
+	var patch schemas.StatusPatch
+	if err := decodeMergePatch(ctx.Request(), &patch); err != nil {
+		log.ErrorContext(ctx.Request().Context(), "Failed to decode patch:", slog.String("error", err.Error()))
+
+		return echoBadRequest(ctx, err)
+	}
+
+	resp, err := h.service.PatchStatus(ctx.Request().Context(), int(id), patch)
+	if err != nil {
+		log.ErrorContext(ctx.Request().Context(), "Failed to patch status:", slog.String("error", err.Error()))
+
+		return echoError(ctx, err)
+	}
+
+	log.InfoContext(ctx.Request().Context(), "Status patched successfully")
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
 func NewStatus(log *slog.Logger, service StatusService) *chi.Mux {
 	r := chi.NewRouter()
 
@@ -165,7 +180,7 @@ func NewStatus(log *slog.Logger, service StatusService) *chi.Mux {
 	r.Use(middleware.URLFormat)
 	r.Use(middleware.Logger)
 
-	validate := validator.New()
+	validate := newValidator()
 
 	handler := NewStatusHandler(log, service, validate)
 
@@ -176,7 +191,7 @@ func NewStatus(log *slog.Logger, service StatusService) *chi.Mux {
 		r.Route("/{Id}", func(r chi.Router) {
 			r.Get("/", HandlerAdapter(func(ctx echo.Context) error {
 				id, _ := strconv.Atoi(ctx.Param("Id"))
-				log.Info(ctx.Param("Id"))
+				log.InfoContext(ctx.Request().Context(), ctx.Param("Id"))
 				return handler.GetStatusId(ctx, status_api.Id(id))
 			}))
 
@@ -185,6 +200,11 @@ func NewStatus(log *slog.Logger, service StatusService) *chi.Mux {
 				return handler.PutStatusId(ctx, status_api.Id(id))
 			}))
 
+			r.Patch("/", HandlerAdapter(func(ctx echo.Context) error {
+				id, _ := strconv.Atoi(ctx.Param("Id"))
+				return handler.PatchStatusId(ctx, status_api.Id(id))
+			}))
+
 			r.Delete("/", HandlerAdapter(func(ctx echo.Context) error {
 				id, _ := strconv.Atoi(ctx.Param("Id"))
 				return handler.DeleteStatusId(ctx, status_api.Id(id))
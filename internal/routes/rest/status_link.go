@@ -0,0 +1,155 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	status_api "event_service/gen/status"
+	"event_service/internal/schemas"
+	"fmt"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+// The status link handlers attach status labels to the entity in the {id}
+// path parameter. kind names the entity in logs and errors.
+
+func getStatusesHandler(log *slog.Logger, kind string, getStatuses func(context.Context, int) ([]*status_api.StatusResponse, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := fmt.Sprintf("rest.%s.getStatuses", kind)
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid id:", slog.String("error", err.Error()))
+
+			writeStatus(w, r, http.StatusBadRequest, "Invalid "+kind+" id")
+			return
+		}
+
+		statuses, err := getStatuses(r.Context(), id)
+		if err != nil {
+			log.ErrorContext(r.Context(), "Failed to get statuses:", slog.String("error", err.Error()))
+
+			writeError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(statuses); err != nil {
+			log.ErrorContext(r.Context(), "Failed to encode response:", slog.String("error", err.Error()))
+			return
+		}
+
+		log.InfoContext(r.Context(), "Statuses fetched successfully")
+	}
+}
+
+func addStatusHandler(log *slog.Logger, kind string, validate *validator.Validate,
+	addStatus func(ctx context.Context, id int, statusId int) (interface{}, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := fmt.Sprintf("rest.%s.addStatus", kind)
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid id:", slog.String("error", err.Error()))
+
+			writeStatus(w, r, http.StatusBadRequest, "Invalid "+kind+" id")
+			return
+		}
+
+		var request schemas.StatusLink
+		if err := DecodeAndValidate(r, &request, validate); err != nil {
+			log.ErrorContext(r.Context(), "Failed to decode request:", slog.String("error", err.Error()))
+
+			writeBadRequest(w, r, err)
+			return
+		}
+
+		resp, err := addStatus(r.Context(), id, request.StatusID)
+		if err != nil {
+			log.ErrorContext(r.Context(), "Failed to add status:", slog.String("error", err.Error()))
+
+			writeError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			log.ErrorContext(r.Context(), "Failed to encode response:", slog.String("error", err.Error()))
+			return
+		}
+
+		log.InfoContext(r.Context(), "Status added successfully")
+	}
+}
+
+func removeStatusHandler(log *slog.Logger, kind string,
+	removeStatus func(ctx context.Context, id int, statusId int) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		op := fmt.Sprintf("rest.%s.removeStatus", kind)
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid id:", slog.String("error", err.Error()))
+
+			writeStatus(w, r, http.StatusBadRequest, "Invalid "+kind+" id")
+			return
+		}
+
+		statusId, err := strconv.Atoi(chi.URLParam(r, "statusId"))
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid status id:", slog.String("error", err.Error()))
+
+			writeStatus(w, r, http.StatusBadRequest, "Invalid status id")
+			return
+		}
+
+		if err := removeStatus(r.Context(), id, statusId); err != nil {
+			log.ErrorContext(r.Context(), "Failed to remove status:", slog.String("error", err.Error()))
+
+			writeError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		log.InfoContext(r.Context(), "Status removed successfully")
+	}
+}
+
+// parseStatusFilter reads the lifecycle status and the status_id label
+// filters shared by event and track listings.
+func parseStatusFilter(r *http.Request) (string, int, error) {
+	query := r.URL.Query()
+
+	statusId := 0
+	if value := query.Get("status_id"); value != "" {
+		converted, err := strconv.Atoi(value)
+		if err != nil {
+			return "", 0, fmt.Errorf("invalid status_id: %w", err)
+		}
+
+		statusId = converted
+	}
+
+	return query.Get("status"), statusId, nil
+}
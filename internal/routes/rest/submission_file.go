@@ -0,0 +1,271 @@
+package rest
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const submissionFileField = "file"
+
+var errMissingFile = errors.New("multipart form has no file part")
+
+func getSubmissionFilesHandler(log *slog.Logger, service TeamActionStatusService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "rest.TeamActionStatus.getFiles"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		timelineId, teamId, err := parseTeamActionPath(r)
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid path:", slog.String("error", err.Error()))
+
+			writeBadRequest(w, r, err)
+			return
+		}
+
+		files, err := service.GetSubmissionFiles(r.Context(), timelineId, teamId)
+		if err != nil {
+			log.ErrorContext(r.Context(), "Failed to get submission files:", slog.String("error", err.Error()))
+
+			writeError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(files); err != nil {
+			log.ErrorContext(r.Context(), "Failed to encode response:", slog.String("error", err.Error()))
+		}
+
+		log.InfoContext(r.Context(), "Submission files fetched successfully")
+	}
+}
+
+// uploadSubmissionFileHandler streams the "file" part of a multipart form
+// to the service without buffering the whole body in memory.
+func uploadSubmissionFileHandler(log *slog.Logger, service TeamActionStatusService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "rest.TeamActionStatus.uploadFile"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		timelineId, teamId, err := parseTeamActionPath(r)
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid path:", slog.String("error", err.Error()))
+
+			writeBadRequest(w, r, err)
+			return
+		}
+
+		reader, err := r.MultipartReader()
+		if err != nil {
+			log.ErrorContext(r.Context(), "Failed to read multipart form:", slog.String("error", err.Error()))
+
+			writeBadRequest(w, r, err)
+			return
+		}
+
+		for {
+			part, err := reader.NextPart()
+			if errors.Is(err, io.EOF) {
+				log.ErrorContext(r.Context(), "Failed to read multipart form:", slog.String("error", errMissingFile.Error()))
+
+				writeBadRequest(w, r, errMissingFile)
+				return
+			}
+
+			if err != nil {
+				log.ErrorContext(r.Context(), "Failed to read multipart form:", slog.String("error", err.Error()))
+
+				writeBadRequest(w, r, err)
+				return
+			}
+
+			if part.FormName() != submissionFileField {
+				_ = part.Close()
+				continue
+			}
+
+			resp, err := service.UploadSubmissionFile(r.Context(), timelineId, teamId, part.FileName(), part)
+			_ = part.Close()
+
+			if err != nil {
+				log.ErrorContext(r.Context(), "Failed to upload submission file:", slog.String("error", err.Error()))
+
+				writeError(w, r, err)
+				return
+			}
+
+			w.WriteHeader(http.StatusCreated)
+			if err := json.NewEncoder(w).Encode(resp); err != nil {
+				log.ErrorContext(r.Context(), "Failed to encode response:", slog.String("error", err.Error()))
+			}
+
+			log.InfoContext(r.Context(), "Submission file uploaded successfully")
+			return
+		}
+	}
+}
+
+func deleteSubmissionFileHandler(log *slog.Logger, service TeamActionStatusService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "rest.TeamActionStatus.deleteFile"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		timelineId, teamId, err := parseTeamActionPath(r)
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid path:", slog.String("error", err.Error()))
+
+			writeBadRequest(w, r, err)
+			return
+		}
+
+		fileId, err := strconv.Atoi(chi.URLParam(r, "fileId"))
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid file id:", slog.String("error", err.Error()))
+
+			writeStatus(w, r, http.StatusBadRequest, "Invalid file id")
+			return
+		}
+
+		if err := service.DeleteSubmissionFile(r.Context(), timelineId, teamId, fileId); err != nil {
+			log.ErrorContext(r.Context(), "Failed to delete submission file:", slog.String("error", err.Error()))
+
+			writeError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+		log.InfoContext(r.Context(), "Submission file deleted successfully")
+	}
+}
+
+func getSubmissionFileLinkHandler(log *slog.Logger, service TeamActionStatusService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "rest.TeamActionStatus.getFileLink"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		timelineId, teamId, err := parseTeamActionPath(r)
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid path:", slog.String("error", err.Error()))
+
+			writeBadRequest(w, r, err)
+			return
+		}
+
+		fileId, err := strconv.Atoi(chi.URLParam(r, "fileId"))
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid file id:", slog.String("error", err.Error()))
+
+			writeStatus(w, r, http.StatusBadRequest, "Invalid file id")
+			return
+		}
+
+		link, err := service.GetSubmissionFileLink(r.Context(), timelineId, teamId, fileId)
+		if err != nil {
+			log.ErrorContext(r.Context(), "Failed to issue download link:", slog.String("error", err.Error()))
+
+			writeError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(link); err != nil {
+			log.ErrorContext(r.Context(), "Failed to encode response:", slog.String("error", err.Error()))
+		}
+
+		log.InfoContext(r.Context(), "Download link issued successfully")
+	}
+}
+
+func downloadSubmissionFileHandler(log *slog.Logger, service TeamActionStatusService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "rest.TeamActionStatus.downloadFile"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		fileId, err := strconv.Atoi(chi.URLParam(r, "fileId"))
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid file id:", slog.String("error", err.Error()))
+
+			writeStatus(w, r, http.StatusBadRequest, "Invalid file id")
+			return
+		}
+
+		expires, err := strconv.ParseInt(r.URL.Query().Get("expires"), 10, 64)
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid expires:", slog.String("error", err.Error()))
+
+			writeStatus(w, r, http.StatusBadRequest, "Invalid expires")
+			return
+		}
+
+		file, content, err := service.OpenSubmissionFile(r.Context(), fileId, time.Unix(expires, 0), r.URL.Query().Get("signature"))
+		if err != nil {
+			log.ErrorContext(r.Context(), "Failed to open submission file:", slog.String("error", err.Error()))
+
+			writeError(w, r, err)
+			return
+		}
+		defer content.Close()
+
+		w.Header().Set("Content-Type", file.ContentType)
+		w.Header().Set("Content-Length", strconv.FormatInt(file.Size, 10))
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": file.FileName}))
+		w.Header().Set("Cache-Control", "private, no-store")
+		w.Header().Set("ETag", fmt.Sprintf("%q", file.SHA256))
+
+		if sum, err := hex.DecodeString(file.SHA256); err == nil {
+			w.Header().Set("Digest", "sha-256="+base64.StdEncoding.EncodeToString(sum))
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if _, err := io.Copy(w, content); err != nil {
+			log.ErrorContext(r.Context(), "Failed to stream submission file:", slog.String("error", err.Error()))
+			return
+		}
+
+		log.InfoContext(r.Context(), "Submission file downloaded successfully")
+	}
+}
+
+func parseTeamActionPath(r *http.Request) (int, int, error) {
+	timelineId, err := strconv.Atoi(chi.URLParam(r, "timelineId"))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid timeline id: %w", err)
+	}
+
+	teamId, err := strconv.Atoi(chi.URLParam(r, "teamId"))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid team id: %w", err)
+	}
+
+	return timelineId, teamId, nil
+}
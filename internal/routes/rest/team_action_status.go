@@ -1,6 +1,7 @@
 package rest
 
 import (
+	"context"
 	"encoding/json"
 	"event_service/internal/models"
 	"event_service/internal/schemas"
@@ -9,18 +10,30 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-playground/validator/v10"
+	"io"
 	"log/slog"
 	"net/http"
 	"strconv"
+	"time"
 )
 
 type TeamActionStatusService interface {
-	GetTeamActionStatusByTeamId(int) ([]*models.TeamActionStatus, error)
-	GetTeamActionStatusByTimelineId(int) ([]*models.TeamActionStatus, error)
-	GetTeamActionStatus(int, int) (*models.TeamActionStatus, error)
-	CreateTeamActionStatus(*schemas.TeamActionStatus) (*models.TeamActionStatus, error)
-	UpdateTeamActionStatus(int, int, *schemas.TeamActionStatusUpdate) (*models.TeamActionStatus, error)
-	DeleteTeamActionStatus(int, int) error
+	GetTeamActionStatusByTeamId(context.Context, int) ([]*models.TeamActionStatus, error)
+	GetTeamActionStatusByTimelineId(context.Context, int) ([]*models.TeamActionStatus, error)
+	GetTeamActionStatus(context.Context, int, int) (*models.TeamActionStatus, error)
+	CreateTeamActionStatus(context.Context, *schemas.TeamActionStatus) (*models.TeamActionStatus, error)
+	UpdateTeamActionStatus(context.Context, int, int, *schemas.TeamActionStatusUpdate) (*models.TeamActionStatus, error)
+	PatchTeamActionStatus(context.Context, int, int, schemas.TeamActionStatusPatch) (*models.TeamActionStatus, error)
+	DeleteTeamActionStatus(context.Context, int, int) error
+
+	GetTeamActionStatusRevisions(context.Context, int, int) ([]*models.TeamActionStatusRevision, error)
+	DiffTeamActionStatusRevisions(context.Context, int, int, int, int) (*schemas.TeamActionStatusRevisionDiff, error)
+
+	GetSubmissionFiles(context.Context, int, int) ([]*models.SubmissionFile, error)
+	UploadSubmissionFile(context.Context, int, int, string, io.Reader) (*models.SubmissionFile, error)
+	DeleteSubmissionFile(context.Context, int, int, int) error
+	GetSubmissionFileLink(context.Context, int, int, int) (*schemas.SubmissionLink, error)
+	OpenSubmissionFile(context.Context, int, time.Time, string) (*models.SubmissionFile, io.ReadCloser, error)
 }
 
 func NewTeamActionStatus(log *slog.Logger, service *service.TeamActionStatusService) *chi.Mux {
@@ -31,16 +44,28 @@ func NewTeamActionStatus(log *slog.Logger, service *service.TeamActionStatusServ
 	r.Use(middleware.URLFormat)
 	r.Use(middleware.Logger)
 
-	validate := validator.New()
+	validate := newValidator()
 
 	r.Route("/", func(r chi.Router) {
 		r.Get("/", getTeamActionStatusHandler(log, service))
 		r.Post("/", createTeamActionStatusHandler(log, service, validate))
+		r.Get("/files/{fileId}/download", downloadSubmissionFileHandler(log, service))
 
 		r.Route("/{timelineId}/{teamId}", func(r chi.Router) {
 			r.Get("/", getTeamActionStatusByIdHandler(log, service))
 			r.Put("/", updateTeamActionStatusHandler(log, service, validate))
+			r.Patch("/", patchTeamActionStatusHandler(log, service))
 			r.Delete("/", deleteTeamActionStatusHandler(log, service))
+
+			r.Get("/revisions", getTeamActionStatusRevisionsHandler(log, service))
+			r.Get("/revisions/diff", diffTeamActionStatusRevisionsHandler(log, service))
+
+			r.Route("/files", func(r chi.Router) {
+				r.Get("/", getSubmissionFilesHandler(log, service))
+				r.Post("/", uploadSubmissionFileHandler(log, service))
+				r.Delete("/{fileId}", deleteSubmissionFileHandler(log, service))
+				r.Get("/{fileId}/link", getSubmissionFileLinkHandler(log, service))
+			})
 		})
 	})
 
@@ -60,34 +85,34 @@ func getTeamActionStatusHandler(log *slog.Logger, service TeamActionStatusServic
 
 		convertedHeaders, err := utils.ValidateHeaders(headersList, log, r)
 		if err != nil {
-			log.Error("Failed to validate headers:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to validate headers:", err.Error())
 
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			writeBadRequest(w, r, err)
 			return
 		}
 
 		var result []*models.TeamActionStatus
 		if _, has := headersList["TeamId"]; has {
-			result, err = service.GetTeamActionStatusByTeamId(convertedHeaders["TeamId"].(int))
+			result, err = service.GetTeamActionStatusByTeamId(r.Context(), convertedHeaders["TeamId"].(int))
 		} else {
-			result, err = service.GetTeamActionStatusByTimelineId(convertedHeaders["TimelineId"].(int))
+			result, err = service.GetTeamActionStatusByTimelineId(r.Context(), convertedHeaders["TimelineId"].(int))
 		}
 
 		if err != nil {
-			log.Error("Failed to get TeamActionStatuses:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to get TeamActionStatuses:", err.Error())
 
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, r, err)
 			return
 		}
 
 		w.WriteHeader(http.StatusOK)
 		if err := json.NewEncoder(w).Encode(result); err != nil {
-			log.Error("Failed to encode response:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to encode response:", err.Error())
 
 			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 		}
 
-		log.Info("TeamActionStatus successfully fetched")
+		log.InfoContext(r.Context(), "TeamActionStatus successfully fetched")
 	}
 }
 
@@ -102,28 +127,28 @@ func createTeamActionStatusHandler(log *slog.Logger, service TeamActionStatusSer
 
 		var teamActionStatus schemas.TeamActionStatus
 		if err := DecodeAndValidate(r, &teamActionStatus, validate); err != nil {
-			log.Error("Failed to decode request:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to decode request:", err.Error())
 
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			writeBadRequest(w, r, err)
 			return
 		}
 
-		resp, err := service.CreateTeamActionStatus(&teamActionStatus)
+		resp, err := service.CreateTeamActionStatus(r.Context(), &teamActionStatus)
 		if err != nil {
-			log.Error("Failed to create teamActionStatus:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to create teamActionStatus:", err.Error())
 
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, r, err)
 			return
 		}
 
 		w.WriteHeader(http.StatusCreated)
 		if err := json.NewEncoder(w).Encode(resp); err != nil {
-			log.Error("Failed to encode response:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to encode response:", err.Error())
 
 			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 		}
 
-		log.Info("TeamActionStatus created successfully")
+		log.InfoContext(r.Context(), "TeamActionStatus created successfully")
 	}
 }
 
@@ -139,22 +164,22 @@ func getTeamActionStatusByIdHandler(log *slog.Logger, service TeamActionStatusSe
 		timelineId, _ := strconv.Atoi(chi.URLParam(r, "timelineId"))
 		teamId, _ := strconv.Atoi(chi.URLParam(r, "teamId"))
 
-		teamActionStatus, err := service.GetTeamActionStatus(timelineId, teamId)
+		teamActionStatus, err := service.GetTeamActionStatus(r.Context(), timelineId, teamId)
 		if err != nil {
-			log.Error("Failed to get teamActionStatus by id:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to get teamActionStatus by id:", err.Error())
 
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, r, err)
 			return
 		}
 
 		w.WriteHeader(http.StatusCreated)
 		if err := json.NewEncoder(w).Encode(teamActionStatus); err != nil {
-			log.Error("Failed to encode response:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to encode response:", err.Error())
 
 			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 		}
 
-		log.Info("TeamActionStatus successfully created")
+		log.InfoContext(r.Context(), "TeamActionStatus successfully created")
 	}
 }
 
@@ -172,28 +197,28 @@ func updateTeamActionStatusHandler(log *slog.Logger, service TeamActionStatusSer
 
 		var teamActionStatus schemas.TeamActionStatusUpdate
 		if err := DecodeAndValidate(r, &teamActionStatus, validate); err != nil {
-			log.Error("Failed to decode request:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to decode request:", err.Error())
 
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			writeBadRequest(w, r, err)
 			return
 		}
 
-		resp, err := service.UpdateTeamActionStatus(timelineId, teamId, &teamActionStatus)
+		resp, err := service.UpdateTeamActionStatus(r.Context(), timelineId, teamId, &teamActionStatus)
 		if err != nil {
-			log.Error("Failed to update TeamActionStatus:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to update TeamActionStatus:", err.Error())
 
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, r, err)
 			return
 		}
 
 		w.WriteHeader(http.StatusOK)
 		if err := json.NewEncoder(w).Encode(resp); err != nil {
-			log.Error("Failed to encode response:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to encode response:", err.Error())
 
 			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 		}
 
-		log.Info("TeamActionStatus updated successfully")
+		log.InfoContext(r.Context(), "TeamActionStatus updated successfully")
 	}
 }
 
@@ -209,14 +234,64 @@ func deleteTeamActionStatusHandler(log *slog.Logger, service TeamActionStatusSer
 		timelineId, _ := strconv.Atoi(chi.URLParam(r, "timelineId"))
 		teamId, _ := strconv.Atoi(chi.URLParam(r, "teamId"))
 
-		err := service.DeleteTeamActionStatus(timelineId, teamId)
+		err := service.DeleteTeamActionStatus(r.Context(), timelineId, teamId)
 		if err != nil {
-			log.Error("Failed to delete TeamActionStatus:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to delete TeamActionStatus:", err.Error())
 
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, r, err)
 			return
 		}
 
 		w.WriteHeader(http.StatusOK)
 	}
 }
+
+func patchTeamActionStatusHandler(log *slog.Logger, service TeamActionStatusService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "rest.TeamActionStatus.patch"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		timelineId, err := strconv.Atoi(chi.URLParam(r, "timelineId"))
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid timeline id:", slog.String("error", err.Error()))
+
+			writeStatus(w, r, http.StatusBadRequest, "Invalid timeline id")
+			return
+		}
+
+		teamId, err := strconv.Atoi(chi.URLParam(r, "teamId"))
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid team id:", slog.String("error", err.Error()))
+
+			writeStatus(w, r, http.StatusBadRequest, "Invalid team id")
+			return
+		}
+
+		var patch schemas.TeamActionStatusPatch
+		if err := decodeMergePatch(r, &patch); err != nil {
+			log.ErrorContext(r.Context(), "Failed to decode patch:", slog.String("error", err.Error()))
+
+			writeBadRequest(w, r, err)
+			return
+		}
+
+		resp, err := service.PatchTeamActionStatus(r.Context(), timelineId, teamId, patch)
+		if err != nil {
+			log.ErrorContext(r.Context(), "Failed to patch TeamActionStatus:", slog.String("error", err.Error()))
+
+			writeError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			log.ErrorContext(r.Context(), "Failed to encode response:", slog.String("error", err.Error()))
+		}
+
+		log.InfoContext(r.Context(), "TeamActionStatus patched successfully")
+	}
+}
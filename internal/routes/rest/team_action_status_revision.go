@@ -0,0 +1,94 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/go-chi/chi/v5/middleware"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+func getTeamActionStatusRevisionsHandler(log *slog.Logger, service TeamActionStatusService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "rest.TeamActionStatus.getRevisions"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		timelineId, teamId, err := parseTeamActionPath(r)
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid path:", slog.String("error", err.Error()))
+
+			writeBadRequest(w, r, err)
+			return
+		}
+
+		revisions, err := service.GetTeamActionStatusRevisions(r.Context(), timelineId, teamId)
+		if err != nil {
+			log.ErrorContext(r.Context(), "Failed to get revisions:", slog.String("error", err.Error()))
+
+			writeError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(revisions); err != nil {
+			log.ErrorContext(r.Context(), "Failed to encode response:", slog.String("error", err.Error()))
+		}
+
+		log.InfoContext(r.Context(), "Revisions fetched successfully")
+	}
+}
+
+// diffTeamActionStatusRevisionsHandler compares the revisions given by the
+// "from" and "to" query parameters.
+func diffTeamActionStatusRevisionsHandler(log *slog.Logger, service TeamActionStatusService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "rest.TeamActionStatus.diffRevisions"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		timelineId, teamId, err := parseTeamActionPath(r)
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid path:", slog.String("error", err.Error()))
+
+			writeBadRequest(w, r, err)
+			return
+		}
+
+		revisions := make(map[string]int, 2)
+		for _, name := range []string{"from", "to"} {
+			revision, err := strconv.Atoi(r.URL.Query().Get(name))
+			if err != nil || revision <= 0 {
+				err = fmt.Errorf("invalid %s revision: %q", name, r.URL.Query().Get(name))
+				log.ErrorContext(r.Context(), "Invalid query:", slog.String("error", err.Error()))
+
+				writeBadRequest(w, r, err)
+				return
+			}
+
+			revisions[name] = revision
+		}
+
+		diff, err := service.DiffTeamActionStatusRevisions(r.Context(), timelineId, teamId, revisions["from"], revisions["to"])
+		if err != nil {
+			log.ErrorContext(r.Context(), "Failed to diff revisions:", slog.String("error", err.Error()))
+
+			writeError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(diff); err != nil {
+			log.ErrorContext(r.Context(), "Failed to encode response:", slog.String("error", err.Error()))
+		}
+
+		log.InfoContext(r.Context(), "Revisions diffed successfully")
+	}
+}
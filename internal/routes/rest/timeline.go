@@ -1,8 +1,12 @@
 package rest
 
 import (
+	"context"
 	timeline_api "event_service/gen/timeline"
+	"event_service/internal/models"
+	"event_service/internal/schemas"
 	"event_service/internal/service"
+	"event_service/pkg/http/utils"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-playground/validator/v10"
@@ -13,15 +17,23 @@ import (
 )
 
 type TimelineService interface {
-	GetAllTimelines(trackId int) ([]*timeline_api.TimelineResponse, error)
-	GetAllTimelinesWithStatus(int, string) ([]*timeline_api.TimelineResponse, error)
-	GetTimelineById(int) (*timeline_api.TimelineResponse, error)
-	CreateTimeline(*timeline_api.Timeline) (*timeline_api.TimelineResponse, error)
-	UpdateTimeline(int, *timeline_api.TimelineUpdate) (*timeline_api.TimelineResponse, error)
-	DeleteTimeline(int) error
-
-	GetAllTimelineStatuses() ([]*timeline_api.TimelineStatusResponse, error)
-	CreateTimelineStatus(response *timeline_api.TimelineStatusResponse) (*timeline_api.TimelineStatusResponse, error)
+	GetAllTimelines(ctx context.Context, trackId int) ([]*timeline_api.TimelineResponse, error)
+	GetAllTimelinesWithStatus(context.Context, int, string) ([]*timeline_api.TimelineResponse, error)
+	GetTimelineById(context.Context, int) (*timeline_api.TimelineResponse, error)
+	CreateTimeline(context.Context, *timeline_api.Timeline) (*timeline_api.TimelineResponse, error)
+	UpdateTimeline(context.Context, int, *timeline_api.TimelineUpdate, int) (*timeline_api.TimelineResponse, error)
+	PatchTimeline(context.Context, int, schemas.TimelinePatch, int) (*timeline_api.TimelineResponse, error)
+	DeleteTimeline(context.Context, int) error
+	GetDeletedTimelines(context.Context) ([]*timeline_api.TimelineResponse, error)
+	RestoreTimeline(context.Context, int) (*timeline_api.TimelineResponse, error)
+
+	GetAllTimelineStatuses(context.Context) ([]*timeline_api.TimelineStatusResponse, error)
+	CreateTimelineStatus(ctx context.Context, response *timeline_api.TimelineStatusResponse) (*timeline_api.TimelineStatusResponse, error)
+
+	GetTimelineOverrides(ctx context.Context, timelineId int) ([]*models.TimelineOverride, error)
+	SetTimelineOverride(ctx context.Context, timelineId int, trackTeamId int, override schemas.TimelineOverride) (*models.TimelineOverride, error)
+	DeleteTimelineOverride(ctx context.Context, timelineId int, trackTeamId int) error
+	GetTeamTimelines(ctx context.Context, trackTeamId int) ([]*schemas.TeamTimeline, error)
 }
 
 type TimelineHandler struct {
@@ -46,10 +58,8 @@ func (h *TimelineHandler) GetTimeline(ctx echo.Context, params timeline_api.GetT
 	)
 
 	if params.XTrackId == nil {
-		log.Error("Missing required header: XTrackId")
-		return ctx.JSON(http.StatusBadRequest, map[string]string{
-			"error": "Missing required header: XTrackId",
-		})
+		log.ErrorContext(ctx.Request().Context(), "Missing required header: XTrackId")
+		return echoStatus(ctx, http.StatusBadRequest, "Missing required header: XTrackId")
 	}
 
 	trackId := *params.XTrackId
@@ -62,19 +72,17 @@ func (h *TimelineHandler) GetTimeline(ctx echo.Context, params timeline_api.GetT
 	var err error
 
 	if status == "" {
-		result, err = h.service.GetAllTimelines(trackId)
+		result, err = h.service.GetAllTimelines(ctx.Request().Context(), trackId)
 	} else {
-		result, err = h.service.GetAllTimelinesWithStatus(trackId, status)
+		result, err = h.service.GetAllTimelinesWithStatus(ctx.Request().Context(), trackId, status)
 	}
 
 	if err != nil {
-		log.Error("Failed to get timelines:", slog.String("error", err.Error()))
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to get timelines",
-		})
+		log.ErrorContext(ctx.Request().Context(), "Failed to get timelines:", slog.String("error", err.Error()))
+		return echoError(ctx, err)
 	}
 
-	log.Info("Timelines fetched successfully")
+	log.InfoContext(ctx.Request().Context(), "Timelines fetched successfully")
 
 	return ctx.JSON(http.StatusOK, result)
 }
@@ -88,23 +96,19 @@ func (h *TimelineHandler) PostTimeline(ctx echo.Context) error {
 
 	var timeline timeline_api.Timeline
 	if err := decodeAndValidateEcho(ctx, &timeline, h.validator); err != nil {
-		log.Error("Failed to decode and validate request:", slog.String("error", err.Error()))
+		log.ErrorContext(ctx.Request().Context(), "Failed to decode and validate request:", slog.String("error", err.Error()))
 
-		return ctx.JSON(http.StatusBadRequest, map[string]string{
-			"error": err.Error(),
-		})
+		return echoBadRequest(ctx, err)
 	}
 
-	resp, err := h.service.CreateTimeline(&timeline)
+	resp, err := h.service.CreateTimeline(ctx.Request().Context(), &timeline)
 	if err != nil {
-		log.Error("Failed to create timeline:", slog.String("error", err.Error()))
+		log.ErrorContext(ctx.Request().Context(), "Failed to create timeline:", slog.String("error", err.Error()))
 
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to create timeline",
-		})
+		return echoError(ctx, err)
 	}
 
-	log.Info("Timeline created successfully")
+	log.InfoContext(ctx.Request().Context(), "Timeline created successfully")
 
 	return ctx.JSON(http.StatusCreated, resp)
 }
@@ -116,16 +120,21 @@ func (h *TimelineHandler) GetTimelineId(ctx echo.Context, id timeline_api.Id) er
 		slog.String("op", op),
 	)
 
-	timeline, err := h.service.GetTimelineById(int(id))
+	timeline, err := h.service.GetTimelineById(ctx.Request().Context(), int(id))
 	if err != nil {
-		log.Error("Failed to get timeline by id:", slog.String("error", err.Error()))
+		log.ErrorContext(ctx.Request().Context(), "Failed to get timeline by id:", slog.String("error", err.Error()))
 
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to get timeline",
-		})
+		return echoError(ctx, err)
 	}
 
-	log.Info("Timeline fetched successfully")
+	etag := utils.ETag(*timeline.Version)
+	ctx.Response().Header().Set("ETag", etag)
+
+	if utils.IfNoneMatch(ctx.Request(), etag) {
+		return ctx.NoContent(http.StatusNotModified)
+	}
+
+	log.InfoContext(ctx.Request().Context(), "Timeline fetched successfully")
 
 	return ctx.JSON(http.StatusOK, timeline)
 }
@@ -137,16 +146,14 @@ func (h *TimelineHandler) DeleteTimelineId(ctx echo.Context, id timeline_api.Id)
 		slog.String("op", op),
 	)
 
-	err := h.service.DeleteTimeline(int(id))
+	err := h.service.DeleteTimeline(ctx.Request().Context(), int(id))
 	if err != nil {
-		log.Error("Failed to delete timeline:", slog.String("error", err.Error()))
+		log.ErrorContext(ctx.Request().Context(), "Failed to delete timeline:", slog.String("error", err.Error()))
 
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to delete timeline",
-		})
+		return echoError(ctx, err)
 	}
 
-	log.Info("Timeline deleted successfully")
+	log.InfoContext(ctx.Request().Context(), "Timeline deleted successfully")
 
 	return ctx.NoContent(http.StatusOK)
 }
@@ -158,29 +165,107 @@ func (h *TimelineHandler) PutTimelineId(ctx echo.Context, id timeline_api.Id) er
 		slog.String("op", op),
 	)
 
+	version, err := utils.IfMatchVersion(ctx.Request())
+	if err != nil {
+		log.ErrorContext(ctx.Request().Context(), "Precondition check failed:", slog.String("error", err.Error()))
+
+		return echoError(ctx, err)
+	}
+
 	var timeline timeline_api.TimelineUpdate
 	if err := decodeAndValidateEcho(ctx, &timeline, h.validator); err != nil {
-		log.Error("Failed to decode and validate request:", slog.String("error", err.Error()))
+		log.ErrorContext(ctx.Request().Context(), "Failed to decode and validate request:", slog.String("error", err.Error()))
 
-		return ctx.JSON(http.StatusBadRequest, map[string]string{
-			"error": err.Error(),
-		})
+		return echoBadRequest(ctx, err)
 	}
 
-	resp, err := h.service.UpdateTimeline(int(id), &timeline)
+	resp, err := h.service.UpdateTimeline(ctx.Request().Context(), int(id), &timeline, version)
 	if err != nil {
-		log.Error("Failed to update timeline:", slog.String("error", err.Error()))
+		log.ErrorContext(ctx.Request().Context(), "Failed to update timeline:", slog.String("error", err.Error()))
 
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to update timeline",
-		})
+		return echoError(ctx, err)
+	}
+
+	ctx.Response().Header().Set("ETag", utils.ETag(*resp.Version))
+
+	log.InfoContext(ctx.Request().Context(), "Timeline updated successfully")
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+func (h *TimelineHandler) PatchTimelineId(ctx echo.Context, id timeline_api.Id) error {
+	const op = "rest.Timeline.patch"
+
+	log := h.log.With(
+		slog.String("op", op),
+	)
+
+	version, err := utils.IfMatchVersion(ctx.Request())
+	if err != nil {
+		log.ErrorContext(ctx.Request().Context(), "Precondition check failed:", slog.String("error", err.Error()))
+
+		return echoError(ctx, err)
+	}
+
+	var patch schemas.TimelinePatch
+	if err := decodeMergePatch(ctx.Request(), &patch); err != nil {
+		log.ErrorContext(ctx.Request().Context(), "Failed to decode patch:", slog.String("error", err.Error()))
+
+		return echoBadRequest(ctx, err)
+	}
+
+	resp, err := h.service.PatchTimeline(ctx.Request().Context(), int(id), patch, version)
+	if err != nil {
+		log.ErrorContext(ctx.Request().Context(), "Failed to patch timeline:", slog.String("error", err.Error()))
+
+		return echoError(ctx, err)
 	}
 
-	log.Info("Timeline updated successfully")
+	ctx.Response().Header().Set("ETag", utils.ETag(*resp.Version))
+
+	log.InfoContext(ctx.Request().Context(), "Timeline patched successfully")
 
 	return ctx.JSON(http.StatusOK, resp)
 }
 
+func (h *TimelineHandler) GetDeletedTimelines(ctx echo.Context) error {
+	const op = "rest.Timeline.getDeleted"
+
+	log := h.log.With(
+		slog.String("op", op),
+	)
+
+	timelines, err := h.service.GetDeletedTimelines(ctx.Request().Context())
+	if err != nil {
+		log.ErrorContext(ctx.Request().Context(), "Failed to get deleted timelines:", slog.String("error", err.Error()))
+
+		return echoError(ctx, err)
+	}
+
+	log.InfoContext(ctx.Request().Context(), "Deleted timelines fetched successfully")
+
+	return ctx.JSON(http.StatusOK, timelines)
+}
+
+func (h *TimelineHandler) PostTimelineIdRestore(ctx echo.Context, id timeline_api.Id) error {
+	const op = "rest.Timeline.restore"
+
+	log := h.log.With(
+		slog.String("op", op),
+	)
+
+	timeline, err := h.service.RestoreTimeline(ctx.Request().Context(), int(id))
+	if err != nil {
+		log.ErrorContext(ctx.Request().Context(), "Failed to restore timeline:", slog.String("error", err.Error()))
+
+		return echoError(ctx, err)
+	}
+
+	log.InfoContext(ctx.Request().Context(), "Timeline restored successfully")
+
+	return ctx.JSON(http.StatusOK, timeline)
+}
+
 func (h *TimelineHandler) GetTimelimeStatus(ctx echo.Context) error {
 	const op = "rest.TimelineStatuses.get"
 
@@ -188,16 +273,14 @@ func (h *TimelineHandler) GetTimelimeStatus(ctx echo.Context) error {
 		slog.String("op", op),
 	)
 
-	resp, err := h.service.GetAllTimelineStatuses()
+	resp, err := h.service.GetAllTimelineStatuses(ctx.Request().Context())
 	if err != nil {
-		log.Error("Failed to get TimelineStatuses:", slog.String("error", err.Error()))
+		log.ErrorContext(ctx.Request().Context(), "Failed to get TimelineStatuses:", slog.String("error", err.Error()))
 
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to get TimelineStatuses",
-		})
+		return echoError(ctx, err)
 	}
 
-	log.Info("TimelineStatuses successfully fetched")
+	log.InfoContext(ctx.Request().Context(), "TimelineStatuses successfully fetched")
 
 	return ctx.JSON(http.StatusOK, resp)
 }
@@ -211,23 +294,19 @@ func (h *TimelineHandler) PostTimelimeStatus(ctx echo.Context) error {
 
 	var timelineStatus timeline_api.TimelineStatusResponse
 	if err := decodeAndValidateEcho(ctx, &timelineStatus, h.validator); err != nil {
-		log.Error("Failed to decode and validate request:", slog.String("error", err.Error()))
+		log.ErrorContext(ctx.Request().Context(), "Failed to decode and validate request:", slog.String("error", err.Error()))
 
-		return ctx.JSON(http.StatusBadRequest, map[string]string{
-			"error": err.Error(),
-		})
+		return echoBadRequest(ctx, err)
 	}
 
-	resp, err := h.service.CreateTimelineStatus(&timelineStatus)
+	resp, err := h.service.CreateTimelineStatus(ctx.Request().Context(), &timelineStatus)
 	if err != nil {
-		log.Error("Failed to create TimelineStatus:", slog.String("error", err.Error()))
+		log.ErrorContext(ctx.Request().Context(), "Failed to create TimelineStatus:", slog.String("error", err.Error()))
 
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to create TimelineStatus",
-		})
+		return echoError(ctx, err)
 	}
 
-	log.Info("TimelineStatus created successfully")
+	log.InfoContext(ctx.Request().Context(), "TimelineStatus created successfully")
 
 	return ctx.JSON(http.StatusCreated, resp)
 }
@@ -240,7 +319,7 @@ func NewTimeline(log *slog.Logger, service *service.TimelineService) *chi.Mux {
 	r.Use(middleware.URLFormat)
 	r.Use(middleware.Logger)
 
-	validate := validator.New()
+	validate := newValidator()
 
 	handler := NewTimelineHandler(log, service, validate)
 
@@ -253,11 +332,11 @@ func NewTimeline(log *slog.Logger, service *service.TimelineService) *chi.Mux {
 			if trackId := headers.Get("XTrackId"); trackId != "" {
 				id, err := strconv.Atoi(trackId)
 				if err != nil {
-					return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid XTrackId"})
+					return echoStatus(ctx, http.StatusBadRequest, "Invalid XTrackId")
 				}
 				params.XTrackId = &id
 			} else {
-				return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Missing required header: XTrackId"})
+				return echoStatus(ctx, http.StatusBadRequest, "Missing required header: XTrackId")
 			}
 
 			if status := queryParams.Get("status"); status != "" {
@@ -268,6 +347,15 @@ func NewTimeline(log *slog.Logger, service *service.TimelineService) *chi.Mux {
 		}))
 
 		r.Post("/", HandlerAdapter(handler.PostTimeline))
+		r.With(requireAdmin).Get("/deleted", HandlerAdapter(handler.GetDeletedTimelines))
+
+		r.Get("/team/{teamId}", HandlerAdapter(func(ctx echo.Context) error {
+			teamId, err := strconv.Atoi(ctx.Param("teamId"))
+			if err != nil {
+				return echoStatus(ctx, http.StatusBadRequest, "Invalid team ID")
+			}
+			return handler.GetTimelineTeamTeamId(ctx, teamId)
+		}))
 
 		r.Route("/status", func(r chi.Router) {
 			r.Get("/", HandlerAdapter(handler.GetTimelimeStatus))
@@ -279,7 +367,7 @@ func NewTimeline(log *slog.Logger, service *service.TimelineService) *chi.Mux {
 			r.Get("/", HandlerAdapter(func(ctx echo.Context) error {
 				id, err := strconv.Atoi(ctx.Param("Id"))
 				if err != nil {
-					return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID"})
+					return echoStatus(ctx, http.StatusBadRequest, "Invalid ID")
 				}
 				return handler.GetTimelineId(ctx, timeline_api.Id(id))
 			}))
@@ -287,18 +375,68 @@ func NewTimeline(log *slog.Logger, service *service.TimelineService) *chi.Mux {
 			r.Put("/", HandlerAdapter(func(ctx echo.Context) error {
 				id, err := strconv.Atoi(ctx.Param("Id"))
 				if err != nil {
-					return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID"})
+					return echoStatus(ctx, http.StatusBadRequest, "Invalid ID")
 				}
 				return handler.PutTimelineId(ctx, timeline_api.Id(id))
 			}))
 
+			r.Patch("/", HandlerAdapter(func(ctx echo.Context) error {
+				id, err := strconv.Atoi(ctx.Param("Id"))
+				if err != nil {
+					return echoStatus(ctx, http.StatusBadRequest, "Invalid ID")
+				}
+				return handler.PatchTimelineId(ctx, timeline_api.Id(id))
+			}))
+
 			r.Delete("/", HandlerAdapter(func(ctx echo.Context) error {
 				id, err := strconv.Atoi(ctx.Param("Id"))
 				if err != nil {
-					return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ID"})
+					return echoStatus(ctx, http.StatusBadRequest, "Invalid ID")
 				}
 				return handler.DeleteTimelineId(ctx, timeline_api.Id(id))
 			}))
+
+			r.With(requireAdmin).Post("/restore", HandlerAdapter(func(ctx echo.Context) error {
+				id, err := strconv.Atoi(ctx.Param("Id"))
+				if err != nil {
+					return echoStatus(ctx, http.StatusBadRequest, "Invalid ID")
+				}
+				return handler.PostTimelineIdRestore(ctx, timeline_api.Id(id))
+			}))
+
+			r.Route("/overrides", func(r chi.Router) {
+				r.Get("/", HandlerAdapter(func(ctx echo.Context) error {
+					id, err := strconv.Atoi(ctx.Param("Id"))
+					if err != nil {
+						return echoStatus(ctx, http.StatusBadRequest, "Invalid ID")
+					}
+					return handler.GetTimelineIdOverrides(ctx, timeline_api.Id(id))
+				}))
+
+				r.Put("/{teamId}", HandlerAdapter(func(ctx echo.Context) error {
+					id, err := strconv.Atoi(ctx.Param("Id"))
+					if err != nil {
+						return echoStatus(ctx, http.StatusBadRequest, "Invalid ID")
+					}
+					teamId, err := strconv.Atoi(ctx.Param("teamId"))
+					if err != nil {
+						return echoStatus(ctx, http.StatusBadRequest, "Invalid team ID")
+					}
+					return handler.PutTimelineIdOverridesTeamId(ctx, timeline_api.Id(id), teamId)
+				}))
+
+				r.Delete("/{teamId}", HandlerAdapter(func(ctx echo.Context) error {
+					id, err := strconv.Atoi(ctx.Param("Id"))
+					if err != nil {
+						return echoStatus(ctx, http.StatusBadRequest, "Invalid ID")
+					}
+					teamId, err := strconv.Atoi(ctx.Param("teamId"))
+					if err != nil {
+						return echoStatus(ctx, http.StatusBadRequest, "Invalid team ID")
+					}
+					return handler.DeleteTimelineIdOverridesTeamId(ctx, timeline_api.Id(id), teamId)
+				}))
+			})
 		})
 	})
 
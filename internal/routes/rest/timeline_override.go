@@ -0,0 +1,91 @@
+package rest
+
+import (
+	timeline_api "event_service/gen/timeline"
+	"event_service/internal/schemas"
+	"github.com/labstack/echo/v4"
+	"log/slog"
+	"net/http"
+)
+
+func (h *TimelineHandler) GetTimelineIdOverrides(ctx echo.Context, id timeline_api.Id) error {
+	const op = "rest.Timeline.getOverrides"
+
+	log := h.log.With(
+		slog.String("op", op),
+	)
+
+	overrides, err := h.service.GetTimelineOverrides(ctx.Request().Context(), int(id))
+	if err != nil {
+		log.ErrorContext(ctx.Request().Context(), "Failed to get timeline overrides:", slog.String("error", err.Error()))
+
+		return echoError(ctx, err)
+	}
+
+	log.InfoContext(ctx.Request().Context(), "Timeline overrides fetched successfully")
+
+	return ctx.JSON(http.StatusOK, overrides)
+}
+
+func (h *TimelineHandler) PutTimelineIdOverridesTeamId(ctx echo.Context, id timeline_api.Id, teamId int) error {
+	const op = "rest.Timeline.setOverride"
+
+	log := h.log.With(
+		slog.String("op", op),
+	)
+
+	var override schemas.TimelineOverride
+	if err := decodeAndValidateEcho(ctx, &override, h.validator); err != nil {
+		log.ErrorContext(ctx.Request().Context(), "Failed to decode and validate request:", slog.String("error", err.Error()))
+
+		return echoBadRequest(ctx, err)
+	}
+
+	saved, err := h.service.SetTimelineOverride(ctx.Request().Context(), int(id), teamId, override)
+	if err != nil {
+		log.ErrorContext(ctx.Request().Context(), "Failed to set timeline override:", slog.String("error", err.Error()))
+
+		return echoError(ctx, err)
+	}
+
+	log.InfoContext(ctx.Request().Context(), "Timeline override set successfully")
+
+	return ctx.JSON(http.StatusOK, saved)
+}
+
+func (h *TimelineHandler) DeleteTimelineIdOverridesTeamId(ctx echo.Context, id timeline_api.Id, teamId int) error {
+	const op = "rest.Timeline.deleteOverride"
+
+	log := h.log.With(
+		slog.String("op", op),
+	)
+
+	if err := h.service.DeleteTimelineOverride(ctx.Request().Context(), int(id), teamId); err != nil {
+		log.ErrorContext(ctx.Request().Context(), "Failed to delete timeline override:", slog.String("error", err.Error()))
+
+		return echoError(ctx, err)
+	}
+
+	log.InfoContext(ctx.Request().Context(), "Timeline override deleted successfully")
+
+	return ctx.NoContent(http.StatusOK)
+}
+
+func (h *TimelineHandler) GetTimelineTeamTeamId(ctx echo.Context, teamId int) error {
+	const op = "rest.Timeline.getTeamTimelines"
+
+	log := h.log.With(
+		slog.String("op", op),
+	)
+
+	timelines, err := h.service.GetTeamTimelines(ctx.Request().Context(), teamId)
+	if err != nil {
+		log.ErrorContext(ctx.Request().Context(), "Failed to get team timelines:", slog.String("error", err.Error()))
+
+		return echoError(ctx, err)
+	}
+
+	log.InfoContext(ctx.Request().Context(), "Team timelines fetched successfully")
+
+	return ctx.JSON(http.StatusOK, timelines)
+}
@@ -1,7 +1,9 @@
 package rest
 
 import (
+	"context"
 	"encoding/json"
+	status_api "event_service/gen/status"
 	"event_service/internal/models"
 	"event_service/internal/schemas"
 	"event_service/internal/service"
@@ -12,25 +14,47 @@ import (
 	"github.com/go-playground/validator/v10"
 	"log/slog"
 	"net/http"
+	"slices"
 	"strconv"
 )
 
+var trackTeamStates = []string{models.TrackTeamPending, models.TrackTeamApproved, models.TrackTeamRejected,
+	models.TrackTeamWithdrawn, models.TrackTeamWaitlisted}
+
 type TrackService interface {
-	GetAllTracks() ([]*models.Track, error)
-	GetTrackById(int) (*models.Track, error)
-	CreateTrack(schemas.Track) (*models.Track, error)
-	UpdateTrack(int, schemas.TrackUpdate) (*models.Track, error)
-	DeleteTrack(int) error
-
-	GetAllTrackLocations(int) ([]*models.Location, error)
-	AddLocationToTrack(*schemas.LocationTrack) (*models.LocationTrack, error)
-	RemoveLocationFromTrack(*schemas.LocationTrack) error
-
-	GetRegisteredTeams(int) ([]*models.TrackTeam, error)
-	GetCertainRegisteredTeam(int, int) (*models.TrackTeam, error)
-	RegisterTeam(*schemas.TrackTeam) (*models.TrackTeam, error)
-	UpdateRegisteredTeam(int, int, schemas.TrackTeamUpdate) (*models.TrackTeam, error)
-	DeleteRegisteredTeam(int, int) error
+	GetAllTracks(context.Context, *schemas.TrackFilter) ([]*models.Track, error)
+	GetTrackById(context.Context, int) (*models.Track, error)
+	CreateTrack(context.Context, schemas.Track) (*models.Track, error)
+	UpdateTrack(context.Context, int, schemas.TrackUpdate, int) (*models.Track, error)
+	PatchTrack(context.Context, int, schemas.TrackPatch, int) (*models.Track, error)
+	DeleteTrack(context.Context, int) error
+	GetDeletedTracks(context.Context) ([]*models.Track, error)
+	RestoreTrack(context.Context, int) (*models.Track, error)
+
+	GetAllTrackLocations(context.Context, int) ([]*models.Location, error)
+	AddLocationToTrack(context.Context, *schemas.LocationTrack) (*models.LocationTrack, error)
+	RemoveLocationFromTrack(context.Context, *schemas.LocationTrack) error
+
+	GetTrackStatuses(context.Context, int) ([]*status_api.StatusResponse, error)
+	AddStatusToTrack(context.Context, *schemas.StatusTrack) (*models.StatusTrack, error)
+	RemoveStatusFromTrack(context.Context, *schemas.StatusTrack) error
+
+	GetRegisteredTeams(context.Context, int, string, bool) ([]*schemas.RegisteredTeam, error)
+	GetCertainRegisteredTeam(context.Context, int, int) (*models.TrackTeam, error)
+	RegisterTeam(context.Context, *schemas.TrackTeam) (*models.TrackTeam, error)
+	UpdateRegisteredTeam(context.Context, int, int, schemas.TrackTeamUpdate) (*models.TrackTeam, error)
+	PatchRegisteredTeam(context.Context, int, int, schemas.TrackTeamPatch) (*models.TrackTeam, error)
+	DeleteRegisteredTeam(context.Context, int, int) error
+	ApproveRegisteredTeams(context.Context, int, schemas.TrackTeamApproval) ([]*models.TrackTeam, error)
+
+	GetTrackEligibility(context.Context, int) (*models.TrackEligibility, error)
+	SetTrackEligibility(context.Context, int, schemas.TrackEligibility) (*models.TrackEligibility, error)
+	CheckTrackEligibility(context.Context, int) ([]*schemas.EligibilityReport, error)
+
+	GetTeamProgress(context.Context, int, int) (*schemas.TeamProgress, error)
+	GetProgressMatrix(context.Context, int) (*schemas.ProgressMatrix, error)
+
+	GetTrackStatistics(context.Context, int) (*schemas.TrackStatistics, error)
 }
 
 func NewTrack(log *slog.Logger, service *service.TrackService) *chi.Mux {
@@ -41,11 +65,12 @@ func NewTrack(log *slog.Logger, service *service.TrackService) *chi.Mux {
 	r.Use(middleware.URLFormat)
 	r.Use(middleware.Logger)
 
-	validate := validator.New()
+	validate := newValidator()
 
 	r.Route("/", func(r chi.Router) {
 		r.Get("/", getAllTracksHandler(log, service))
 		r.Post("/", createTrackHandler(log, service, validate))
+		r.With(requireAdmin).Get("/deleted", getDeletedTracksHandler(log, service))
 
 		r.Route("/location", func(r chi.Router) {
 			r.Post("/", addLocationToTrackHandler(log, service))
@@ -71,11 +96,35 @@ func NewTrack(log *slog.Logger, service *service.TrackService) *chi.Mux {
 		r.Route("/{id}", func(r chi.Router) {
 			r.Get("/", getTracksByIDHandler(log, service))
 			r.Put("/", updateTrackHandler(log, service, validate))
+			r.Patch("/", patchTrackHandler(log, service))
 			r.Delete("/", deleteTrackHandler(log, service))
+			r.With(requireAdmin).Post("/restore", restoreTrackHandler(log, service))
+
+			r.Route("/status", func(r chi.Router) {
+				r.Get("/", getStatusesHandler(log, "Track", service.GetTrackStatuses))
+				r.Post("/", addStatusHandler(log, "Track", validate, func(ctx context.Context, id int, statusId int) (interface{}, error) {
+					return service.AddStatusToTrack(ctx, &schemas.StatusTrack{TrackID: id, StatusID: statusId})
+				}))
+				r.Delete("/{statusId}", removeStatusHandler(log, "Track", func(ctx context.Context, id int, statusId int) error {
+					return service.RemoveStatusFromTrack(ctx, &schemas.StatusTrack{TrackID: id, StatusID: statusId})
+				}))
+			})
+
+			r.Route("/eligibility", func(r chi.Router) {
+				r.Get("/", getTrackEligibilityHandler(log, service))
+				r.Put("/", setTrackEligibilityHandler(log, service, validate))
+				r.Post("/check", checkTrackEligibilityHandler(log, service))
+			})
+
+			r.Get("/progress", getProgressMatrixHandler(log, service))
+			r.Get("/statistics", getTrackStatisticsHandler(log, service))
+			r.Post("/team/approve", approveRegisteredTeamsHandler(log, service, validate))
 
 			r.Route("/team/{teamId}", func(r chi.Router) {
 				r.Put("/", updateRegisteredTeamHandler(log, service, validate))
+				r.Patch("/", patchRegisteredTeamHandler(log, service))
 				r.Delete("/", deleteRegisteredTeamHandler(log, service))
+				r.Get("/progress", getTeamProgressHandler(log, service))
 			})
 		})
 	})
@@ -92,22 +141,40 @@ func getAllTracksHandler(log *slog.Logger, service TrackService) http.HandlerFun
 			slog.With("request_id", middleware.GetReqID(r.Context())),
 		)
 
-		tracks, err := service.GetAllTracks()
+		status, statusId, err := parseStatusFilter(r)
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid track filter:", slog.String("error", err.Error()))
+
+			writeBadRequest(w, r, err)
+			return
+		}
+
+		filter := &schemas.TrackFilter{Status: status, StatusID: statusId}
+		if value := r.URL.Query().Get("event_id"); value != "" {
+			if filter.EventID, err = strconv.Atoi(value); err != nil {
+				log.ErrorContext(r.Context(), "Invalid event_id:", slog.String("error", err.Error()))
+
+				writeStatus(w, r, http.StatusBadRequest, "Invalid event_id")
+				return
+			}
+		}
+
+		tracks, err := service.GetAllTracks(r.Context(), filter)
 		if err != nil {
-			log.Error("error getting all tracks:", err)
+			log.ErrorContext(r.Context(), "error getting all tracks:", err)
 
-			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			writeError(w, r, err)
 			return
 		}
 
 		w.WriteHeader(http.StatusOK)
 		if err := json.NewEncoder(w).Encode(tracks); err != nil {
-			log.Error("error encoding tracks:", err)
+			log.ErrorContext(r.Context(), "error encoding tracks:", err)
 
 			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 		}
 
-		log.Info("tracks successfully fetched")
+		log.InfoContext(r.Context(), "tracks successfully fetched")
 	}
 }
 
@@ -121,22 +188,30 @@ func getTracksByIDHandler(log *slog.Logger, service TrackService) http.HandlerFu
 		)
 
 		trackId, err := strconv.Atoi(chi.URLParam(r, "id"))
-		track, err := service.GetTrackById(trackId)
+		track, err := service.GetTrackById(r.Context(), trackId)
 		if err != nil {
-			log.Error("Failed to get track:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to get track:", err.Error())
+
+			writeError(w, r, err)
+			return
+		}
+
+		etag := utils.ETag(track.Version)
+		w.Header().Set("ETag", etag)
 
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+		if utils.IfNoneMatch(r, etag) {
+			w.WriteHeader(http.StatusNotModified)
 			return
 		}
 
 		w.WriteHeader(http.StatusOK)
 		if err := json.NewEncoder(w).Encode(track); err != nil {
-			log.Error("Failed to encode response:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to encode response:", err.Error())
 
 			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 		}
 
-		log.Info("Track fetched successfully")
+		log.InfoContext(r.Context(), "Track fetched successfully")
 	}
 }
 
@@ -151,28 +226,28 @@ func createTrackHandler(log *slog.Logger, service TrackService, validate *valida
 
 		var track schemas.Track
 		if err := DecodeAndValidate(r, &track, validate); err != nil {
-			log.Error("Failed to decode request:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to decode request:", err.Error())
 
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			writeBadRequest(w, r, err)
 			return
 		}
 
-		resp, err := service.CreateTrack(track)
+		resp, err := service.CreateTrack(r.Context(), track)
 		if err != nil {
-			log.Error("Failed to create track:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to create track:", err.Error())
 
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, r, err)
 			return
 		}
 
 		w.WriteHeader(http.StatusCreated)
 		if err := json.NewEncoder(w).Encode(resp); err != nil {
-			log.Error("Failed to encode response:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to encode response:", err.Error())
 
 			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 		}
 
-		log.Info("Track created successfully")
+		log.InfoContext(r.Context(), "Track created successfully")
 	}
 }
 
@@ -187,30 +262,90 @@ func updateTrackHandler(log *slog.Logger, service TrackService, validate *valida
 
 		trackId, err := strconv.Atoi(chi.URLParam(r, "id"))
 
+		version, err := utils.IfMatchVersion(r)
+		if err != nil {
+			log.ErrorContext(r.Context(), "Precondition check failed:", slog.String("error", err.Error()))
+
+			writeError(w, r, err)
+			return
+		}
+
 		var track schemas.TrackUpdate
 		if err := DecodeAndValidate(r, &track, validate); err != nil {
-			log.Error("Failed to decode request:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to decode request:", err.Error())
 
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			writeBadRequest(w, r, err)
 			return
 		}
 
-		resp, err := service.UpdateTrack(trackId, track)
+		resp, err := service.UpdateTrack(r.Context(), trackId, track, version)
 		if err != nil {
-			log.Error("Failed to update track:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to update track:", err.Error())
 
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, r, err)
 			return
 		}
 
+		w.Header().Set("ETag", utils.ETag(resp.Version))
 		w.WriteHeader(http.StatusOK)
 		if err := json.NewEncoder(w).Encode(resp); err != nil {
-			log.Error("Failed to encode response:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to encode response:", err.Error())
 
 			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 		}
 
-		log.Info("Track updated successfully")
+		log.InfoContext(r.Context(), "Track updated successfully")
+	}
+}
+
+func patchTrackHandler(log *slog.Logger, service TrackService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "rest.Track.patch"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		trackId, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid track id:", slog.String("error", err.Error()))
+
+			writeStatus(w, r, http.StatusBadRequest, "Invalid track id")
+			return
+		}
+
+		version, err := utils.IfMatchVersion(r)
+		if err != nil {
+			log.ErrorContext(r.Context(), "Precondition check failed:", slog.String("error", err.Error()))
+
+			writeError(w, r, err)
+			return
+		}
+
+		var patch schemas.TrackPatch
+		if err := decodeMergePatch(r, &patch); err != nil {
+			log.ErrorContext(r.Context(), "Failed to decode patch:", slog.String("error", err.Error()))
+
+			writeBadRequest(w, r, err)
+			return
+		}
+
+		resp, err := service.PatchTrack(r.Context(), trackId, patch, version)
+		if err != nil {
+			log.ErrorContext(r.Context(), "Failed to patch track:", slog.String("error", err.Error()))
+
+			writeError(w, r, err)
+			return
+		}
+
+		w.Header().Set("ETag", utils.ETag(resp.Version))
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			log.ErrorContext(r.Context(), "Failed to encode response:", slog.String("error", err.Error()))
+		}
+
+		log.InfoContext(r.Context(), "Track patched successfully")
 	}
 }
 
@@ -224,17 +359,81 @@ func deleteTrackHandler(log *slog.Logger, service TrackService) http.HandlerFunc
 		)
 
 		trackId, err := strconv.Atoi(chi.URLParam(r, "id"))
-		err = service.DeleteTrack(trackId)
+		err = service.DeleteTrack(r.Context(), trackId)
 
 		if err != nil {
-			log.Error("Failed to delete track:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to delete track:", err.Error())
 
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, r, err)
 			return
 		}
 
 		w.WriteHeader(http.StatusOK)
-		log.Info("Track deleted successfully")
+		log.InfoContext(r.Context(), "Track deleted successfully")
+	}
+}
+
+func getDeletedTracksHandler(log *slog.Logger, service TrackService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "rest.Track.getDeleted"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		tracks, err := service.GetDeletedTracks(r.Context())
+		if err != nil {
+			log.ErrorContext(r.Context(), "Failed to get deleted tracks:", slog.String("error", err.Error()))
+
+			writeError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(tracks); err != nil {
+			log.ErrorContext(r.Context(), "Failed to encode response:", slog.String("error", err.Error()))
+
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		}
+
+		log.InfoContext(r.Context(), "Deleted tracks fetched successfully")
+	}
+}
+
+func restoreTrackHandler(log *slog.Logger, service TrackService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "rest.Track.restore"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		trackId, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid track id:", slog.String("error", err.Error()))
+
+			writeStatus(w, r, http.StatusBadRequest, "Invalid track id")
+			return
+		}
+
+		track, err := service.RestoreTrack(r.Context(), trackId)
+		if err != nil {
+			log.ErrorContext(r.Context(), "Failed to restore track:", slog.String("error", err.Error()))
+
+			writeError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(track); err != nil {
+			log.ErrorContext(r.Context(), "Failed to encode response:", slog.String("error", err.Error()))
+
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		}
+
+		log.InfoContext(r.Context(), "Track restored successfully")
 	}
 }
 
@@ -248,23 +447,23 @@ func getAllTrackLocationsHandler(log *slog.Logger, service TrackService) http.Ha
 		)
 
 		trackId, err := strconv.Atoi(chi.URLParam(r, "id"))
-		locations, err := service.GetAllTrackLocations(trackId)
+		locations, err := service.GetAllTrackLocations(r.Context(), trackId)
 
 		if err != nil {
-			log.Error("Failed to get locations by track:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to get locations by track:", err.Error())
 
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, r, err)
 			return
 		}
 
 		w.WriteHeader(http.StatusOK)
 		if err := json.NewEncoder(w).Encode(locations); err != nil {
-			log.Error("Failed to encode response:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to encode response:", err.Error())
 
 			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 		}
 
-		log.Info("Locations by track fetched successfully")
+		log.InfoContext(r.Context(), "Locations by track fetched successfully")
 	}
 }
 
@@ -284,32 +483,32 @@ func addLocationToTrackHandler(log *slog.Logger, service TrackService) http.Hand
 
 		convertedHeaders, err := utils.ValidateHeaders(headersList, log, r)
 		if err != nil {
-			log.Error("Failed to validate headers:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to validate headers:", err.Error())
 
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			writeBadRequest(w, r, err)
 			return
 		}
 
-		newLocation, err := service.AddLocationToTrack(&schemas.LocationTrack{
+		newLocation, err := service.AddLocationToTrack(r.Context(), &schemas.LocationTrack{
 			LocationId: convertedHeaders["LocationId"].(int),
 			TrackId:    convertedHeaders["TrackId"].(int),
 		})
 
 		if err != nil {
-			log.Error("Failed to add location to track:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to add location to track:", err.Error())
 
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, r, err)
 			return
 		}
 
 		w.WriteHeader(http.StatusOK)
 		if err := json.NewEncoder(w).Encode(newLocation); err != nil {
-			log.Error("Failed to encode response:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to encode response:", err.Error())
 
 			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 		}
 
-		log.Info("Location added to track successfully")
+		log.InfoContext(r.Context(), "Location added to track successfully")
 	}
 }
 
@@ -325,35 +524,35 @@ func removeLocationFromTrackHandler(log *slog.Logger, service TrackService) http
 		queryParams := r.URL.Query()
 
 		if queryParams.Get("location_id") == "" {
-			log.Error("Missing 'location_id' in query")
+			log.ErrorContext(r.Context(), "Missing 'location_id' in query")
 
-			http.Error(w, "Missing 'location_id' in query", http.StatusBadRequest)
+			writeStatus(w, r, http.StatusBadRequest, "Missing 'location_id' in query")
 			return
 		}
 
 		trackId, err := strconv.Atoi(chi.URLParam(r, "id"))
 		locationId, err := strconv.Atoi(queryParams.Get("location_id"))
 		if err != nil {
-			log.Error("Invalid format of location_id:", err.Error())
+			log.ErrorContext(r.Context(), "Invalid format of location_id:", err.Error())
 
-			http.Error(w, fmt.Sprintf("Invalid format of location_id query, expected number, got %s", queryParams.Get("status_id")), http.StatusBadRequest)
+			writeStatus(w, r, http.StatusBadRequest, fmt.Sprintf("Invalid format of location_id query, expected number, got %s", queryParams.Get("status_id")))
 			return
 		}
 
-		err = service.RemoveLocationFromTrack(&schemas.LocationTrack{
+		err = service.RemoveLocationFromTrack(r.Context(), &schemas.LocationTrack{
 			TrackId:    trackId,
 			LocationId: locationId,
 		})
 
 		if err != nil {
-			log.Error("Failed to remove location from track:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to remove location from track:", err.Error())
 
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, r, err)
 			return
 		}
 
 		w.WriteHeader(http.StatusOK)
-		log.Info("Location deleted from track successfully")
+		log.InfoContext(r.Context(), "Location deleted from track successfully")
 	}
 }
 
@@ -372,29 +571,39 @@ func getRegisteredTeamsHandler(log *slog.Logger, service TrackService) http.Hand
 
 		convertedHeaders, err := utils.ValidateHeaders(headersList, log, r)
 		if err != nil {
-			log.Error("Failed to validate headers: ", err.Error())
+			log.ErrorContext(r.Context(), "Failed to validate headers: ", err.Error())
 
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			writeBadRequest(w, r, err)
 		}
 
 		trackId := convertedHeaders["TrackId"].(int)
 
-		registeredTeams, err := service.GetRegisteredTeams(trackId)
+		state := r.URL.Query().Get("state")
+		if state != "" && !slices.Contains(trackTeamStates, state) {
+			log.ErrorContext(r.Context(), "Invalid registration state:", slog.String("state", state))
+
+			writeStatus(w, r, http.StatusBadRequest, "Invalid state")
+			return
+		}
+
+		withTeams := r.URL.Query().Get("include") == "team"
+
+		registeredTeams, err := service.GetRegisteredTeams(r.Context(), trackId, state, withTeams)
 		if err != nil {
-			log.Error("Failed to fetch registered teams:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to fetch registered teams:", err.Error())
 
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, r, err)
 			return
 		}
 
 		w.WriteHeader(http.StatusOK)
 		if err := json.NewEncoder(w).Encode(registeredTeams); err != nil {
-			log.Error("Failed to encode response:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to encode response:", err.Error())
 
 			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 		}
 
-		log.Info("RegisteredTeams successfully fetched")
+		log.InfoContext(r.Context(), "RegisteredTeams successfully fetched")
 	}
 }
 
@@ -409,28 +618,28 @@ func registerTeamHandler(log *slog.Logger, service TrackService, validate *valid
 
 		var trackTeam schemas.TrackTeam
 		if err := DecodeAndValidate(r, &trackTeam, validate); err != nil {
-			log.Error("Failed to decode request:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to decode request:", err.Error())
 
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			writeBadRequest(w, r, err)
 			return
 		}
 
-		resp, err := service.RegisterTeam(&trackTeam)
+		resp, err := service.RegisterTeam(r.Context(), &trackTeam)
 		if err != nil {
-			log.Error("Failed to create TrackTeam:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to create TrackTeam:", err.Error())
 
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, r, err)
 			return
 		}
 
 		w.WriteHeader(http.StatusCreated)
 		if err := json.NewEncoder(w).Encode(resp); err != nil {
-			log.Error("Failed to encode response:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to encode response:", err.Error())
 
 			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 		}
 
-		log.Info("TrackTeam created successfully")
+		log.InfoContext(r.Context(), "TrackTeam created successfully")
 	}
 }
 
@@ -448,28 +657,28 @@ func updateRegisteredTeamHandler(log *slog.Logger, service TrackService, validat
 
 		var trackTeam schemas.TrackTeamUpdate
 		if err := DecodeAndValidate(r, &trackTeam, validate); err != nil {
-			log.Error("Failed to decode request:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to decode request:", err.Error())
 
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			writeBadRequest(w, r, err)
 			return
 		}
 
-		resp, err := service.UpdateRegisteredTeam(trackId, teamId, trackTeam)
+		resp, err := service.UpdateRegisteredTeam(r.Context(), trackId, teamId, trackTeam)
 		if err != nil {
-			log.Error("Failed to update registered team:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to update registered team:", err.Error())
 
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, r, err)
 			return
 		}
 
 		w.WriteHeader(http.StatusOK)
 		if err := json.NewEncoder(w).Encode(resp); err != nil {
-			log.Error("Failed to encode response:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to encode response:", err.Error())
 
 			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 		}
 
-		log.Info("Registered team updated successfully")
+		log.InfoContext(r.Context(), "Registered team updated successfully")
 	}
 }
 
@@ -485,16 +694,108 @@ func deleteRegisteredTeamHandler(log *slog.Logger, service TrackService) http.Ha
 		trackId, err := strconv.Atoi(chi.URLParam(r, "id"))
 		teamId, err := strconv.Atoi(chi.URLParam(r, "teamId"))
 
-		err = service.DeleteRegisteredTeam(trackId, teamId)
+		err = service.DeleteRegisteredTeam(r.Context(), trackId, teamId)
+
+		if err != nil {
+			log.ErrorContext(r.Context(), "Failed to delete registered team:", err.Error())
+
+			writeError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		log.InfoContext(r.Context(), "Registered team deleted successfully")
+	}
+}
+
+func patchRegisteredTeamHandler(log *slog.Logger, service TrackService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "rest.Track.patchRegisteredTeam"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		trackId, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid track id:", slog.String("error", err.Error()))
+
+			writeStatus(w, r, http.StatusBadRequest, "Invalid track id")
+			return
+		}
+
+		teamId, err := strconv.Atoi(chi.URLParam(r, "teamId"))
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid team id:", slog.String("error", err.Error()))
+
+			writeStatus(w, r, http.StatusBadRequest, "Invalid team id")
+			return
+		}
+
+		var patch schemas.TrackTeamPatch
+		if err := decodeMergePatch(r, &patch); err != nil {
+			log.ErrorContext(r.Context(), "Failed to decode patch:", slog.String("error", err.Error()))
+
+			writeBadRequest(w, r, err)
+			return
+		}
 
+		resp, err := service.PatchRegisteredTeam(r.Context(), trackId, teamId, patch)
 		if err != nil {
-			log.Error("Failed to delete registered team:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to patch registered team:", slog.String("error", err.Error()))
 
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, r, err)
 			return
 		}
 
 		w.WriteHeader(http.StatusOK)
-		log.Info("Registered team deleted successfully")
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			log.ErrorContext(r.Context(), "Failed to encode response:", slog.String("error", err.Error()))
+		}
+
+		log.InfoContext(r.Context(), "Registered team patched successfully")
+	}
+}
+
+func approveRegisteredTeamsHandler(log *slog.Logger, service TrackService, validate *validator.Validate) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "rest.Track.approveRegisteredTeams"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		trackId, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid track id:", slog.String("error", err.Error()))
+
+			writeStatus(w, r, http.StatusBadRequest, "Invalid track id")
+			return
+		}
+
+		var approval schemas.TrackTeamApproval
+		if err := DecodeAndValidate(r, &approval, validate); err != nil {
+			log.ErrorContext(r.Context(), "Failed to decode request:", slog.String("error", err.Error()))
+
+			writeBadRequest(w, r, err)
+			return
+		}
+
+		resp, err := service.ApproveRegisteredTeams(r.Context(), trackId, approval)
+		if err != nil {
+			log.ErrorContext(r.Context(), "Failed to approve registered teams:", slog.String("error", err.Error()))
+
+			writeError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			log.ErrorContext(r.Context(), "Failed to encode response:", slog.String("error", err.Error()))
+		}
+
+		log.InfoContext(r.Context(), "Registered teams approved successfully")
 	}
 }
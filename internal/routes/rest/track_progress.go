@@ -0,0 +1,86 @@
+package rest
+
+import (
+	"encoding/json"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+func getTeamProgressHandler(log *slog.Logger, service TrackService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "rest.Track.getTeamProgress"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		trackId, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid track id:", slog.String("error", err.Error()))
+
+			writeStatus(w, r, http.StatusBadRequest, "Invalid track id")
+			return
+		}
+
+		teamId, err := strconv.Atoi(chi.URLParam(r, "teamId"))
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid team id:", slog.String("error", err.Error()))
+
+			writeStatus(w, r, http.StatusBadRequest, "Invalid team id")
+			return
+		}
+
+		progress, err := service.GetTeamProgress(r.Context(), trackId, teamId)
+		if err != nil {
+			log.ErrorContext(r.Context(), "Failed to get team progress:", slog.String("error", err.Error()))
+
+			writeError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(progress); err != nil {
+			log.ErrorContext(r.Context(), "Failed to encode response:", slog.String("error", err.Error()))
+		}
+
+		log.InfoContext(r.Context(), "Team progress fetched successfully")
+	}
+}
+
+func getProgressMatrixHandler(log *slog.Logger, service TrackService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "rest.Track.getProgressMatrix"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		trackId, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid track id:", slog.String("error", err.Error()))
+
+			writeStatus(w, r, http.StatusBadRequest, "Invalid track id")
+			return
+		}
+
+		matrix, err := service.GetProgressMatrix(r.Context(), trackId)
+		if err != nil {
+			log.ErrorContext(r.Context(), "Failed to get progress matrix:", slog.String("error", err.Error()))
+
+			writeError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(matrix); err != nil {
+			log.ErrorContext(r.Context(), "Failed to encode response:", slog.String("error", err.Error()))
+		}
+
+		log.InfoContext(r.Context(), "Progress matrix fetched successfully")
+	}
+}
@@ -1,6 +1,7 @@
 package rest
 
 import (
+	"context"
 	"encoding/json"
 	"event_service/internal/models"
 	"event_service/internal/repositories"
@@ -16,11 +17,11 @@ import (
 )
 
 type TrackWinnerService interface {
-	GetWinnersOfTrack(int) ([]*models.TrackWinner, error)
-	GetWinnerById(int, int) (*models.TrackWinner, error)
-	CreateWinnerOfTrack(*schemas.TrackWinner) (*models.TrackWinner, error)
+	GetWinnersOfTrack(context.Context, int) ([]*models.TrackWinner, error)
+	GetWinnerById(context.Context, int, int) (*models.TrackWinner, error)
+	CreateWinnerOfTrack(context.Context, *schemas.TrackWinner) (*models.TrackWinner, error)
 
-	CalculateRating(int, int, int) ([]*repositories.AggregateResult, error)
+	CalculateRating(context.Context, int, int, int) ([]*repositories.AggregateResult, error)
 }
 
 func NewTrackWinner(log *slog.Logger, service *service.TrackWinnerService) *chi.Mux {
@@ -31,7 +32,7 @@ func NewTrackWinner(log *slog.Logger, service *service.TrackWinnerService) *chi.
 	r.Use(middleware.URLFormat)
 	r.Use(middleware.Logger)
 
-	validate := validator.New()
+	validate := newValidator()
 
 	r.Route("/", func(r chi.Router) {
 		r.Get("/", getWinnersOfTrackHandler(log, service))
@@ -61,28 +62,28 @@ func getWinnersOfTrackHandler(log *slog.Logger, service TrackWinnerService) http
 
 		convertedHeaders, err := utils.ValidateHeaders(headersList, log, r)
 		if err != nil {
-			log.Error("Headers validation failed with error:", err)
+			log.ErrorContext(r.Context(), "Headers validation failed with error:", err)
 
-			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			writeBadRequest(w, r, err)
 			return
 		}
 
-		tracks, err := service.GetWinnersOfTrack(convertedHeaders["TeamId"].(int))
+		tracks, err := service.GetWinnersOfTrack(r.Context(), convertedHeaders["TeamId"].(int))
 		if err != nil {
-			log.Error("error getting winners:", err)
+			log.ErrorContext(r.Context(), "error getting winners:", err)
 
-			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			writeError(w, r, err)
 			return
 		}
 
 		w.WriteHeader(http.StatusOK)
 		if err := json.NewEncoder(w).Encode(tracks); err != nil {
-			log.Error("error encoding tracks:", err)
+			log.ErrorContext(r.Context(), "error encoding tracks:", err)
 
 			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 		}
 
-		log.Info("Winners successfully fetched")
+		log.InfoContext(r.Context(), "Winners successfully fetched")
 	}
 }
 
@@ -97,28 +98,28 @@ func createWinnersOfTrackHandler(log *slog.Logger, service TrackWinnerService, v
 
 		var track schemas.TrackWinner
 		if err := DecodeAndValidate(r, &track, validate); err != nil {
-			log.Error("Failed to decode request:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to decode request:", err.Error())
 
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			writeBadRequest(w, r, err)
 			return
 		}
 
-		resp, err := service.CreateWinnerOfTrack(&track)
+		resp, err := service.CreateWinnerOfTrack(r.Context(), &track)
 		if err != nil {
-			log.Error("Failed to create TrackWinner:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to create TrackWinner:", err.Error())
 
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, r, err)
 			return
 		}
 
 		w.WriteHeader(http.StatusCreated)
 		if err := json.NewEncoder(w).Encode(resp); err != nil {
-			log.Error("Failed to encode response:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to encode response:", err.Error())
 
 			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 		}
 
-		log.Info("TrackWinner created successfully")
+		log.InfoContext(r.Context(), "TrackWinner created successfully")
 	}
 }
 
@@ -132,6 +133,12 @@ func getWinnerByIdHandler(log *slog.Logger, service TrackWinnerService) http.Han
 		)
 
 		trackId, err := strconv.Atoi(chi.URLParam(r, "trackId"))
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid track id:", slog.String("error", err.Error()))
+
+			writeStatus(w, r, http.StatusBadRequest, "Invalid track id")
+			return
+		}
 
 		headersList := map[string]string{
 			"TeamId": "int",
@@ -139,28 +146,28 @@ func getWinnerByIdHandler(log *slog.Logger, service TrackWinnerService) http.Han
 
 		convertedHeaders, err := utils.ValidateHeaders(headersList, log, r)
 		if err != nil {
-			log.Error("Headers validation failed with error: ", err.Error())
+			log.ErrorContext(r.Context(), "Headers validation failed with error: ", err.Error())
 
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeBadRequest(w, r, err)
 			return
 		}
 
-		track, err := service.GetWinnerById(trackId, convertedHeaders["TeamId"].(int))
+		track, err := service.GetWinnerById(r.Context(), trackId, convertedHeaders["TeamId"].(int))
 		if err != nil {
-			log.Error("Failed to get TrackWinner:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to get TrackWinner:", err.Error())
 
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, r, err)
 			return
 		}
 
 		w.WriteHeader(http.StatusOK)
 		if err := json.NewEncoder(w).Encode(track); err != nil {
-			log.Error("Failed to encode response:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to encode response:", err.Error())
 
 			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 		}
 
-		log.Info("TrackWinner fetched successfully")
+		log.InfoContext(r.Context(), "TrackWinner fetched successfully")
 	}
 }
 
@@ -173,18 +180,25 @@ func calculateRatingHandler(log *slog.Logger, service TrackWinnerService) http.H
 			slog.String("request_it", middleware.GetReqID(r.Context())),
 		)
 
-		trackId, _ := strconv.Atoi(chi.URLParam(r, "trackId"))
+		trackId, err := strconv.Atoi(chi.URLParam(r, "trackId"))
+		if err != nil {
+			log.ErrorContext(r.Context(), "Invalid track id:", slog.String("error", err.Error()))
+
+			writeStatus(w, r, http.StatusBadRequest, "Invalid track id")
+			return
+		}
+
 		queryParams := r.URL.Query()
 
 		limit := 100
 		offset := 0
 
 		if queryParams.Get("limit") != "" {
-			converted, err := strconv.Atoi(queryParams.Get("status_id"))
+			converted, err := strconv.Atoi(queryParams.Get("limit"))
 			if err != nil {
-				log.Error("Failed to convert limit query param:", err.Error())
+				log.ErrorContext(r.Context(), "Failed to convert limit query param:", err.Error())
 
-				http.Error(w, err.Error(), http.StatusInternalServerError)
+				writeBadRequest(w, r, err)
 				return
 			}
 
@@ -194,30 +208,30 @@ func calculateRatingHandler(log *slog.Logger, service TrackWinnerService) http.H
 		if queryParams.Get("offset") != "" {
 			converted, err := strconv.Atoi(queryParams.Get("offset"))
 			if err != nil {
-				log.Error("Failed to convert offset query param:", err.Error())
+				log.ErrorContext(r.Context(), "Failed to convert offset query param:", err.Error())
 
-				http.Error(w, err.Error(), http.StatusInternalServerError)
+				writeBadRequest(w, r, err)
 				return
 			}
 
 			offset = converted
 		}
 
-		results, err := service.CalculateRating(trackId, limit, offset)
+		results, err := service.CalculateRating(r.Context(), trackId, limit, offset)
 		if err != nil {
-			log.Error("Failed to calculate results:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to calculate results:", err.Error())
 
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, r, err)
 			return
 		}
 
 		w.WriteHeader(http.StatusOK)
 		if err := json.NewEncoder(w).Encode(results); err != nil {
-			log.Error("Failed to encode response:", err.Error())
+			log.ErrorContext(r.Context(), "Failed to encode response:", err.Error())
 
 			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 		}
 
-		log.Info("TrackWinners results calculated successfully")
+		log.InfoContext(r.Context(), "TrackWinners results calculated successfully")
 	}
 }
@@ -0,0 +1,14 @@
+package schemas
+
+import "time"
+
+type AuditFilter struct {
+	Entity   string    `json:"entity" example:"team_action_status"`
+	EntityID string    `json:"entity_id" example:"12:5"`
+	ActorID  int       `json:"actor_id" example:"52"`
+	Action   string    `json:"action" example:"update"`
+	From     time.Time `json:"from" example:"2023-01-01T00:00:00Z"`
+	To       time.Time `json:"to" example:"2023-01-02T00:00:00Z"`
+	Limit    int       `json:"limit" example:"100"`
+	Offset   int       `json:"offset" example:"0"`
+}
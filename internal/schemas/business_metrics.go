@@ -0,0 +1,7 @@
+package schemas
+
+type BusinessMetrics struct {
+	EventsByStatus       map[string]int `json:"events_by_status"`
+	TracksByStatus       map[string]int `json:"tracks_by_status"`
+	RegistrationsByState map[string]int `json:"registrations_by_state"`
+}
@@ -6,3 +6,8 @@ type Date struct {
 	DateStart time.Time `json:"date_start" validate:"required" example:"2023-01-01T00:00:00Z"`
 	DateEnd   time.Time `json:"date_end" validate:"required,gtfield=DateStart" example:"2023-01-02T00:00:00Z"`
 }
+
+type DatePatch struct {
+	DateStart Optional[time.Time] `json:"date_start" example:"2023-01-01T00:00:00Z"`
+	DateEnd   Optional[time.Time] `json:"date_end" example:"2023-01-02T00:00:00Z"`
+}
@@ -6,6 +6,7 @@ type Event struct {
 	RedirectLink string `json:"redirect_link" validate:"required" example:"http://example.com"`
 	DateId       int    `json:"date_id" validate:"required" example:"1"`
 	Status       string `json:"status" validate:"required" example:"in_progress"`
+	TimeZone     string `json:"time_zone" validate:"omitempty,timezone" example:"Europe/Moscow"`
 }
 
 type EventUpdate struct {
@@ -14,4 +15,32 @@ type EventUpdate struct {
 	RedirectLink string `json:"redirect_link" example:"http://example.com"`
 	DateId       int    `json:"date_id" example:"1"`
 	Status       string `json:"status" example:"in_progress"`
+	TimeZone     string `json:"time_zone" validate:"omitempty,timezone" example:"Europe/Moscow"`
+}
+
+type EventPatch struct {
+	Title        Optional[string] `json:"title" validate:"omitempty,min=1,max=255" example:"Event title"`
+	Description  Nullable[string] `json:"description" example:"Event description"`
+	RedirectLink Optional[string] `json:"redirect_link" validate:"omitempty,url" example:"http://example.com"`
+	DateId       Optional[int]    `json:"date_id" validate:"omitempty,gt=0" example:"1"`
+	Status       Optional[string] `json:"status" validate:"omitempty,oneof=planned in_process completed cancelled" example:"in_process"`
+	TimeZone     Optional[string] `json:"time_zone" validate:"omitempty,timezone" example:"Europe/Moscow"`
+}
+
+func (u EventUpdate) Patch() EventPatch {
+	return EventPatch{
+		Title:        NonZero(u.Title),
+		Description:  NullableNonZero(u.Description),
+		RedirectLink: NonZero(u.RedirectLink),
+		DateId:       NonZero(u.DateId),
+		Status:       NonZero(u.Status),
+		TimeZone:     NonZero(u.TimeZone),
+	}
+}
+
+// EventFilter narrows the event list by lifecycle status and attached
+// status labels.
+type EventFilter struct {
+	Status   string
+	StatusID int
 }
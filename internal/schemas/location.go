@@ -7,3 +7,33 @@ type Location struct {
 type LocationUpdate struct {
 	Title string `json:"title" example:"Location Title"`
 }
+
+type LocationPatch struct {
+	Title        Optional[string]  `json:"title" validate:"omitempty,min=1,max=255" example:"Location Title"`
+	TimeZone     Nullable[string]  `json:"time_zone" validate:"omitempty,timezone" example:"Asia/Novosibirsk"`
+	Address      Nullable[string]  `json:"address" example:"Екатеринбург, ул. Мира, 19"`
+	Latitude     Nullable[float64] `json:"latitude" validate:"omitempty,min=-90,max=90" example:"56.8431"`
+	Longitude    Nullable[float64] `json:"longitude" validate:"omitempty,min=-180,max=180" example:"60.6454"`
+	Capacity     Nullable[int]     `json:"capacity" validate:"omitempty,min=0" example:"120"`
+	TeamCapacity Nullable[int]     `json:"team_capacity" validate:"omitempty,min=0" example:"30"`
+	Type         Optional[string]  `json:"type" validate:"omitempty,oneof=physical online hybrid" example:"physical"`
+	StreamURL    Nullable[string]  `json:"stream_url" validate:"omitempty,url" example:"https://stream.example.com/hall-a"`
+	ParentID     Nullable[int]     `json:"parent_id" validate:"omitempty,gt=0" example:"1"`
+}
+
+// LocationFilter narrows the location list. Near search is active when
+// Latitude and Longitude are set and matches within RadiusKm.
+type LocationFilter struct {
+	ParentID  int
+	Latitude  *float64
+	Longitude *float64
+	RadiusKm  float64
+}
+
+// LocationLoad is how many active teams work in a location across all of
+// its tracks, compared with the number of teams it can host.
+type LocationLoad struct {
+	LocationID   int `json:"location_id" example:"42"`
+	TeamCapacity int `json:"team_capacity" example:"30"`
+	Teams        int `json:"teams" example:"31"`
+}
@@ -0,0 +1,26 @@
+package schemas
+
+import "time"
+
+type LocationBooking struct {
+	Title     string    `json:"title" validate:"required,max=255" example:"Hall rental"`
+	DateStart time.Time `json:"date_start" validate:"required" example:"2025-05-01T10:00:00Z"`
+	DateEnd   time.Time `json:"date_end" validate:"required,gtfield=DateStart" example:"2025-05-01T18:00:00Z"`
+	EventID   int       `json:"event_id" validate:"omitempty,gt=0" example:"42"`
+	TrackID   int       `json:"track_id" validate:"omitempty,gt=0" example:"42"`
+}
+
+type TimeSlot struct {
+	DateStart time.Time `json:"date_start" example:"2025-05-01T10:00:00Z"`
+	DateEnd   time.Time `json:"date_end" example:"2025-05-01T18:00:00Z"`
+}
+
+// LocationAvailability splits From..To into the ranges taken by bookings and
+// sessions and the free ranges left between them.
+type LocationAvailability struct {
+	LocationID int        `json:"location_id" example:"42"`
+	From       time.Time  `json:"from" example:"2025-05-01T00:00:00Z"`
+	To         time.Time  `json:"to" example:"2025-05-08T00:00:00Z"`
+	Busy       []TimeSlot `json:"busy"`
+	Free       []TimeSlot `json:"free"`
+}
@@ -0,0 +1,130 @@
+package schemas
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+var ErrNullNotAllowed = errors.New("field cannot be null")
+
+// PatchField is implemented by merge-patch fields so the validator checks the
+// submitted value and skips fields that were omitted or cleared.
+type PatchField interface {
+	ValidationValue() interface{}
+}
+
+// Optional is a JSON Merge Patch (RFC 7396) member that may be omitted or
+// replaced but not cleared, because the underlying column is required.
+type Optional[T any] struct {
+	Set   bool
+	Value T
+}
+
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return ErrNullNotAllowed
+	}
+
+	o.Set = true
+
+	return json.Unmarshal(data, &o.Value)
+}
+
+func (o Optional[T]) ValidationValue() interface{} {
+	if !o.Set {
+		return nil
+	}
+
+	return o.Value
+}
+
+// Nullable is a JSON Merge Patch member where an explicit null clears the
+// stored value.
+type Nullable[T any] struct {
+	Set   bool
+	Null  bool
+	Value T
+}
+
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	n.Set = true
+
+	if string(data) == "null" {
+		n.Null = true
+		return nil
+	}
+
+	return json.Unmarshal(data, &n.Value)
+}
+
+func (n Nullable[T]) ValidationValue() interface{} {
+	if !n.Set || n.Null {
+		return nil
+	}
+
+	return n.Value
+}
+
+// Get returns the value to store, which is the zero value once cleared.
+func (n Nullable[T]) Get() T {
+	if n.Null {
+		var zero T
+		return zero
+	}
+
+	return n.Value
+}
+
+func (o Optional[T]) ApplyTo(dst *T) {
+	if o.Set {
+		*dst = o.Value
+	}
+}
+
+func (n Nullable[T]) ApplyTo(dst *T) {
+	if n.Set {
+		*dst = n.Get()
+	}
+}
+
+// ApplyToPointer stores the value behind a nullable column mapped to a
+// pointer, an explicit null resets the pointer.
+func (n Nullable[T]) ApplyToPointer(dst **T) {
+	if !n.Set {
+		return
+	}
+
+	if n.Null {
+		*dst = nil
+		return
+	}
+
+	value := n.Value
+	*dst = &value
+}
+
+func NonZero[T comparable](value T) Optional[T] {
+	var zero T
+	return Optional[T]{Set: value != zero, Value: value}
+}
+
+func FromPointer[T any](value *T) Optional[T] {
+	if value == nil {
+		return Optional[T]{}
+	}
+
+	return Optional[T]{Set: true, Value: *value}
+}
+
+func NullableNonZero[T comparable](value T) Nullable[T] {
+	var zero T
+	return Nullable[T]{Set: value != zero, Value: value}
+}
+
+func NullableFromPointer[T any](value *T) Nullable[T] {
+	if value == nil {
+		return Nullable[T]{}
+	}
+
+	return Nullable[T]{Set: true, Value: *value}
+}
@@ -0,0 +1,8 @@
+package schemas
+
+type PurgeResult struct {
+	Events    int `json:"events" example:"1"`
+	Tracks    int `json:"tracks" example:"3"`
+	Timelines int `json:"timelines" example:"12"`
+	Locations int `json:"locations" example:"0"`
+}
@@ -0,0 +1,76 @@
+package schemas
+
+import "time"
+
+const (
+	RescheduleModeShift = "shift"
+	RescheduleModeScale = "scale"
+)
+
+// EventReschedule moves an event together with its tracks, deadlines and
+// agenda sessions.
+// Shift adds Shift (a Go duration such as "168h") to every date, scale maps
+// the event onto DateStart..DateEnd and stretches everything inside it
+// proportionally.
+type EventReschedule struct {
+	Mode      string    `json:"mode" validate:"required,oneof=shift scale" example:"shift"`
+	Shift     string    `json:"shift,omitempty" example:"168h"`
+	DateStart time.Time `json:"date_start,omitempty" example:"2023-01-08T00:00:00Z"`
+	DateEnd   time.Time `json:"date_end,omitempty" example:"2023-01-09T00:00:00Z"`
+}
+
+type ReschedulePlan struct {
+	EventID       int                  `json:"event_id"`
+	Preview       bool                 `json:"preview"`
+	Dates         []DateChange         `json:"dates"`
+	Registrations []RegistrationChange `json:"registrations"`
+	Deadlines     []DeadlineChange     `json:"deadlines"`
+	Overrides     []OverrideChange     `json:"overrides"`
+	Sessions      []SessionChange      `json:"sessions"`
+}
+
+// DateChange describes one entity moved onto a new Date row. NewDateID is
+// only known once the plan has been applied.
+type DateChange struct {
+	Entity       string    `json:"entity"`
+	EntityID     int       `json:"entity_id"`
+	OldDateID    int       `json:"old_date_id"`
+	NewDateID    int       `json:"new_date_id,omitempty"`
+	OldDateStart time.Time `json:"old_date_start"`
+	OldDateEnd   time.Time `json:"old_date_end"`
+	NewDateStart time.Time `json:"new_date_start"`
+	NewDateEnd   time.Time `json:"new_date_end"`
+}
+
+// RegistrationChange moves the registration window of a track. Bounds that
+// are not set stay unset and are omitted.
+type RegistrationChange struct {
+	TrackID     int        `json:"track_id"`
+	OldOpensAt  *time.Time `json:"old_opens_at,omitempty"`
+	OldClosesAt *time.Time `json:"old_closes_at,omitempty"`
+	NewOpensAt  *time.Time `json:"new_opens_at,omitempty"`
+	NewClosesAt *time.Time `json:"new_closes_at,omitempty"`
+}
+
+type SessionChange struct {
+	SessionID    int       `json:"session_id"`
+	OldDateStart time.Time `json:"old_date_start"`
+	OldDateEnd   time.Time `json:"old_date_end"`
+	NewDateStart time.Time `json:"new_date_start"`
+	NewDateEnd   time.Time `json:"new_date_end"`
+}
+
+type DeadlineChange struct {
+	TimelineID  int       `json:"timeline_id"`
+	TrackID     int       `json:"track_id"`
+	OldDeadline time.Time `json:"old_deadline"`
+	NewDeadline time.Time `json:"new_deadline"`
+}
+
+// OverrideChange moves the deadline a single team was given for a timeline.
+type OverrideChange struct {
+	TimelineID  int       `json:"timeline_id"`
+	TrackTeamID int       `json:"track_team_id"`
+	OldDeadline time.Time `json:"old_deadline"`
+	NewDeadline time.Time `json:"new_deadline"`
+}
@@ -0,0 +1,34 @@
+package schemas
+
+import "time"
+
+const (
+	SearchTypeEvent    = "event"
+	SearchTypeTrack    = "track"
+	SearchTypeTimeline = "timeline"
+)
+
+type SearchFilter struct {
+	Query  string    `json:"q" example:"хакатон go"`
+	Types  []string  `json:"types" example:"event,track"`
+	Status string    `json:"status" example:"active"`
+	From   time.Time `json:"from" example:"2025-05-01T00:00:00Z"`
+	To     time.Time `json:"to" example:"2025-06-01T00:00:00Z"`
+	Limit  int       `json:"limit" example:"20"`
+	Offset int       `json:"offset" example:"0"`
+}
+
+// SearchResult is a single match. Timelines report their deadline as both
+// DateStart and DateEnd.
+type SearchResult struct {
+	Type      string     `json:"type" example:"track"`
+	ID        int        `json:"id" example:"7"`
+	EventID   int        `json:"event_id" example:"42"`
+	TrackID   int        `json:"track_id,omitempty" example:"7"`
+	Title     string     `json:"title" example:"Go backend"`
+	Snippet   string     `json:"snippet" example:"Трек по <b>Go</b> для бэкенд-разработчиков"`
+	Status    string     `json:"status,omitempty" example:"active"`
+	DateStart *time.Time `json:"date_start,omitempty" example:"2025-05-01T10:00:00Z"`
+	DateEnd   *time.Time `json:"date_end,omitempty" example:"2025-05-03T18:00:00Z"`
+	Rank      float64    `json:"rank" example:"0.6079"`
+}
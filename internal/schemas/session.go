@@ -0,0 +1,84 @@
+package schemas
+
+import "time"
+
+const (
+	SessionKindCeremony  = "ceremony"
+	SessionKindWorkshop  = "workshop"
+	SessionKindMentoring = "mentoring"
+	SessionKindTalk      = "talk"
+	SessionKindDemo      = "demo"
+	SessionKindOther     = "other"
+)
+
+type Session struct {
+	Title       string    `json:"title" validate:"required,max=255" example:"Opening ceremony"`
+	Description string    `json:"description" example:"Welcome speech and rules"`
+	Kind        string    `json:"kind" validate:"omitempty,oneof=ceremony workshop mentoring talk demo other" example:"ceremony"`
+	DateStart   time.Time `json:"date_start" validate:"required" example:"2025-05-01T10:00:00Z"`
+	DateEnd     time.Time `json:"date_end" validate:"required,gtfield=DateStart" example:"2025-05-01T11:00:00Z"`
+	EventID     int       `json:"event_id" validate:"required" example:"42"`
+	TrackID     int       `json:"track_id" validate:"omitempty,gt=0" example:"42"`
+	LocationID  int       `json:"location_id" validate:"omitempty,gt=0" example:"42"`
+	Attendees   int       `json:"attendees" validate:"omitempty,min=0" example:"80"`
+	SpeakerIDs  []int     `json:"speaker_ids" validate:"omitempty,dive,gt=0" example:"1,2"`
+}
+
+type SessionUpdate struct {
+	Title       string    `json:"title" example:"Opening ceremony"`
+	Description string    `json:"description" example:"Welcome speech and rules"`
+	Kind        string    `json:"kind" validate:"omitempty,oneof=ceremony workshop mentoring talk demo other" example:"ceremony"`
+	DateStart   time.Time `json:"date_start" example:"2025-05-01T10:00:00Z"`
+	DateEnd     time.Time `json:"date_end" example:"2025-05-01T11:00:00Z"`
+	TrackID     *int      `json:"track_id" example:"42"`
+	LocationID  *int      `json:"location_id" example:"42"`
+	Attendees   *int      `json:"attendees" example:"80"`
+}
+
+type SessionPatch struct {
+	Title       Optional[string]    `json:"title" validate:"omitempty,min=1,max=255" example:"Opening ceremony"`
+	Description Nullable[string]    `json:"description" example:"Welcome speech and rules"`
+	Kind        Optional[string]    `json:"kind" validate:"omitempty,oneof=ceremony workshop mentoring talk demo other" example:"ceremony"`
+	DateStart   Optional[time.Time] `json:"date_start" example:"2025-05-01T10:00:00Z"`
+	DateEnd     Optional[time.Time] `json:"date_end" example:"2025-05-01T11:00:00Z"`
+	TrackID     Nullable[int]       `json:"track_id" validate:"omitempty,gt=0" example:"42"`
+	LocationID  Nullable[int]       `json:"location_id" validate:"omitempty,gt=0" example:"42"`
+	Attendees   Nullable[int]       `json:"attendees" validate:"omitempty,min=0" example:"80"`
+}
+
+func (u SessionUpdate) Patch() SessionPatch {
+	return SessionPatch{
+		Title:       NonZero(u.Title),
+		Description: NullableNonZero(u.Description),
+		Kind:        NonZero(u.Kind),
+		DateStart:   NonZero(u.DateStart),
+		DateEnd:     NonZero(u.DateEnd),
+		TrackID:     NullableFromPointer(u.TrackID),
+		LocationID:  NullableFromPointer(u.LocationID),
+		Attendees:   NullableFromPointer(u.Attendees),
+	}
+}
+
+type SessionFilter struct {
+	EventID    int
+	TrackID    int
+	LocationID int
+	From       time.Time
+	To         time.Time
+}
+
+type SessionSpeaker struct {
+	SpeakerID int `json:"speaker_id" validate:"required" example:"1"`
+}
+
+// SessionConflict is a pair of sessions booked into the same location at
+// overlapping times, possibly by different events.
+type SessionConflict struct {
+	LocationID        int       `json:"location_id" example:"42"`
+	SessionID         int       `json:"session_id" example:"1"`
+	EventID           int       `json:"event_id" example:"1"`
+	ConflictSessionID int       `json:"conflict_session_id" example:"2"`
+	ConflictEventID   int       `json:"conflict_event_id" example:"2"`
+	OverlapStart      time.Time `json:"overlap_start" example:"2025-05-01T10:30:00Z"`
+	OverlapEnd        time.Time `json:"overlap_end" example:"2025-05-01T11:00:00Z"`
+}
@@ -0,0 +1,31 @@
+package schemas
+
+type Speaker struct {
+	Name    string `json:"name" validate:"required,max=255" example:"Ivan Petrov"`
+	Bio     string `json:"bio" example:"Backend engineer"`
+	Company string `json:"company" validate:"max=255" example:"TalentHub"`
+	UserID  int    `json:"user_id" validate:"omitempty,gt=0" example:"7"`
+}
+
+type SpeakerUpdate struct {
+	Name    string `json:"name" validate:"max=255" example:"Ivan Petrov"`
+	Bio     string `json:"bio" example:"Backend engineer"`
+	Company string `json:"company" validate:"max=255" example:"TalentHub"`
+	UserID  *int   `json:"user_id" example:"7"`
+}
+
+type SpeakerPatch struct {
+	Name    Optional[string] `json:"name" validate:"omitempty,min=1,max=255" example:"Ivan Petrov"`
+	Bio     Nullable[string] `json:"bio" example:"Backend engineer"`
+	Company Nullable[string] `json:"company" validate:"omitempty,max=255" example:"TalentHub"`
+	UserID  Nullable[int]    `json:"user_id" validate:"omitempty,gt=0" example:"7"`
+}
+
+func (u SpeakerUpdate) Patch() SpeakerPatch {
+	return SpeakerPatch{
+		Name:    NonZero(u.Name),
+		Bio:     NullableNonZero(u.Bio),
+		Company: NullableNonZero(u.Company),
+		UserID:  NullableFromPointer(u.UserID),
+	}
+}
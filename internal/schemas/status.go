@@ -9,3 +9,14 @@ type StatusUpdate struct {
 	Title       string `json:"title" example:"Status Link"`
 	Description string `json:"description" example:"Status Description"`
 }
+
+type StatusPatch struct {
+	Title       Optional[string] `json:"title" validate:"omitempty,min=1,max=255" example:"Status Link"`
+	Description Nullable[string] `json:"description" example:"Status Description"`
+}
+
+// StatusLink is the body for attaching a status to the event or track in the
+// path.
+type StatusLink struct {
+	StatusID int `json:"status_id" validate:"required" example:"1"`
+}
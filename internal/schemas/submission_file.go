@@ -0,0 +1,8 @@
+package schemas
+
+import "time"
+
+type SubmissionLink struct {
+	URL       string    `json:"url" example:"/team-action-status/files/1/download?expires=1746090000&signature=4f2a"`
+	ExpiresAt time.Time `json:"expires_at" example:"2025-05-01T09:00:00Z"`
+}
@@ -6,14 +6,38 @@ type TeamActionStatus struct {
 	TrackTeamID    int       `json:"track_team_id" validate:"required" example:"1"`
 	TimelineID     int       `json:"timeline_id" validate:"required" example:"1"`
 	ResultValue    int       `json:"result_value" validate:"required" example:"600"`
-	ResolutionLink string    `json:"resolution_link" validate:"required" example:"https://www.youtube.com"`
+	ResolutionLink string    `json:"resolution_link" validate:"omitempty,url" example:"https://www.youtube.com"`
 	CompletedAt    time.Time `json:"completed_at" validate:"required" example:"2023-01-02T00:00:00Z"`
 	Notes          string    `json:"notes" validate:"required" example:"Notes"`
 }
 
 type TeamActionStatusUpdate struct {
-	ResultValue    string    `json:"result_value" example:"600"`
+	ResultValue    *int      `json:"result_value" example:"600"`
 	ResolutionLink string    `json:"resolution_link" example:"https://www.youtube.com"`
 	CompletedAt    time.Time `json:"completed_at" example:"2023-01-02T00:00:00Z"`
 	Notes          string    `json:"notes" example:"Notes"`
 }
+
+type TeamActionStatusPatch struct {
+	ResultValue    Optional[int]       `json:"result_value" validate:"omitempty,gte=0" example:"600"`
+	ResolutionLink Nullable[string]    `json:"resolution_link" validate:"omitempty,url" example:"https://www.youtube.com"`
+	CompletedAt    Nullable[time.Time] `json:"completed_at" example:"2023-01-02T00:00:00Z"`
+	Notes          Nullable[string]    `json:"notes" example:"Notes"`
+}
+
+func (u TeamActionStatusUpdate) Patch() TeamActionStatusPatch {
+	return TeamActionStatusPatch{
+		ResultValue:    FromPointer(u.ResultValue),
+		ResolutionLink: NullableNonZero(u.ResolutionLink),
+		CompletedAt:    NullableNonZero(u.CompletedAt),
+		Notes:          NullableNonZero(u.Notes),
+	}
+}
+
+// TeamActionStatusRevisionDiff lists the fields that differ between two
+// revisions, each as an old and new value.
+type TeamActionStatusRevisionDiff struct {
+	From    int                    `json:"from" example:"1"`
+	To      int                    `json:"to" example:"2"`
+	Changes map[string]interface{} `json:"changes"`
+}
@@ -9,6 +9,12 @@ type Timeline struct {
 	IsBlocking  bool      `json:"is_blocking" validate:"omitempty" example:"true"`
 	Status      string    `json:"status" validate:"required" example:"ready"`
 	TrackID     int       `json:"track_id" validate:"required" example:"1"`
+
+	CountedRevision  string `json:"counted_revision" validate:"omitempty,oneof=first last best" example:"last"`
+	LatePolicy       string `json:"late_policy" validate:"omitempty,oneof=reject grace linear stepwise flag" example:"flag"`
+	LateGraceMinutes int    `json:"late_grace_minutes" validate:"gte=0" example:"15"`
+	LatePenalty      int    `json:"late_penalty" validate:"gte=0,lte=100" example:"10"`
+	LateStepMinutes  int    `json:"late_step_minutes" validate:"gte=0" example:"60"`
 }
 
 type TimelineUpdate struct {
@@ -19,4 +25,24 @@ type TimelineUpdate struct {
 	Status           string    `json:"status" example:"ready"`
 	TrackID          int       `json:"track_id" example:"1"`
 	TimelineStatusID int       `json:"timeline_status_id" example:"1"`
+	CountedRevision  string    `json:"counted_revision" validate:"omitempty,oneof=first last best" example:"last"`
+	LatePolicy       string    `json:"late_policy" validate:"omitempty,oneof=reject grace linear stepwise flag" example:"flag"`
+	LateGraceMinutes int       `json:"late_grace_minutes" validate:"gte=0" example:"15"`
+	LatePenalty      int       `json:"late_penalty" validate:"gte=0,lte=100" example:"10"`
+	LateStepMinutes  int       `json:"late_step_minutes" validate:"gte=0" example:"60"`
+}
+
+type TimelinePatch struct {
+	Title            Optional[string]    `json:"title" validate:"omitempty,min=1,max=255" example:"Timeline"`
+	Description      Nullable[string]    `json:"description" example:"Description"`
+	Deadline         Nullable[time.Time] `json:"deadline" example:"2020-01-01T00:00:00Z"`
+	IsBlocking       Optional[bool]      `json:"is_blocking" example:"true"`
+	Status           Optional[string]    `json:"status" validate:"omitempty,oneof=ready expired completed" example:"ready"`
+	TrackID          Optional[int]       `json:"track_id" validate:"omitempty,gt=0" example:"1"`
+	TimelineStatusID Optional[int]       `json:"timeline_status_id" validate:"omitempty,gt=0" example:"1"`
+	CountedRevision  Optional[string]    `json:"counted_revision" validate:"omitempty,oneof=first last best" example:"last"`
+	LatePolicy       Optional[string]    `json:"late_policy" validate:"omitempty,oneof=reject grace linear stepwise flag" example:"flag"`
+	LateGraceMinutes Optional[int]       `json:"late_grace_minutes" validate:"omitempty,gte=0" example:"15"`
+	LatePenalty      Optional[int]       `json:"late_penalty" validate:"omitempty,gte=0,lte=100" example:"10"`
+	LateStepMinutes  Nullable[int]       `json:"late_step_minutes" validate:"omitempty,gt=0" example:"60"`
 }
@@ -0,0 +1,23 @@
+package schemas
+
+import (
+	"event_service/internal/models"
+	"time"
+)
+
+type TimelineOverride struct {
+	Deadline   *time.Time `json:"deadline" validate:"required_without=IsBlocking" example:"2025-05-02T18:00:00Z"`
+	IsBlocking *bool      `json:"is_blocking" example:"false"`
+	Reason     string     `json:"reason" validate:"required,max=1000" example:"Power outage at the team venue"`
+}
+
+// TeamTimeline is a timeline as one team sees it, with its override applied.
+type TeamTimeline struct {
+	TimelineID int                      `json:"timeline_id" example:"1"`
+	Title      string                   `json:"title" example:"Timeline"`
+	Status     string                   `json:"status" example:"ready"`
+	Deadline   time.Time                `json:"deadline" example:"2025-05-02T18:00:00Z"`
+	IsBlocking bool                     `json:"is_blocking" example:"true"`
+	IsExpired  bool                     `json:"is_expired" example:"false"`
+	Override   *models.TimelineOverride `json:"override,omitempty"`
+}
@@ -1,19 +1,61 @@
 package schemas
 
+import "time"
+
 type Track struct {
-	Title        string `json:"title" validate:"required" example:"Track Title"`
-	Description  string `json:"description" validate:"required" example:"Track Description"`
-	IsScoreBased bool   `json:"is_score_based" validate:"omitempty" example:"false"`
-	EventID      int    `json:"event_id" validate:"required" example:"42"`
-	DateID       int    `json:"date_id" validate:"required" example:"42"`
-	Status       string `json:"status" validate:"required" example:"planned"`
+	Title                string    `json:"title" validate:"required" example:"Track Title"`
+	Description          string    `json:"description" validate:"required" example:"Track Description"`
+	IsScoreBased         bool      `json:"is_score_based" validate:"omitempty" example:"false"`
+	EventID              int       `json:"event_id" validate:"required" example:"42"`
+	DateID               int       `json:"date_id" validate:"required" example:"42"`
+	Status               string    `json:"status" validate:"required" example:"planned"`
+	RegistrationOpensAt  time.Time `json:"registration_opens_at" example:"2025-04-01T00:00:00Z"`
+	RegistrationClosesAt time.Time `json:"registration_closes_at" validate:"omitempty,gtfield=RegistrationOpensAt" example:"2025-04-20T00:00:00Z"`
+	MaxTeams             int       `json:"max_teams" validate:"omitempty,gt=0" example:"20"`
 }
 
 type TrackUpdate struct {
-	Title        string `json:"title" example:"Track Title"`
-	Description  string `json:"description" example:"Track Description"`
-	IsScoreBased string `json:"is_score_based" example:"false"`
-	EventID      int    `json:"event_id" example:"42"`
-	DateID       int    `json:"date_id" example:"42"`
-	Status       string `json:"status" example:"planned"`
+	Title                string    `json:"title" example:"Track Title"`
+	Description          string    `json:"description" example:"Track Description"`
+	IsScoreBased         *bool     `json:"is_score_based" example:"false"`
+	EventID              int       `json:"event_id" example:"42"`
+	DateID               int       `json:"date_id" example:"42"`
+	Status               string    `json:"status" example:"planned"`
+	RegistrationOpensAt  time.Time `json:"registration_opens_at" example:"2025-04-01T00:00:00Z"`
+	RegistrationClosesAt time.Time `json:"registration_closes_at" example:"2025-04-20T00:00:00Z"`
+	MaxTeams             int       `json:"max_teams" validate:"omitempty,gt=0" example:"20"`
+}
+
+type TrackPatch struct {
+	Title                Optional[string]    `json:"title" validate:"omitempty,min=1,max=255" example:"Track Title"`
+	Description          Nullable[string]    `json:"description" example:"Track Description"`
+	IsScoreBased         Optional[bool]      `json:"is_score_based" example:"false"`
+	EventID              Optional[int]       `json:"event_id" validate:"omitempty,gt=0" example:"42"`
+	DateID               Optional[int]       `json:"date_id" validate:"omitempty,gt=0" example:"42"`
+	Status               Optional[string]    `json:"status" validate:"omitempty,oneof=planned in_process completed cancelled" example:"planned"`
+	RegistrationOpensAt  Nullable[time.Time] `json:"registration_opens_at" example:"2025-04-01T00:00:00Z"`
+	RegistrationClosesAt Nullable[time.Time] `json:"registration_closes_at" example:"2025-04-20T00:00:00Z"`
+	MaxTeams             Nullable[int]       `json:"max_teams" validate:"omitempty,gt=0" example:"20"`
+}
+
+func (u TrackUpdate) Patch() TrackPatch {
+	return TrackPatch{
+		Title:                NonZero(u.Title),
+		Description:          NullableNonZero(u.Description),
+		IsScoreBased:         FromPointer(u.IsScoreBased),
+		EventID:              NonZero(u.EventID),
+		DateID:               NonZero(u.DateID),
+		Status:               NonZero(u.Status),
+		RegistrationOpensAt:  NullableNonZero(u.RegistrationOpensAt),
+		RegistrationClosesAt: NullableNonZero(u.RegistrationClosesAt),
+		MaxTeams:             NullableNonZero(u.MaxTeams),
+	}
+}
+
+// TrackFilter narrows the track list by event, lifecycle status and attached
+// status labels.
+type TrackFilter struct {
+	EventID  int
+	Status   string
+	StatusID int
 }
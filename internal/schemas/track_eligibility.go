@@ -0,0 +1,31 @@
+package schemas
+
+type TrackEligibility struct {
+	MinTeamSize  int  `json:"min_team_size" validate:"omitempty,gt=0" example:"2"`
+	MaxTeamSize  int  `json:"max_team_size" validate:"omitempty,gt=0,gtefield=MinTeamSize" example:"5"`
+	StudentsOnly bool `json:"students_only" example:"true"`
+	OnePerEvent  bool `json:"one_per_event" example:"true"`
+	NoOverlap    bool `json:"no_overlap" example:"true"`
+}
+
+// TeamRegistration is another registration of a team that may clash with a
+// new one, either by event or by dates.
+type TeamRegistration struct {
+	TrackID   int  `json:"track_id" example:"42"`
+	SameEvent bool `json:"same_event" example:"true"`
+	Overlaps  bool `json:"overlaps" example:"false"`
+}
+
+type EligibilityReason struct {
+	Rule    string `json:"rule" example:"team_size"`
+	Message string `json:"message" example:"team has 6 members, at most 5 are allowed"`
+}
+
+// EligibilityReport is the result of checking one registered team against
+// the current rules of its track.
+type EligibilityReport struct {
+	TeamID   int                 `json:"team_id" example:"1"`
+	State    string              `json:"state" example:"approved"`
+	Eligible bool                `json:"eligible" example:"false"`
+	Reasons  []EligibilityReason `json:"reasons"`
+}
@@ -0,0 +1,50 @@
+package schemas
+
+import "time"
+
+const (
+	SubmissionMissing   = "missing"
+	SubmissionSubmitted = "submitted"
+	SubmissionScored    = "scored"
+)
+
+// StageProgress is one timeline as seen by one team. Score is left out until
+// the caller may see results, RemainingSeconds is zero once the deadline
+// has passed.
+type StageProgress struct {
+	TimelineID       int        `json:"timeline_id" example:"1"`
+	Title            string     `json:"title" example:"Prototype"`
+	Order            int        `json:"order" example:"1"`
+	Status           string     `json:"status" example:"ready"`
+	Deadline         time.Time  `json:"deadline" example:"2025-05-02T18:00:00Z"`
+	IsBlocking       bool       `json:"is_blocking" example:"true"`
+	IsOverridden     bool       `json:"is_overridden" example:"false"`
+	RemainingSeconds int64      `json:"remaining_seconds" example:"86400"`
+	Submission       string     `json:"submission" example:"scored"`
+	SubmittedAt      *time.Time `json:"submitted_at,omitempty" example:"2025-05-01T12:00:00Z"`
+	IsLate           bool       `json:"is_late" example:"false"`
+	Score            *int       `json:"score,omitempty" example:"80"`
+}
+
+type TeamProgress struct {
+	TrackID     int             `json:"track_id" example:"1"`
+	TeamID      int             `json:"team_id" example:"1"`
+	TrackTeamID int             `json:"track_team_id" example:"1"`
+	State       string          `json:"state" example:"approved"`
+	IsActive    bool            `json:"is_active" example:"true"`
+	Stages      []StageProgress `json:"stages"`
+}
+
+type ProgressStage struct {
+	TimelineID int    `json:"timeline_id" example:"1"`
+	Title      string `json:"title" example:"Prototype"`
+	Order      int    `json:"order" example:"1"`
+}
+
+// ProgressMatrix lists every registered team against every stage of a track,
+// Teams[i].Stages follows the order of Stages.
+type ProgressMatrix struct {
+	TrackID int             `json:"track_id" example:"1"`
+	Stages  []ProgressStage `json:"stages"`
+	Teams   []*TeamProgress `json:"teams"`
+}
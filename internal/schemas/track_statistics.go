@@ -0,0 +1,65 @@
+package schemas
+
+import "time"
+
+type TeamCounts struct {
+	Registered int            `json:"registered" example:"24"`
+	Active     int            `json:"active" example:"18"`
+	ByState    map[string]int `json:"by_state"`
+}
+
+type HistogramBucket struct {
+	From  float64 `json:"from" example:"40"`
+	To    float64 `json:"to" example:"50"`
+	Count int     `json:"count" example:"3"`
+}
+
+// Distribution summarizes a set of values. Everything but Count is zero when
+// the set is empty.
+type Distribution struct {
+	Count     int               `json:"count" example:"12"`
+	Min       float64           `json:"min" example:"35"`
+	Max       float64           `json:"max" example:"95"`
+	Mean      float64           `json:"mean" example:"71.5"`
+	Median    float64           `json:"median" example:"74"`
+	P25       float64           `json:"p25" example:"60"`
+	P75       float64           `json:"p75" example:"85"`
+	P90       float64           `json:"p90" example:"91"`
+	Histogram []HistogramBucket `json:"histogram"`
+}
+
+// StageStatistics covers one timeline across the approved teams of a track.
+// Scores are after late penalties, TimeToSubmit holds minutes before the
+// team's own deadline and is negative for late submissions.
+type StageStatistics struct {
+	TimelineID     int          `json:"timeline_id" example:"1"`
+	Title          string       `json:"title" example:"Prototype"`
+	Order          int          `json:"order" example:"1"`
+	IsBlocking     bool         `json:"is_blocking" example:"true"`
+	Teams          int          `json:"teams" example:"18"`
+	Submitted      int          `json:"submitted" example:"15"`
+	Late           int          `json:"late" example:"2"`
+	SubmissionRate float64      `json:"submission_rate" example:"0.83"`
+	Scores         Distribution `json:"scores"`
+	TimeToSubmit   Distribution `json:"time_to_submit"`
+}
+
+// FunnelStep is one blocking stage. Entered teams passed every earlier
+// blocking stage, a team that has not submitted counts as eliminated only
+// once its deadline is over.
+type FunnelStep struct {
+	TimelineID int    `json:"timeline_id" example:"2"`
+	Title      string `json:"title" example:"Prototype"`
+	Entered    int    `json:"entered" example:"18"`
+	Passed     int    `json:"passed" example:"12"`
+	Pending    int    `json:"pending" example:"2"`
+	Eliminated int    `json:"eliminated" example:"4"`
+}
+
+type TrackStatistics struct {
+	TrackID    int               `json:"track_id" example:"1"`
+	ComputedAt time.Time         `json:"computed_at" example:"2025-05-02T18:00:00Z"`
+	Teams      TeamCounts        `json:"teams"`
+	Stages     []StageStatistics `json:"stages"`
+	Funnel     []FunnelStep      `json:"funnel"`
+}
@@ -1,11 +1,40 @@
 package schemas
 
+import (
+	"event_service/internal/models"
+	"event_service/pkg/teams"
+)
+
 type TrackTeam struct {
-	TeamID   int  `json:"team_id" validate:"required" example:"1"`
-	IsActive bool `json:"is_active" validate:"required" example:"true"`
-	TrackID  int  `json:"track_id" validate:"required" example:"1"`
+	TeamID  int `json:"team_id" validate:"required" example:"1"`
+	TrackID int `json:"track_id" validate:"required" example:"1"`
 }
 
 type TrackTeamUpdate struct {
-	IsActive string `json:"is_active" example:"false"`
+	IsActive *bool  `json:"is_active" example:"false"`
+	State    string `json:"state" validate:"omitempty,oneof=approved rejected withdrawn" example:"approved"`
+}
+
+type TrackTeamPatch struct {
+	IsActive Optional[bool]   `json:"is_active" example:"false"`
+	State    Optional[string] `json:"state" validate:"omitempty,oneof=approved rejected withdrawn" example:"approved"`
+}
+
+func (u TrackTeamUpdate) Patch() TrackTeamPatch {
+	return TrackTeamPatch{
+		IsActive: FromPointer(u.IsActive),
+		State:    NonZero(u.State),
+	}
+}
+
+// TrackTeamApproval approves several pending registrations of a track at once.
+type TrackTeamApproval struct {
+	TeamIDs []int `json:"team_ids" validate:"required,min=1,dive,gt=0" example:"1,2,3"`
+}
+
+// RegisteredTeam is a registration optionally enriched with the team as the
+// user and teams service knows it.
+type RegisteredTeam struct {
+	*models.TrackTeam
+	Team *teams.Team `json:"Team,omitempty"`
 }
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"event_service/internal/models"
+	"event_service/internal/repositories"
+	"event_service/internal/schemas"
+	"event_service/pkg/tracing"
+	"event_service/pkg/utils"
+	"fmt"
+	"github.com/go-pg/pg/v10"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	AuditActionCreate     = "create"
+	AuditActionUpdate     = "update"
+	AuditActionDelete     = "delete"
+	AuditActionRestore    = "restore"
+	AuditActionReschedule = "reschedule"
+
+	AuditEntityEvent            = "event"
+	AuditEntityEventLocation    = "event_location"
+	AuditEntityTrack            = "track"
+	AuditEntityLocationTrack    = "location_track"
+	AuditEntityTrackTeam        = "track_team"
+	AuditEntityTrackEligibility = "track_eligibility"
+	AuditEntityTimeline         = "timeline"
+	AuditEntityTimelineStatus   = "timeline_status"
+	AuditEntityTimelineOverride = "timeline_override"
+	AuditEntityLocation         = "location"
+	AuditEntityLocationBooking  = "location_booking"
+	AuditEntityStatus           = "status"
+	AuditEntityStatusEvent      = "status_event"
+	AuditEntityStatusTrack      = "status_track"
+	AuditEntityDate             = "date"
+	AuditEntityTeamActionStatus = "team_action_status"
+	AuditEntitySubmissionFile   = "submission_file"
+	AuditEntityTrackWinner      = "track_winner"
+	AuditEntitySession          = "session"
+	AuditEntitySessionSpeaker   = "session_speaker"
+	AuditEntitySpeaker          = "speaker"
+
+	defaultAuditLimit = 100
+)
+
+type AuditService struct {
+	repo *repositories.AuditRepository
+	db   *pg.DB
+}
+
+type auditChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+func NewAuditService(repo *repositories.AuditRepository, db *pg.DB) *AuditService {
+	return &AuditService{
+		repo: repo,
+		db:   db,
+	}
+}
+
+func AuditID(ids ...int) string {
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		parts = append(parts, strconv.Itoa(id))
+	}
+
+	return strings.Join(parts, ":")
+}
+
+// Record writes an audit entry inside the caller's transaction, so the entry
+// is committed or rolled back together with the change it describes. Either
+// before or after may be nil for creations and deletions.
+func (s *AuditService) Record(ctx context.Context, tx *pg.Tx, entity string, entityId string, action string,
+	before interface{}, after interface{}) error {
+	beforeFields, err := auditFields(before)
+	if err != nil {
+		return err
+	}
+
+	afterFields, err := auditFields(after)
+	if err != nil {
+		return err
+	}
+
+	record := &models.AuditRecord{
+		Entity:    entity,
+		EntityID:  entityId,
+		Action:    action,
+		RequestID: utils.RequestIDFromContext(ctx),
+	}
+
+	if actorId, ok := utils.ActorFromContext(ctx); ok {
+		record.ActorID = &actorId
+	}
+
+	if record.Before, err = marshalAuditFields(beforeFields); err != nil {
+		return err
+	}
+
+	if record.After, err = marshalAuditFields(afterFields); err != nil {
+		return err
+	}
+
+	if record.Diff, err = marshalAuditFields(auditDiff(beforeFields, afterFields)); err != nil {
+		return err
+	}
+
+	_, err = s.repo.Create(tx, record)
+	return err
+}
+
+func (s *AuditService) GetAuditRecords(ctx context.Context, filter *schemas.AuditFilter) (_ []*models.AuditRecord, err error) {
+	ctx, span := tracing.Start(ctx, "AuditService.GetAuditRecords")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultAuditLimit
+	}
+
+	return s.repo.GetAuditRecords(tx, filter)
+}
+
+// auditFields flattens a model into its scalar columns. Relations are
+// skipped because they are audited on their own.
+func auditFields(value interface{}) (map[string]interface{}, error) {
+	if value == nil || (reflect.ValueOf(value).Kind() == reflect.Ptr && reflect.ValueOf(value).IsNil()) {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal audit value: %w", err)
+	}
+
+	fields := make(map[string]interface{})
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal audit value: %w", err)
+	}
+
+	typ := reflect.Indirect(reflect.ValueOf(value)).Type()
+
+	for key, field := range fields {
+		switch field.(type) {
+		case map[string]interface{}, []interface{}:
+			delete(fields, key)
+		case nil:
+			if isRelationField(typ, key) {
+				delete(fields, key)
+			}
+		}
+	}
+
+	return fields, nil
+}
+
+func isRelationField(typ reflect.Type, name string) bool {
+	if typ.Kind() != reflect.Struct {
+		return false
+	}
+
+	field, ok := typ.FieldByName(name)
+	if !ok {
+		return false
+	}
+
+	switch field.Type.Kind() {
+	case reflect.Slice:
+		return true
+	case reflect.Ptr:
+		elem := field.Type.Elem()
+		return elem.Kind() == reflect.Struct && elem != reflect.TypeOf(time.Time{})
+	default:
+		return false
+	}
+}
+
+func auditDiff(before map[string]interface{}, after map[string]interface{}) map[string]interface{} {
+	diff := make(map[string]interface{})
+
+	for key, newValue := range after {
+		oldValue, ok := before[key]
+		if !ok || !reflect.DeepEqual(oldValue, newValue) {
+			diff[key] = auditChange{Old: oldValue, New: newValue}
+		}
+	}
+
+	for key, oldValue := range before {
+		if _, ok := after[key]; !ok {
+			diff[key] = auditChange{Old: oldValue, New: nil}
+		}
+	}
+
+	return diff
+}
+
+func marshalAuditFields(fields map[string]interface{}) (json.RawMessage, error) {
+	if fields == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(fields)
+}
@@ -0,0 +1,378 @@
+package service
+
+import (
+	"context"
+	"event_service/internal/models"
+	"event_service/internal/repositories"
+	"event_service/pkg/ical"
+	"event_service/pkg/tracing"
+	"fmt"
+	"github.com/go-pg/pg/v10"
+	"time"
+)
+
+const (
+	calendarProdID = "-//TalentHub//event_service//RU"
+	calendarDomain = "event-service.talenthub"
+)
+
+type CalendarService struct {
+	eventRepo     *repositories.EventRepository
+	trackRepo     *repositories.TrackRepository
+	timelineRepo  *repositories.TimelineRepository
+	trackTeamRepo *repositories.TrackTeamRepository
+	sessionRepo   *repositories.SessionRepository
+	overrideRepo  *repositories.TimelineOverrideRepository
+
+	db *pg.DB
+}
+
+func NewCalendarService(eventRepo *repositories.EventRepository, trackRepo *repositories.TrackRepository,
+	timelineRepo *repositories.TimelineRepository, trackTeamRepo *repositories.TrackTeamRepository,
+	sessionRepo *repositories.SessionRepository, overrideRepo *repositories.TimelineOverrideRepository,
+	db *pg.DB) *CalendarService {
+	return &CalendarService{
+		eventRepo:     eventRepo,
+		trackRepo:     trackRepo,
+		timelineRepo:  timelineRepo,
+		trackTeamRepo: trackTeamRepo,
+		sessionRepo:   sessionRepo,
+		overrideRepo:  overrideRepo,
+		db:            db,
+	}
+}
+
+func (s *CalendarService) GetEventCalendar(ctx context.Context, eventId int) (_ *ical.Calendar, err error) {
+	ctx, span := tracing.Start(ctx, "CalendarService.GetEventCalendar")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	event, err := s.eventRepo.GetEventWithDateByIDIncludingDeleted(tx, eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	tracks, err := s.trackRepo.GetTracksWithDateByEventIDIncludingDeleted(tx, eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, track := range tracks {
+		track.Event = event
+	}
+
+	calendar := &ical.Calendar{ProdID: calendarProdID, Name: event.Title, TimeZone: event.TimeZone}
+	addEntry(calendar, eventEntry(event))
+
+	// Track sessions are added together with their tracks.
+	sessions, err := s.sessionRepo.GetSessionsByEventID(tx, eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, session := range sessions {
+		if session.TrackID == 0 {
+			addEntry(calendar, sessionEntry(session, nil, event, calendarZone(event.TimeZone)))
+		}
+	}
+
+	if err = s.addTracks(tx, calendar, tracks, nil); err != nil {
+		return nil, err
+	}
+
+	return calendar, nil
+}
+
+func (s *CalendarService) GetTrackCalendar(ctx context.Context, trackId int) (_ *ical.Calendar, err error) {
+	ctx, span := tracing.Start(ctx, "CalendarService.GetTrackCalendar")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	tracks, err := s.trackRepo.GetTracksWithDateByIDsIncludingDeleted(tx, []int{trackId})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tracks) == 0 {
+		return nil, pg.ErrNoRows
+	}
+
+	calendar := &ical.Calendar{ProdID: calendarProdID, Name: tracks[0].Title, TimeZone: calendarTimeZone(tracks)}
+	if err = s.addTracks(tx, calendar, tracks, nil); err != nil {
+		return nil, err
+	}
+
+	return calendar, nil
+}
+
+func (s *CalendarService) GetTeamCalendar(ctx context.Context, teamId int) (_ *ical.Calendar, err error) {
+	ctx, span := tracing.Start(ctx, "CalendarService.GetTeamCalendar")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	trackTeams, err := s.trackTeamRepo.GetTracksByTeamID(tx, teamId)
+	if err != nil {
+		return nil, err
+	}
+
+	trackIds := make([]int, 0, len(trackTeams))
+	trackTeamIds := make([]int, 0, len(trackTeams))
+
+	for _, trackTeam := range trackTeams {
+		if trackTeam.State == models.TrackTeamRejected || trackTeam.State == models.TrackTeamWithdrawn {
+			continue
+		}
+
+		trackIds = append(trackIds, trackTeam.TrackID)
+		trackTeamIds = append(trackTeamIds, trackTeam.ID)
+	}
+
+	overrides, err := s.overrideRepo.GetOverridesByTrackTeamIDs(tx, trackTeamIds)
+	if err != nil {
+		return nil, err
+	}
+
+	tracks, err := s.trackRepo.GetTracksWithDateByIDsIncludingDeleted(tx, trackIds)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := &ical.Calendar{ProdID: calendarProdID, Name: fmt.Sprintf("Team %d", teamId), TimeZone: calendarTimeZone(tracks)}
+	if err = s.addTracks(tx, calendar, tracks, overridesByTimeline(overrides)); err != nil {
+		return nil, err
+	}
+
+	return calendar, nil
+}
+
+// addTracks puts per-team deadlines from overrides, keyed by timeline id, in
+// place of the timeline deadlines. Event and track feeds pass none.
+func (s *CalendarService) addTracks(tx *pg.Tx, calendar *ical.Calendar, tracks []*models.Track,
+	overrides map[int]*models.TimelineOverride) error {
+	trackIds := make([]int, 0, len(tracks))
+	tracksById := make(map[int]*models.Track, len(tracks))
+
+	for _, track := range tracks {
+		trackIds = append(trackIds, track.ID)
+		tracksById[track.ID] = track
+	}
+
+	zones, err := s.trackRepo.GetTimeZones(tx, trackIds)
+	if err != nil {
+		return err
+	}
+
+	for _, track := range tracks {
+		addEntry(calendar, trackEntry(track, calendarZone(zones[track.ID])))
+	}
+
+	timelines, err := s.timelineRepo.GetTimelinesByTrackIDsIncludingDeleted(tx, trackIds)
+	if err != nil {
+		return err
+	}
+
+	for _, timeline := range timelines {
+		timeline = applyOverride(timeline, overrides[timeline.ID])
+		addEntry(calendar, timelineEntry(timeline, tracksById[timeline.TrackID], calendarZone(zones[timeline.TrackID])))
+	}
+
+	sessions, err := s.sessionRepo.GetSessionsByTrackIDs(tx, trackIds)
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		addEntry(calendar, sessionEntry(session, tracksById[session.TrackID], nil, calendarZone(zones[session.TrackID])))
+	}
+
+	return nil
+}
+
+// addEntry leaves out entries without a start, a track or event whose date
+// is not set yet has nothing to put in a calendar.
+func addEntry(calendar *ical.Calendar, entry ical.Event) {
+	if !entry.Start.IsZero() {
+		calendar.Add(entry)
+	}
+}
+
+func calendarUID(kind string, id int) string {
+	return fmt.Sprintf("%s-%d@%s", kind, id, calendarDomain)
+}
+
+// calendarTimeZone names the zone of the first event for feeds made of
+// tracks, which may span several events.
+func calendarTimeZone(tracks []*models.Track) string {
+	for _, track := range tracks {
+		if track.Event != nil {
+			return track.Event.TimeZone
+		}
+	}
+
+	return ""
+}
+
+// calendarZone resolves the zone entries are rendered in. Stored zones are
+// validated on write, so anything unexpected falls back to UTC.
+func calendarZone(name string) *time.Location {
+	location, err := LoadTimeZone(name)
+	if err != nil {
+		return time.UTC
+	}
+
+	return location
+}
+
+// calendarStatus cancels deleted rows as well. Feeds keep them until they
+// are purged, so subscribers drop the entry instead of keeping a stale one.
+func calendarStatus(status string, deletedAt time.Time) string {
+	if status == "cancelled" || !deletedAt.IsZero() {
+		return ical.StatusCancelled
+	}
+
+	return ical.StatusConfirmed
+}
+
+// inherit makes an entry follow the entry of its parent. Versions only grow,
+// so their sum gives a sequence that grows with every change of the entry or
+// of anything above it, a cancelled parent included.
+func inherit(entry *ical.Event, parent ical.Event) {
+	entry.Sequence += parent.Sequence
+
+	if parent.Status == ical.StatusCancelled {
+		entry.Status = ical.StatusCancelled
+	}
+}
+
+func eventEntry(event *models.Event) ical.Event {
+	entry := ical.Event{
+		UID:         calendarUID("event", event.ID),
+		Sequence:    event.Version,
+		Status:      calendarStatus(event.Status, event.DeletedAt),
+		Summary:     event.Title,
+		Description: event.Description,
+		URL:         event.RedirectLink,
+		Categories:  []string{"event"},
+		Stamp:       event.UpdatedAt,
+		TimeZone:    calendarZone(event.TimeZone),
+	}
+
+	if event.Date != nil {
+		entry.Start = event.Date.DateStart
+		entry.End = event.Date.DateEnd
+	}
+
+	return entry
+}
+
+func trackEntry(track *models.Track, zone *time.Location) ical.Event {
+	entry := ical.Event{
+		UID:         calendarUID("track", track.ID),
+		Sequence:    track.Version,
+		Status:      calendarStatus(track.Status, track.DeletedAt),
+		Summary:     track.Title,
+		Description: track.Description,
+		Categories:  []string{"track"},
+		Stamp:       track.UpdatedAt,
+		TimeZone:    zone,
+	}
+
+	if track.Event != nil {
+		entry.Summary = fmt.Sprintf("%s: %s", track.Event.Title, track.Title)
+		inherit(&entry, eventEntry(track.Event))
+	}
+
+	if track.Date != nil {
+		entry.Start = track.Date.DateStart
+		entry.End = track.Date.DateEnd
+	}
+
+	return entry
+}
+
+func timelineEntry(timeline *models.Timeline, track *models.Track, zone *time.Location) ical.Event {
+	entry := ical.Event{
+		UID:         calendarUID("timeline", timeline.ID),
+		Sequence:    timeline.Version,
+		Status:      calendarStatus(timeline.Status, timeline.DeletedAt),
+		Summary:     "Deadline — " + timeline.Title,
+		Description: timeline.Description,
+		Categories:  []string{"deadline"},
+		Start:       timeline.Deadline,
+		Stamp:       timeline.UpdatedAt,
+		TimeZone:    zone,
+	}
+
+	if track != nil {
+		entry.Summary = fmt.Sprintf("Deadline — %s: %s", track.Title, timeline.Title)
+		inherit(&entry, trackEntry(track, zone))
+	}
+
+	return entry
+}
+
+// sessionEntry takes the event of sessions that belong to no track, so they
+// follow it the way track sessions follow their track.
+func sessionEntry(session *models.Session, track *models.Track, event *models.Event, zone *time.Location) ical.Event {
+	entry := ical.Event{
+		UID:         calendarUID("session", session.ID),
+		Sequence:    session.Version,
+		Status:      ical.StatusConfirmed,
+		Summary:     session.Title,
+		Description: session.Description,
+		Categories:  []string{"session", session.Kind},
+		Start:       session.DateStart,
+		End:         session.DateEnd,
+		Stamp:       session.UpdatedAt,
+		TimeZone:    zone,
+	}
+
+	switch {
+	case track != nil:
+		entry.Summary = fmt.Sprintf("%s: %s", track.Title, session.Title)
+		inherit(&entry, trackEntry(track, zone))
+	case event != nil:
+		inherit(&entry, eventEntry(event))
+	}
+
+	return entry
+}
@@ -1,21 +1,34 @@
 package service
 
 import (
+	"context"
 	api "event_service/gen/date"
 	"event_service/internal/models"
 	"event_service/internal/repositories"
+	"event_service/internal/schemas"
+	"event_service/pkg/tracing"
 	"github.com/go-pg/pg/v10"
 )
 
 type DateService struct {
-	repo *repositories.DateRepository
-	db   *pg.DB
+	repo        *repositories.DateRepository
+	sessionRepo *repositories.SessionRepository
+	bookingRepo *repositories.LocationBookingRepository
+	audit       *AuditService
+	schedule    *ScheduleValidator
+	db          *pg.DB
 }
 
-func NewDateService(repo *repositories.DateRepository, db *pg.DB) *DateService {
+func NewDateService(repo *repositories.DateRepository, sessionRepo *repositories.SessionRepository,
+	bookingRepo *repositories.LocationBookingRepository, audit *AuditService, schedule *ScheduleValidator,
+	db *pg.DB) *DateService {
 	return &DateService{
-		repo: repo,
-		db:   db,
+		repo:        repo,
+		sessionRepo: sessionRepo,
+		bookingRepo: bookingRepo,
+		audit:       audit,
+		schedule:    schedule,
+		db:          db,
 	}
 }
 
@@ -37,8 +50,11 @@ func MultipleDateConvert(models []*models.Date) []*api.DateResponse {
 	return responses
 }
 
-func (s *DateService) GetAllDates() ([]*api.DateResponse, error) {
-	tx, err := s.db.Begin()
+func (s *DateService) GetAllDates(ctx context.Context) (_ []*api.DateResponse, err error) {
+	ctx, span := tracing.Start(ctx, "DateService.GetAllDates")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -59,8 +75,11 @@ func (s *DateService) GetAllDates() ([]*api.DateResponse, error) {
 	return MultipleDateConvert(dateModels), nil
 }
 
-func (s *DateService) GetDateByID(id int) (*api.DateResponse, error) {
-	tx, err := s.db.Begin()
+func (s *DateService) GetDateByID(ctx context.Context, id int) (_ *api.DateResponse, err error) {
+	ctx, span := tracing.Start(ctx, "DateService.GetDateByID")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -81,8 +100,11 @@ func (s *DateService) GetDateByID(id int) (*api.DateResponse, error) {
 	return SingleDateConvert(dateModel), nil
 }
 
-func (s *DateService) CreateDate(date api.Date) (*api.DateResponse, error) {
-	tx, err := s.db.Begin()
+func (s *DateService) CreateDate(ctx context.Context, date api.Date) (_ *api.DateResponse, err error) {
+	ctx, span := tracing.Start(ctx, "DateService.CreateDate")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -106,11 +128,29 @@ func (s *DateService) CreateDate(date api.Date) (*api.DateResponse, error) {
 		return nil, err
 	}
 
+	if err = s.schedule.ValidateDate(tx, dateModel.ID); err != nil {
+		return nil, err
+	}
+
+	if err = s.audit.Record(ctx, tx, AuditEntityDate, AuditID(dateModel.ID), AuditActionCreate, nil, dateModel); err != nil {
+		return nil, err
+	}
+
 	return SingleDateConvert(dateModel), nil
 }
 
-func (s *DateService) UpdateDate(id int, date api.DateUpdate) (*api.DateResponse, error) {
-	tx, err := s.db.Begin()
+func (s *DateService) UpdateDate(ctx context.Context, id int, date api.DateUpdate) (*api.DateResponse, error) {
+	return s.PatchDate(ctx, id, schemas.DatePatch{
+		DateStart: schemas.FromPointer(date.DateStart),
+		DateEnd:   schemas.FromPointer(date.DateEnd),
+	})
+}
+
+func (s *DateService) PatchDate(ctx context.Context, id int, patch schemas.DatePatch) (_ *api.DateResponse, err error) {
+	ctx, span := tracing.Start(ctx, "DateService.PatchDate")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -124,25 +164,52 @@ func (s *DateService) UpdateDate(id int, date api.DateUpdate) (*api.DateResponse
 		err = tx.Commit()
 	}()
 
-	if date.DateStart != nil {
-		_, err = s.repo.ChangeDateStart(tx, id, date.DateStart)
-		if err != nil {
+	before, err := s.repo.GetDateById(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if patch.DateStart.Set {
+		if _, err = s.repo.ChangeDateStart(tx, id, &patch.DateStart.Value); err != nil {
 			return nil, err
 		}
 	}
 
-	if date.DateEnd != nil {
-		_, err = s.repo.ChangeDateEnd(tx, id, date.DateEnd)
-		if err != nil {
+	if patch.DateEnd.Set {
+		if _, err = s.repo.ChangeDateEnd(tx, id, &patch.DateEnd.Value); err != nil {
 			return nil, err
 		}
 	}
 
-	return s.GetDateByID(id)
+	after, err := s.repo.GetDateById(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.schedule.ValidateDate(tx, id); err != nil {
+		return nil, err
+	}
+
+	if err = s.bookingRepo.SyncDateBookings(tx, id); err != nil {
+		return nil, err
+	}
+
+	if err = s.repo.BumpOwnerVersions(tx, id); err != nil {
+		return nil, err
+	}
+
+	if err = s.audit.Record(ctx, tx, AuditEntityDate, AuditID(id), AuditActionUpdate, before, after); err != nil {
+		return nil, err
+	}
+
+	return SingleDateConvert(after), nil
 }
 
-func (s *DateService) DeleteDate(id int) error {
-	tx, err := s.db.Begin()
+func (s *DateService) DeleteDate(ctx context.Context, id int) (err error) {
+	ctx, span := tracing.Start(ctx, "DateService.DeleteDate")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return err
 	}
@@ -156,5 +223,41 @@ func (s *DateService) DeleteDate(id int) error {
 		err = tx.Commit()
 	}()
 
-	return s.repo.DeleteDate(tx, id)
+	date, err := s.repo.GetDateById(tx, id)
+	if err != nil {
+		return err
+	}
+
+	if err = s.repo.DeleteDate(tx, id); err != nil {
+		return err
+	}
+
+	return s.audit.Record(ctx, tx, AuditEntityDate, AuditID(id), AuditActionDelete, date, nil)
+}
+
+// GetDateAgenda returns the sessions of every event overlapping the date.
+func (s *DateService) GetDateAgenda(ctx context.Context, id int) (_ []*models.Session, err error) {
+	ctx, span := tracing.Start(ctx, "DateService.GetDateAgenda")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	date, err := s.repo.GetDateById(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.sessionRepo.GetSessions(tx, &schemas.SessionFilter{From: date.DateStart, To: date.DateEnd})
 }
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-pg/pg/v10"
+	"strings"
+)
+
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+	pgExclusionViolation  = "23P01"
+)
+
+var (
+	ErrNotFound    = errors.New("resource not found")
+	ErrConflict    = errors.New("resource conflicts with its current state")
+	ErrValidation  = errors.New("request cannot be applied")
+	ErrForbidden   = errors.New("operation is forbidden")
+	ErrUnavailable = errors.New("dependency is unavailable")
+
+	ErrStaleVersion = errors.New("resource was modified by another request")
+)
+
+// constraintFields names the field behind constraints that do not follow
+// the default naming.
+var constraintFields = map[string]string{
+	"location_booking_no_overlap": "location_id",
+	"session_location_no_overlap": "location_id",
+}
+
+// Error is a domain error of one of the kinds above. Detail and Violations
+// are meant for API clients, the wrapped error is only for logs.
+type Error struct {
+	Kind       error
+	Detail     string
+	Violations []Violation
+	Err        error
+}
+
+type Violation struct {
+	Field   string
+	Rule    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Detail
+	}
+
+	return e.Detail + ": " + e.Err.Error()
+}
+
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func NotFound(format string, args ...interface{}) error {
+	return &Error{Kind: ErrNotFound, Detail: fmt.Sprintf(format, args...)}
+}
+
+func Conflict(format string, args ...interface{}) error {
+	return &Error{Kind: ErrConflict, Detail: fmt.Sprintf(format, args...)}
+}
+
+func Validation(format string, args ...interface{}) error {
+	return &Error{Kind: ErrValidation, Detail: fmt.Sprintf(format, args...)}
+}
+
+func Forbidden(format string, args ...interface{}) error {
+	return &Error{Kind: ErrForbidden, Detail: fmt.Sprintf(format, args...)}
+}
+
+// TranslateError turns go-pg failures into domain errors so that handlers
+// never have to look at driver errors. Anything unknown is returned as is.
+func TranslateError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var domain *Error
+	if errors.As(err, &domain) {
+		return err
+	}
+
+	if errors.Is(err, pg.ErrNoRows) {
+		return &Error{Kind: ErrNotFound, Detail: ErrNotFound.Error(), Err: err}
+	}
+
+	var pgErr pg.Error
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	switch pgErr.Field('C') {
+	case pgUniqueViolation:
+		return constraintError(ErrConflict, "resource already exists", "unique", pgErr, err)
+	case pgForeignKeyViolation:
+		return constraintError(ErrValidation, "referenced resource does not exist", "exists", pgErr, err)
+	case pgExclusionViolation:
+		return constraintError(ErrConflict, "resource is already booked for this time", RuleLocationAvailable, pgErr, err)
+	}
+
+	return err
+}
+
+// constraintError never passes the error detail of Postgres on, it echoes
+// the offending values. The field comes from the constraint name instead.
+func constraintError(kind error, detail string, rule string, pgErr pg.Error, err error) error {
+	domain := &Error{Kind: kind, Detail: detail, Err: err}
+
+	if field := constraintField(pgErr.Field('t'), pgErr.Field('n')); field != "" {
+		domain.Violations = []Violation{{Field: field, Rule: rule, Message: detail}}
+	}
+
+	return domain
+}
+
+// constraintField recovers the column from the names Postgres gives
+// constraints by default, <table>_<column>_key and <table>_<column>_fkey.
+func constraintField(table string, name string) string {
+	if field, ok := constraintFields[name]; ok {
+		return field
+	}
+
+	for _, suffix := range []string{"_fkey", "_key"} {
+		column, ok := strings.CutSuffix(name, suffix)
+		if !ok {
+			continue
+		}
+
+		if column, ok = strings.CutPrefix(column, table+"_"); ok && column != "" {
+			return column
+		}
+	}
+
+	return ""
+}
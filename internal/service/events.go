@@ -1,33 +1,61 @@
 package service
 
 import (
+	"context"
+	"errors"
+	status_api "event_service/gen/status"
 	"event_service/internal/models"
 	"event_service/internal/repositories"
 	"event_service/internal/schemas"
+	"event_service/pkg/tracing"
 	"github.com/go-pg/pg/v10"
+	"time"
 )
 
 type EventService struct {
 	repo *repositories.EventRepository
 
-	trackRepository   *repositories.TrackRepository
-	locationEventRepo *repositories.EventLocationRepository
+	trackRepository    *repositories.TrackRepository
+	timelineRepository *repositories.TimelineRepository
+	overrideRepository *repositories.TimelineOverrideRepository
+	locationEventRepo  *repositories.EventLocationRepository
+	dateRepository     *repositories.DateRepository
+	sessionRepository  *repositories.SessionRepository
+	bookingRepository  *repositories.LocationBookingRepository
+	statusEventRepo    *repositories.StatusEventRepository
+
+	audit    *AuditService
+	schedule *ScheduleValidator
 
 	db *pg.DB
 }
 
 func NewEventsService(repo *repositories.EventRepository, trackRepository *repositories.TrackRepository,
-	locationEventRepo *repositories.EventLocationRepository, db *pg.DB) *EventService {
+	timelineRepository *repositories.TimelineRepository, overrideRepository *repositories.TimelineOverrideRepository,
+	locationEventRepo *repositories.EventLocationRepository, dateRepository *repositories.DateRepository, sessionRepository *repositories.SessionRepository,
+	bookingRepository *repositories.LocationBookingRepository, statusEventRepo *repositories.StatusEventRepository,
+	audit *AuditService, schedule *ScheduleValidator, db *pg.DB) *EventService {
 	return &EventService{
-		repo:              repo,
-		trackRepository:   trackRepository,
-		locationEventRepo: locationEventRepo,
-		db:                db,
+		repo:               repo,
+		trackRepository:    trackRepository,
+		timelineRepository: timelineRepository,
+		overrideRepository: overrideRepository,
+		locationEventRepo:  locationEventRepo,
+		dateRepository:     dateRepository,
+		sessionRepository:  sessionRepository,
+		bookingRepository:  bookingRepository,
+		statusEventRepo:    statusEventRepo,
+		audit:              audit,
+		schedule:           schedule,
+		db:                 db,
 	}
 }
 
-func (s *EventService) GetAllEvents() (_ []*models.Event, err error) {
-	tx, err := s.db.Begin()
+func (s *EventService) GetAllEvents(ctx context.Context, filter *schemas.EventFilter) (_ []*models.Event, err error) {
+	ctx, span := tracing.Start(ctx, "EventService.GetAllEvents")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -40,11 +68,19 @@ func (s *EventService) GetAllEvents() (_ []*models.Event, err error) {
 		err = tx.Commit()
 	}()
 
-	return s.repo.GetAllEvents(tx)
+	events, err := s.repo.GetAllEvents(tx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return localizeEvents(events), nil
 }
 
-func (s *EventService) GetEventByID(eventId int) (_ *models.Event, err error) {
-	tx, err := s.db.Begin()
+func (s *EventService) GetEventByID(ctx context.Context, eventId int) (_ *models.Event, err error) {
+	ctx, span := tracing.Start(ctx, "EventService.GetEventByID")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -57,11 +93,21 @@ func (s *EventService) GetEventByID(eventId int) (_ *models.Event, err error) {
 		err = tx.Commit()
 	}()
 
-	return s.repo.GetEventByID(tx, eventId)
+	event, err := s.repo.GetEventWithDateByID(tx, eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	localizeEvent(event)
+
+	return event, nil
 }
 
-func (s *EventService) GetEventByStatus(status string) (_ []*models.Event, err error) {
-	tx, err := s.db.Begin()
+func (s *EventService) GetEventByStatus(ctx context.Context, status string) (_ []*models.Event, err error) {
+	ctx, span := tracing.Start(ctx, "EventService.GetEventByStatus")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -73,11 +119,19 @@ func (s *EventService) GetEventByStatus(status string) (_ []*models.Event, err e
 		err = tx.Commit()
 	}()
 
-	return s.repo.GetEventByStatus(tx, status)
+	events, err := s.repo.GetEventByStatus(tx, status)
+	if err != nil {
+		return nil, err
+	}
+
+	return localizeEvents(events), nil
 }
 
-func (s *EventService) CreateEvent(event schemas.Event) (_ *models.Event, err error) {
-	tx, err := s.db.Begin()
+func (s *EventService) CreateEvent(ctx context.Context, event schemas.Event) (_ *models.Event, err error) {
+	ctx, span := tracing.Start(ctx, "EventService.CreateEvent")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -96,13 +150,32 @@ func (s *EventService) CreateEvent(event schemas.Event) (_ *models.Event, err er
 		Description:  event.Description,
 		RedirectLink: event.RedirectLink,
 		DateID:       event.DateId,
+		TimeZone:     event.TimeZone,
+	}
+
+	created, err := s.repo.Create(tx, model)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.audit.Record(ctx, tx, AuditEntityEvent, AuditID(created.ID), AuditActionCreate, nil, created); err != nil {
+		return nil, err
 	}
 
-	return s.repo.Create(tx, model)
+	localizeEvent(created)
+
+	return created, nil
+}
+
+func (s *EventService) UpdateEvent(ctx context.Context, eventId int, newEvent schemas.EventUpdate, version int) (*models.Event, error) {
+	return s.PatchEvent(ctx, eventId, newEvent.Patch(), version)
 }
 
-func (s *EventService) UpdateEvent(eventId int, newEvent schemas.EventUpdate) (_ *models.Event, err error) {
-	tx, err := s.db.Begin()
+func (s *EventService) PatchEvent(ctx context.Context, eventId int, patch schemas.EventPatch, version int) (_ *models.Event, err error) {
+	ctx, span := tracing.Start(ctx, "EventService.PatchEvent")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -116,36 +189,51 @@ func (s *EventService) UpdateEvent(eventId int, newEvent schemas.EventUpdate) (_
 		_ = tx.Commit()
 	}()
 
-	event, err := s.GetEventByID(eventId)
+	event, err := s.GetEventByID(ctx, eventId)
 	if err != nil {
 		return nil, err
 	}
 
-	if newEvent.Title != "" {
-		event.Title = newEvent.Title
+	if version > 0 && event.Version != version {
+		return nil, ErrStaleVersion
 	}
 
-	if newEvent.Description != "" {
-		event.Description = newEvent.Description
+	before := *event
+
+	patch.Title.ApplyTo(&event.Title)
+	patch.Description.ApplyTo(&event.Description)
+	patch.RedirectLink.ApplyTo(&event.RedirectLink)
+	patch.DateId.ApplyTo(&event.DateID)
+	patch.Status.ApplyTo(&event.Status)
+	patch.TimeZone.ApplyTo(&event.TimeZone)
+
+	updated, err := s.repo.UpdateEvent(tx, eventId, event, version)
+	if errors.Is(err, pg.ErrNoRows) && version > 0 {
+		return nil, ErrStaleVersion
 	}
 
-	if newEvent.RedirectLink != "" {
-		event.RedirectLink = newEvent.RedirectLink
+	if err != nil {
+		return nil, err
 	}
 
-	if newEvent.DateId != 0 {
-		event.DateID = newEvent.DateId
+	if err = s.schedule.ValidateEvent(tx, eventId); err != nil {
+		return nil, err
 	}
 
-	if newEvent.Status != "" {
-		event.Status = newEvent.Status
+	if err = s.audit.Record(ctx, tx, AuditEntityEvent, AuditID(eventId), AuditActionUpdate, &before, updated); err != nil {
+		return nil, err
 	}
 
-	return s.repo.UpdateEvent(tx, eventId, event)
+	localizeEvent(updated)
+
+	return updated, nil
 }
 
-func (s *EventService) DeleteEvent(eventID int) (err error) {
-	tx, err := s.db.Begin()
+func (s *EventService) DeleteEvent(ctx context.Context, eventID int) (err error) {
+	ctx, span := tracing.Start(ctx, "EventService.DeleteEvent")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return err
 	}
@@ -159,11 +247,114 @@ func (s *EventService) DeleteEvent(eventID int) (err error) {
 		_ = tx.Commit()
 	}()
 
-	return s.repo.DeleteEvent(tx, eventID)
+	event, err := s.repo.GetEventByID(tx, eventID)
+	if err != nil {
+		return err
+	}
+
+	deletedAt := time.Now()
+
+	if err = s.repo.DeleteEvent(tx, eventID, deletedAt); err != nil {
+		return err
+	}
+
+	trackIds, err := s.trackRepository.DeleteTracksByEventID(tx, eventID, deletedAt)
+	if err != nil {
+		return err
+	}
+
+	if err = s.timelineRepository.DeleteTimelinesByTrackIDs(tx, trackIds, deletedAt); err != nil {
+		return err
+	}
+
+	if err = s.bookingRepository.SyncTrackBookings(tx, trackIds); err != nil {
+		return err
+	}
+
+	return s.audit.Record(ctx, tx, AuditEntityEvent, AuditID(eventID), AuditActionDelete, event, nil)
 }
 
-func (s *EventService) GetAllEventsToStart() (_ []*models.Event, err error) {
-	tx, err := s.db.Begin()
+func (s *EventService) GetDeletedEvents(ctx context.Context) (_ []*models.Event, err error) {
+	ctx, span := tracing.Start(ctx, "EventService.GetDeletedEvents")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	events, err := s.repo.GetDeletedEvents(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return localizeEvents(events), nil
+}
+
+func (s *EventService) RestoreEvent(ctx context.Context, eventID int) (_ *models.Event, err error) {
+	ctx, span := tracing.Start(ctx, "EventService.RestoreEvent")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	deleted, err := s.repo.GetDeletedEventByID(tx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := s.repo.RestoreEvent(tx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	trackIds, err := s.trackRepository.RestoreTracksByEventID(tx, eventID, deleted.DeletedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.timelineRepository.RestoreTimelinesByTrackIDs(tx, trackIds, deleted.DeletedAt); err != nil {
+		return nil, err
+	}
+
+	if err = s.bookingRepository.SyncTrackBookings(tx, trackIds); err != nil {
+		return nil, err
+	}
+
+	if err = s.audit.Record(ctx, tx, AuditEntityEvent, AuditID(eventID), AuditActionRestore, deleted, event); err != nil {
+		return nil, err
+	}
+
+	localizeEvent(event)
+
+	return event, nil
+}
+
+func (s *EventService) GetAllEventsToStart(ctx context.Context) (_ []*models.Event, err error) {
+	ctx, span := tracing.Start(ctx, "EventService.GetAllEventsToStart")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -180,8 +371,11 @@ func (s *EventService) GetAllEventsToStart() (_ []*models.Event, err error) {
 	return s.repo.GetAllEventsToStart(tx)
 }
 
-func (s *EventService) GetAllEventsToEnd() (_ []*models.Event, err error) {
-	tx, err := s.db.Begin()
+func (s *EventService) GetAllEventsToEnd(ctx context.Context) (_ []*models.Event, err error) {
+	ctx, span := tracing.Start(ctx, "EventService.GetAllEventsToEnd")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -198,8 +392,11 @@ func (s *EventService) GetAllEventsToEnd() (_ []*models.Event, err error) {
 	return s.repo.GetAllEventsToEnd(tx)
 }
 
-func (s *EventService) StartEvent(eventID int) (_ *models.Event, err error) {
-	tx, err := s.db.Begin()
+func (s *EventService) StartEvent(ctx context.Context, eventID int) (_ *models.Event, err error) {
+	ctx, span := tracing.Start(ctx, "EventService.StartEvent")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -213,11 +410,14 @@ func (s *EventService) StartEvent(eventID int) (_ *models.Event, err error) {
 		_ = tx.Commit()
 	}()
 
-	return s.UpdateEvent(eventID, schemas.EventUpdate{Status: "in_process"})
+	return s.UpdateEvent(ctx, eventID, schemas.EventUpdate{Status: "in_process"}, 0)
 }
 
-func (s *EventService) EndEvent(eventID int) (_ *models.Event, err error) {
-	tx, err := s.db.Begin()
+func (s *EventService) EndEvent(ctx context.Context, eventID int) (_ *models.Event, err error) {
+	ctx, span := tracing.Start(ctx, "EventService.EndEvent")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -231,11 +431,14 @@ func (s *EventService) EndEvent(eventID int) (_ *models.Event, err error) {
 		_ = tx.Commit()
 	}()
 
-	return s.UpdateEvent(eventID, schemas.EventUpdate{Status: "completed"})
+	return s.UpdateEvent(ctx, eventID, schemas.EventUpdate{Status: "completed"}, 0)
 }
 
-func (s *EventService) GetAllEventLocations(eventId int) (_ []*models.Location, err error) {
-	tx, err := s.db.Begin()
+func (s *EventService) GetAllEventLocations(ctx context.Context, eventId int) (_ []*models.Location, err error) {
+	ctx, span := tracing.Start(ctx, "EventService.GetAllEventLocations")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -252,8 +455,11 @@ func (s *EventService) GetAllEventLocations(eventId int) (_ []*models.Location,
 	return s.locationEventRepo.GetAllEventsLocations(tx, eventId)
 }
 
-func (s *EventService) AddLocationToEvent(locationEventSchema *schemas.EventLocation) (_ *models.EventLocation, err error) {
-	tx, err := s.db.Begin()
+func (s *EventService) AddLocationToEvent(ctx context.Context, locationEventSchema *schemas.EventLocation) (_ *models.EventLocation, err error) {
+	ctx, span := tracing.Start(ctx, "EventService.AddLocationToEvent")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -272,11 +478,24 @@ func (s *EventService) AddLocationToEvent(locationEventSchema *schemas.EventLoca
 		LocationID: locationEventSchema.LocationID,
 	}
 
-	return s.locationEventRepo.Create(tx, locationEventModel)
+	created, err := s.locationEventRepo.Create(tx, locationEventModel)
+	if err != nil {
+		return nil, err
+	}
+
+	auditId := AuditID(created.EventID, created.LocationID)
+	if err = s.audit.Record(ctx, tx, AuditEntityEventLocation, auditId, AuditActionCreate, nil, created); err != nil {
+		return nil, err
+	}
+
+	return created, nil
 }
 
-func (s *EventService) RemoveLocationFromEvent(statusEventSchema *schemas.EventLocation) (err error) {
-	tx, err := s.db.Begin()
+func (s *EventService) RemoveLocationFromEvent(ctx context.Context, statusEventSchema *schemas.EventLocation) (err error) {
+	ctx, span := tracing.Start(ctx, "EventService.RemoveLocationFromEvent")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return err
 	}
@@ -290,5 +509,129 @@ func (s *EventService) RemoveLocationFromEvent(statusEventSchema *schemas.EventL
 		_ = tx.Commit()
 	}()
 
-	return s.locationEventRepo.DeleteEventLocation(tx, statusEventSchema.EventID, statusEventSchema.LocationID)
+	if err = s.locationEventRepo.DeleteEventLocation(tx, statusEventSchema.EventID, statusEventSchema.LocationID); err != nil {
+		return err
+	}
+
+	auditId := AuditID(statusEventSchema.EventID, statusEventSchema.LocationID)
+	return s.audit.Record(ctx, tx, AuditEntityEventLocation, auditId, AuditActionDelete, statusEventSchema, nil)
+}
+
+func (s *EventService) GetEventAgenda(ctx context.Context, eventId int) (_ []*models.Session, err error) {
+	ctx, span := tracing.Start(ctx, "EventService.GetEventAgenda")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	if _, err = s.repo.GetEventByID(tx, eventId); err != nil {
+		return nil, err
+	}
+
+	return s.sessionRepository.GetSessions(tx, &schemas.SessionFilter{EventID: eventId})
+}
+
+func (s *EventService) GetEventStatuses(ctx context.Context, eventId int) (_ []*status_api.StatusResponse, err error) {
+	ctx, span := tracing.Start(ctx, "EventService.GetEventStatuses")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	if _, err = s.repo.GetEventByID(tx, eventId); err != nil {
+		return nil, err
+	}
+
+	statuses, err := s.statusEventRepo.GetStatusesByEventID(tx, eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	return MultipleStatusConvert(statuses), nil
+}
+
+func (s *EventService) AddStatusToEvent(ctx context.Context, statusEvent *schemas.StatusEvent) (_ *models.StatusEvent, err error) {
+	ctx, span := tracing.Start(ctx, "EventService.AddStatusToEvent")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	if _, err = s.repo.GetEventByID(tx, statusEvent.EventID); err != nil {
+		return nil, err
+	}
+
+	created, err := s.statusEventRepo.Create(tx, &models.StatusEvent{
+		StatusID: statusEvent.StatusID,
+		EventID:  statusEvent.EventID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	auditId := AuditID(created.EventID, created.StatusID)
+	if err = s.audit.Record(ctx, tx, AuditEntityStatusEvent, auditId, AuditActionCreate, nil, created); err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (s *EventService) RemoveStatusFromEvent(ctx context.Context, statusEvent *schemas.StatusEvent) (err error) {
+	ctx, span := tracing.Start(ctx, "EventService.RemoveStatusFromEvent")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	if err = s.statusEventRepo.DeleteStatusEvent(tx, statusEvent.StatusID, statusEvent.EventID); err != nil {
+		return err
+	}
+
+	auditId := AuditID(statusEvent.EventID, statusEvent.StatusID)
+	return s.audit.Record(ctx, tx, AuditEntityStatusEvent, auditId, AuditActionDelete, statusEvent, nil)
 }
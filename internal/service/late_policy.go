@@ -0,0 +1,63 @@
+package service
+
+import (
+	"event_service/internal/models"
+	"fmt"
+	"time"
+)
+
+const (
+	RuleLatePolicy     = "late_policy"
+	RuleLateSubmission = "late_submission"
+)
+
+// applyLatePolicy marks a result submitted at submittedAt against the policy
+// of its timeline. Rejection only applies to new submissions, so judges can
+// still score a result that an earlier policy let through.
+func applyLatePolicy(timeline *models.Timeline, status *models.TeamActionStatus, submitted bool) error {
+	status.IsLate, status.LateMinutes, status.PenaltyPercent = false, 0, 0
+
+	if timeline.Deadline.IsZero() || !status.SubmittedAt.After(timeline.Deadline) {
+		return nil
+	}
+
+	lateMinutes := int((status.SubmittedAt.Sub(timeline.Deadline) + time.Minute - 1) / time.Minute)
+	overGrace := lateMinutes - timeline.LateGraceMinutes
+
+	status.IsLate = true
+	status.LateMinutes = lateMinutes
+
+	switch timeline.LatePolicy {
+	case models.LatePolicyReject:
+		if submitted {
+			return lateViolation(timeline.ID, fmt.Sprintf("deadline passed %d minutes ago", lateMinutes))
+		}
+	case models.LatePolicyGrace:
+		if submitted && overGrace > 0 {
+			return lateViolation(timeline.ID, fmt.Sprintf("grace period of %d minutes is over", timeline.LateGraceMinutes))
+		}
+	case models.LatePolicyLinear:
+		if overGrace > 0 {
+			status.PenaltyPercent = min(100, overGrace*timeline.LatePenalty/60)
+		}
+	case models.LatePolicyStepwise:
+		if overGrace > 0 && timeline.LateStepMinutes > 0 {
+			steps := (overGrace + timeline.LateStepMinutes - 1) / timeline.LateStepMinutes
+			status.PenaltyPercent = min(100, steps*timeline.LatePenalty)
+		}
+	}
+
+	return nil
+}
+
+func lateViolation(timelineId int, message string) error {
+	return &Error{
+		Kind:   ErrValidation,
+		Detail: fmt.Sprintf("submission is past the deadline of timeline %d", timelineId),
+		Violations: []Violation{{
+			Field:   "submitted_at",
+			Rule:    RuleLateSubmission,
+			Message: message,
+		}},
+	}
+}
@@ -1,21 +1,81 @@
 package service
 
 import (
+	"context"
+	"errors"
 	locationapi "event_service/gen/location"
 	"event_service/internal/models"
 	"event_service/internal/repositories"
+	"event_service/internal/schemas"
+	"event_service/pkg/tracing"
+	"fmt"
 	"github.com/go-pg/pg/v10"
+	"math"
+	"slices"
+	"sort"
+	"time"
+)
+
+const (
+	RuleCoordinates    = "coordinates"
+	RuleStreamRequired = "stream_required"
+	RuleLocationParent = "location_parent"
+	RuleCapacity       = "capacity"
+
+	defaultNearRadiusKm = 10
+	earthRadiusKm       = 6371
+
+	defaultAvailabilityRange = 7 * 24 * time.Hour
 )
 
 type LocationService struct {
-	repo *repositories.LocationRepository
-	db   *pg.DB
+	repo              *repositories.LocationRepository
+	sessionRepo       *repositories.SessionRepository
+	bookingRepo       *repositories.LocationBookingRepository
+	eventLocationRepo *repositories.EventLocationRepository
+	audit             *AuditService
+	db                *pg.DB
 }
 
 func SingleLocationConvert(model *models.Location) *locationapi.LocationResponse {
-	return &locationapi.LocationResponse{
+	response := &locationapi.LocationResponse{
+		Id:    &model.ID,
 		Title: model.Title,
 	}
+
+	if model.TimeZone != "" {
+		response.TimeZone = &model.TimeZone
+	}
+
+	if model.Address != "" {
+		response.Address = &model.Address
+	}
+
+	if model.Capacity != 0 {
+		response.Capacity = &model.Capacity
+	}
+
+	if model.TeamCapacity != 0 {
+		response.TeamCapacity = &model.TeamCapacity
+	}
+
+	if model.Type != "" {
+		locationType := locationapi.LocationType(model.Type)
+		response.Type = &locationType
+	}
+
+	if model.StreamURL != "" {
+		response.StreamUrl = &model.StreamURL
+	}
+
+	if model.ParentID != 0 {
+		response.ParentId = &model.ParentID
+	}
+
+	response.Latitude = model.Latitude
+	response.Longitude = model.Longitude
+
+	return response
 }
 
 func MultipleLocationConvert(models []*models.Location) []*locationapi.LocationResponse {
@@ -28,15 +88,27 @@ func MultipleLocationConvert(models []*models.Location) []*locationapi.LocationR
 	return responses
 }
 
-func NewLocationService(repo *repositories.LocationRepository, db *pg.DB) *LocationService {
+func NewLocationService(repo *repositories.LocationRepository, sessionRepo *repositories.SessionRepository,
+	bookingRepo *repositories.LocationBookingRepository, eventLocationRepo *repositories.EventLocationRepository,
+	audit *AuditService, db *pg.DB) *LocationService {
 	return &LocationService{
-		repo: repo,
-		db:   db,
+		repo:              repo,
+		sessionRepo:       sessionRepo,
+		bookingRepo:       bookingRepo,
+		eventLocationRepo: eventLocationRepo,
+		audit:             audit,
+		db:                db,
 	}
 }
 
-func (s *LocationService) GetAllLocations() (_ []*locationapi.LocationResponse, err error) {
-	tx, err := s.db.Begin()
+// GetAllLocations lists locations, the near search also reports how far
+// each location is from the requested point and which events take place
+// there.
+func (s *LocationService) GetAllLocations(ctx context.Context, filter *schemas.LocationFilter) (_ []*locationapi.LocationResponse, err error) {
+	ctx, span := tracing.Start(ctx, "LocationService.GetAllLocations")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -50,16 +122,49 @@ func (s *LocationService) GetAllLocations() (_ []*locationapi.LocationResponse,
 		err = tx.Commit()
 	}()
 
-	locationModels, err := s.repo.GetAllLocations(tx)
+	near := filter.Latitude != nil && filter.Longitude != nil
+	if near && filter.RadiusKm <= 0 {
+		filter.RadiusKm = defaultNearRadiusKm
+	}
+
+	locationModels, err := s.repo.GetLocations(tx, filter)
 	if err != nil {
 		return nil, err
 	}
 
-	return MultipleLocationConvert(locationModels), nil
+	responses := MultipleLocationConvert(locationModels)
+
+	if !near {
+		return responses, nil
+	}
+
+	locationIds := make([]int, 0, len(locationModels))
+	for _, model := range locationModels {
+		locationIds = append(locationIds, model.ID)
+	}
+
+	eventIds, err := s.eventLocationRepo.GetEventIDsByLocationIDs(tx, locationIds)
+	if err != nil {
+		return nil, err
+	}
+
+	for idx, model := range locationModels {
+		distance := haversineKm(*filter.Latitude, *filter.Longitude, *model.Latitude, *model.Longitude)
+		responses[idx].DistanceKm = &distance
+
+		if ids, ok := eventIds[model.ID]; ok {
+			responses[idx].EventIds = &ids
+		}
+	}
+
+	return responses, nil
 }
 
-func (s *LocationService) GetLocationById(locationId int) (_ *locationapi.LocationResponse, err error) {
-	tx, err := s.db.Begin()
+func (s *LocationService) GetLocationById(ctx context.Context, locationId int) (_ *locationapi.LocationResponse, err error) {
+	ctx, span := tracing.Start(ctx, "LocationService.GetLocationById")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -80,8 +185,11 @@ func (s *LocationService) GetLocationById(locationId int) (_ *locationapi.Locati
 	return SingleLocationConvert(locationModel), nil
 }
 
-func (s *LocationService) CreateLocation(location locationapi.Location) (_ *locationapi.LocationResponse, err error) {
-	tx, err := s.db.Begin()
+func (s *LocationService) CreateLocation(ctx context.Context, location locationapi.Location) (_ *locationapi.LocationResponse, err error) {
+	ctx, span := tracing.Start(ctx, "LocationService.CreateLocation")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -97,6 +205,23 @@ func (s *LocationService) CreateLocation(location locationapi.Location) (_ *loca
 
 	model := &models.Location{
 		Title: location.Title,
+		Type:  models.LocationTypePhysical,
+	}
+
+	applyLocationPatch(model, schemas.LocationPatch{
+		TimeZone:     schemas.NullableFromPointer(location.TimeZone),
+		Address:      schemas.NullableFromPointer(location.Address),
+		Latitude:     schemas.NullableFromPointer(location.Latitude),
+		Longitude:    schemas.NullableFromPointer(location.Longitude),
+		Capacity:     schemas.NullableFromPointer(location.Capacity),
+		TeamCapacity: schemas.NullableFromPointer(location.TeamCapacity),
+		Type:         locationTypePatch(location.Type),
+		StreamURL:    schemas.NullableFromPointer(location.StreamUrl),
+		ParentID:     schemas.NullableFromPointer(location.ParentId),
+	})
+
+	if err = s.validateLocation(tx, model); err != nil {
+		return nil, err
 	}
 
 	locationModel, err := s.repo.Create(tx, model)
@@ -104,11 +229,33 @@ func (s *LocationService) CreateLocation(location locationapi.Location) (_ *loca
 		return nil, err
 	}
 
+	if err = s.audit.Record(ctx, tx, AuditEntityLocation, AuditID(locationModel.ID), AuditActionCreate, nil, locationModel); err != nil {
+		return nil, err
+	}
+
 	return SingleLocationConvert(locationModel), nil
 }
 
-func (s *LocationService) UpdateLocation(locationId int, newLocation locationapi.LocationUpdate) (_ *locationapi.LocationResponse, err error) {
-	tx, err := s.db.Begin()
+func (s *LocationService) UpdateLocation(ctx context.Context, locationId int, newLocation locationapi.LocationUpdate) (*locationapi.LocationResponse, error) {
+	return s.PatchLocation(ctx, locationId, schemas.LocationPatch{
+		Title:        schemas.FromPointer(newLocation.Title),
+		TimeZone:     schemas.NullableFromPointer(newLocation.TimeZone),
+		Address:      schemas.NullableFromPointer(newLocation.Address),
+		Latitude:     schemas.NullableFromPointer(newLocation.Latitude),
+		Longitude:    schemas.NullableFromPointer(newLocation.Longitude),
+		Capacity:     schemas.NullableFromPointer(newLocation.Capacity),
+		TeamCapacity: schemas.NullableFromPointer(newLocation.TeamCapacity),
+		Type:         locationTypePatch(newLocation.Type),
+		StreamURL:    schemas.NullableFromPointer(newLocation.StreamUrl),
+		ParentID:     schemas.NullableFromPointer(newLocation.ParentId),
+	})
+}
+
+func (s *LocationService) PatchLocation(ctx context.Context, locationId int, patch schemas.LocationPatch) (_ *locationapi.LocationResponse, err error) {
+	ctx, span := tracing.Start(ctx, "LocationService.PatchLocation")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -122,29 +269,35 @@ func (s *LocationService) UpdateLocation(locationId int, newLocation locationapi
 		_ = tx.Commit()
 	}()
 
-	location, err := s.GetLocationById(locationId)
+	before, err := s.repo.GetLocationById(tx, locationId)
 	if err != nil {
 		return nil, err
 	}
 
-	if newLocation.Title != nil {
-		location.Title = *newLocation.Title
-	}
+	model := *before
+	applyLocationPatch(&model, patch)
 
-	model := &models.Location{
-		Title: location.Title,
+	if err = s.validateLocation(tx, &model); err != nil {
+		return nil, err
 	}
 
-	locationModel, err := s.repo.Update(tx, locationId, model)
+	locationModel, err := s.repo.Update(tx, locationId, &model)
 	if err != nil {
 		return nil, err
 	}
 
+	if err = s.audit.Record(ctx, tx, AuditEntityLocation, AuditID(locationId), AuditActionUpdate, before, locationModel); err != nil {
+		return nil, err
+	}
+
 	return SingleLocationConvert(locationModel), nil
 }
 
-func (s *LocationService) DeleteLocation(locationId int) (err error) {
-	tx, err := s.db.Begin()
+func (s *LocationService) DeleteLocation(ctx context.Context, locationId int) (err error) {
+	ctx, span := tracing.Start(ctx, "LocationService.DeleteLocation")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return err
 	}
@@ -158,5 +311,453 @@ func (s *LocationService) DeleteLocation(locationId int) (err error) {
 		_ = tx.Commit()
 	}()
 
-	return s.repo.DeleteLocation(tx, locationId)
+	location, err := s.repo.GetLocationById(tx, locationId)
+	if err != nil {
+		return err
+	}
+
+	if err = s.repo.DeleteLocation(tx, locationId); err != nil {
+		return err
+	}
+
+	return s.audit.Record(ctx, tx, AuditEntityLocation, AuditID(locationId), AuditActionDelete, location, nil)
+}
+
+func (s *LocationService) GetDeletedLocations(ctx context.Context) (_ []*locationapi.LocationResponse, err error) {
+	ctx, span := tracing.Start(ctx, "LocationService.GetDeletedLocations")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	locationModels, err := s.repo.GetDeletedLocations(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return MultipleLocationConvert(locationModels), nil
+}
+
+func (s *LocationService) RestoreLocation(ctx context.Context, locationId int) (_ *locationapi.LocationResponse, err error) {
+	ctx, span := tracing.Start(ctx, "LocationService.RestoreLocation")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	locationModel, err := s.repo.RestoreLocation(tx, locationId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.audit.Record(ctx, tx, AuditEntityLocation, AuditID(locationId), AuditActionRestore, nil, locationModel); err != nil {
+		return nil, err
+	}
+
+	return SingleLocationConvert(locationModel), nil
+}
+
+// GetLocationAgenda returns the sessions booked into the location by any
+// event, optionally limited to those overlapping from..to.
+func (s *LocationService) GetLocationAgenda(ctx context.Context, locationId int, from time.Time, to time.Time) (_ []*models.Session, err error) {
+	ctx, span := tracing.Start(ctx, "LocationService.GetLocationAgenda")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	if _, err = s.repo.GetLocationById(tx, locationId); err != nil {
+		return nil, err
+	}
+
+	return s.sessionRepo.GetSessions(tx, &schemas.SessionFilter{LocationID: locationId, From: from, To: to})
+}
+
+func (s *LocationService) GetLocationBookings(ctx context.Context, locationId int, from time.Time, to time.Time) (_ []*models.LocationBooking, err error) {
+	ctx, span := tracing.Start(ctx, "LocationService.GetLocationBookings")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	if _, err = s.repo.GetLocationById(tx, locationId); err != nil {
+		return nil, err
+	}
+
+	return s.bookingRepo.GetBookings(tx, locationId, from, to)
+}
+
+// CreateLocationBooking reserves the location outside of any track, for
+// example for setup or a third-party rental.
+func (s *LocationService) CreateLocationBooking(ctx context.Context, locationId int,
+	booking schemas.LocationBooking) (_ *models.LocationBooking, err error) {
+	ctx, span := tracing.Start(ctx, "LocationService.CreateLocationBooking")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	if _, err = s.repo.GetLocationById(tx, locationId); err != nil {
+		return nil, err
+	}
+
+	created, err := s.bookingRepo.Create(tx, &models.LocationBooking{
+		Source:     models.BookingSourceManual,
+		Title:      booking.Title,
+		DateStart:  booking.DateStart,
+		DateEnd:    booking.DateEnd,
+		LocationID: locationId,
+		EventID:    booking.EventID,
+		TrackID:    booking.TrackID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.audit.Record(ctx, tx, AuditEntityLocationBooking, AuditID(created.ID), AuditActionCreate, nil, created); err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// DeleteLocationBooking only removes manual bookings, track bookings follow
+// the track's locations and date.
+func (s *LocationService) DeleteLocationBooking(ctx context.Context, locationId int, bookingId int) (err error) {
+	ctx, span := tracing.Start(ctx, "LocationService.DeleteLocationBooking")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	booking, err := s.bookingRepo.GetBookingByID(tx, locationId, bookingId)
+	if err != nil {
+		return err
+	}
+
+	if booking.Source != models.BookingSourceManual {
+		return Conflict("booking %d belongs to track %d, remove the location from the track instead", bookingId, booking.TrackID)
+	}
+
+	if err = s.bookingRepo.DeleteBooking(tx, bookingId); err != nil {
+		return err
+	}
+
+	return s.audit.Record(ctx, tx, AuditEntityLocationBooking, AuditID(bookingId), AuditActionDelete, booking, nil)
+}
+
+// GetLocationAvailability returns free slots of at least minDuration between
+// from and to. Both bookings and sessions make the location busy. The range
+// defaults to a week from now.
+func (s *LocationService) GetLocationAvailability(ctx context.Context, locationId int, from time.Time, to time.Time,
+	minDuration time.Duration) (_ *schemas.LocationAvailability, err error) {
+	ctx, span := tracing.Start(ctx, "LocationService.GetLocationAvailability")
+	defer func() { tracing.End(span, err) }()
+
+	if from.IsZero() {
+		from = time.Now().UTC().Truncate(time.Minute)
+	}
+
+	if to.IsZero() {
+		to = from.Add(defaultAvailabilityRange)
+	}
+
+	if !to.After(from) {
+		return nil, Validation("availability range must end after it starts")
+	}
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	if _, err = s.repo.GetLocationById(tx, locationId); err != nil {
+		return nil, err
+	}
+
+	bookings, err := s.bookingRepo.GetBookings(tx, locationId, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.sessionRepo.GetSessions(tx, &schemas.SessionFilter{LocationID: locationId, From: from, To: to})
+	if err != nil {
+		return nil, err
+	}
+
+	taken := make([]schemas.TimeSlot, 0, len(bookings)+len(sessions))
+	for _, booking := range bookings {
+		taken = append(taken, schemas.TimeSlot{DateStart: booking.DateStart, DateEnd: booking.DateEnd})
+	}
+
+	for _, session := range sessions {
+		taken = append(taken, schemas.TimeSlot{DateStart: session.DateStart, DateEnd: session.DateEnd})
+	}
+
+	busy := mergeSlots(from, to, taken)
+
+	return &schemas.LocationAvailability{
+		LocationID: locationId,
+		From:       from,
+		To:         to,
+		Busy:       busy,
+		Free:       freeSlots(from, to, busy, minDuration),
+	}, nil
+}
+
+// mergeSlots clips the slots to from..to and joins the ones that overlap or
+// touch, returning them in order.
+func mergeSlots(from time.Time, to time.Time, slots []schemas.TimeSlot) []schemas.TimeSlot {
+	sort.Slice(slots, func(i, j int) bool { return slots[i].DateStart.Before(slots[j].DateStart) })
+
+	merged := make([]schemas.TimeSlot, 0, len(slots))
+	for _, slot := range slots {
+		if slot.DateStart.Before(from) {
+			slot.DateStart = from
+		}
+
+		if slot.DateEnd.After(to) {
+			slot.DateEnd = to
+		}
+
+		if !slot.DateEnd.After(slot.DateStart) {
+			continue
+		}
+
+		if last := len(merged) - 1; last >= 0 && !slot.DateStart.After(merged[last].DateEnd) {
+			if slot.DateEnd.After(merged[last].DateEnd) {
+				merged[last].DateEnd = slot.DateEnd
+			}
+
+			continue
+		}
+
+		merged = append(merged, slot)
+	}
+
+	return merged
+}
+
+func freeSlots(from time.Time, to time.Time, busy []schemas.TimeSlot, minDuration time.Duration) []schemas.TimeSlot {
+	free := make([]schemas.TimeSlot, 0, len(busy)+1)
+
+	cursor := from
+	for _, slot := range append(busy, schemas.TimeSlot{DateStart: to, DateEnd: to}) {
+		if slot.DateStart.Sub(cursor) > 0 && slot.DateStart.Sub(cursor) >= minDuration {
+			free = append(free, schemas.TimeSlot{DateStart: cursor, DateEnd: slot.DateStart})
+		}
+
+		cursor = slot.DateEnd
+	}
+
+	return free
+}
+
+func applyLocationPatch(model *models.Location, patch schemas.LocationPatch) {
+	patch.Title.ApplyTo(&model.Title)
+	patch.TimeZone.ApplyTo(&model.TimeZone)
+	patch.Address.ApplyTo(&model.Address)
+	patch.Latitude.ApplyToPointer(&model.Latitude)
+	patch.Longitude.ApplyToPointer(&model.Longitude)
+	patch.Capacity.ApplyTo(&model.Capacity)
+	patch.TeamCapacity.ApplyTo(&model.TeamCapacity)
+	patch.Type.ApplyTo(&model.Type)
+	patch.StreamURL.ApplyTo(&model.StreamURL)
+	patch.ParentID.ApplyTo(&model.ParentID)
+}
+
+func locationTypePatch(value *locationapi.LocationType) schemas.Optional[string] {
+	if value == nil {
+		return schemas.Optional[string]{}
+	}
+
+	return schemas.Optional[string]{Set: true, Value: string(*value)}
+}
+
+// validateLocation checks the rules that span several fields. PUT and POST
+// bodies are not validated by tags, so ranges are checked here as well.
+func (s *LocationService) validateLocation(tx *pg.Tx, model *models.Location) error {
+	if err := validateTimeZone(model.TimeZone); err != nil {
+		return err
+	}
+
+	violations := make([]Violation, 0)
+
+	switch model.Type {
+	case models.LocationTypePhysical:
+	case models.LocationTypeOnline, models.LocationTypeHybrid:
+		if model.StreamURL == "" {
+			violations = append(violations, Violation{
+				Field:   "stream_url",
+				Rule:    RuleStreamRequired,
+				Message: fmt.Sprintf("%s location needs a stream_url", model.Type),
+			})
+		}
+	default:
+		violations = append(violations, Violation{
+			Field:   "type",
+			Rule:    "oneof",
+			Message: "type must be one of physical, online, hybrid",
+		})
+	}
+
+	switch {
+	case (model.Latitude == nil) != (model.Longitude == nil):
+		violations = append(violations, Violation{
+			Field:   "latitude",
+			Rule:    RuleCoordinates,
+			Message: "latitude and longitude must be set together",
+		})
+	case model.Latitude != nil && (math.Abs(*model.Latitude) > 90 || math.Abs(*model.Longitude) > 180):
+		violations = append(violations, Violation{
+			Field:   "latitude",
+			Rule:    RuleCoordinates,
+			Message: "latitude must be within ±90 and longitude within ±180",
+		})
+	}
+
+	if model.Capacity < 0 || model.TeamCapacity < 0 {
+		violations = append(violations, Violation{
+			Field:   "capacity",
+			Rule:    RuleCapacity,
+			Message: "capacity cannot be negative",
+		})
+	}
+
+	if model.ParentID != 0 {
+		violation, err := s.parentViolation(tx, model)
+		if err != nil {
+			return err
+		}
+
+		if violation != nil {
+			violations = append(violations, *violation)
+		}
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+
+	return &Error{
+		Kind:       ErrValidation,
+		Detail:     "location is inconsistent",
+		Violations: violations,
+	}
+}
+
+func (s *LocationService) parentViolation(tx *pg.Tx, model *models.Location) (*Violation, error) {
+	violation := &Violation{Field: "parent_id", Rule: RuleLocationParent}
+
+	if _, err := s.repo.GetLocationById(tx, model.ParentID); err != nil {
+		if !errors.Is(err, pg.ErrNoRows) {
+			return nil, err
+		}
+
+		violation.Message = fmt.Sprintf("parent location %d does not exist", model.ParentID)
+		return violation, nil
+	}
+
+	if model.ID == 0 {
+		return nil, nil
+	}
+
+	ancestors, err := s.repo.GetAncestorIDs(tx, model.ParentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if slices.Contains(ancestors, model.ID) {
+		violation.Message = fmt.Sprintf("location %d cannot be nested inside its own descendant %d", model.ID, model.ParentID)
+		return violation, nil
+	}
+
+	return nil, nil
+}
+
+func haversineKm(lat1 float64, lon1 float64, lat2 float64, lon2 float64) float64 {
+	toRadians := func(degrees float64) float64 { return degrees * math.Pi / 180 }
+
+	dLat := toRadians(lat2 - lat1)
+	dLon := toRadians(lon2 - lon1)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRadians(lat1))*math.Cos(toRadians(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
 }
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"event_service/internal/repositories"
+	"event_service/internal/schemas"
+	"event_service/pkg/tracing"
+	"github.com/go-pg/pg/v10"
+)
+
+type MetricsService struct {
+	repo *repositories.MetricsRepository
+
+	db *pg.DB
+}
+
+func NewMetricsService(repo *repositories.MetricsRepository, db *pg.DB) *MetricsService {
+	return &MetricsService{
+		repo: repo,
+		db:   db,
+	}
+}
+
+// GetBusinessMetrics counts live events, tracks and registrations for the
+// metrics gauges.
+func (s *MetricsService) GetBusinessMetrics(ctx context.Context) (_ *schemas.BusinessMetrics, err error) {
+	ctx, span := tracing.Start(ctx, "MetricsService.GetBusinessMetrics")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	events, err := s.repo.CountEventsByStatus(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	tracks, err := s.repo.CountTracksByStatus(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	registrations, err := s.repo.CountRegistrationsByState(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &schemas.BusinessMetrics{
+		EventsByStatus:       statusCounts(events),
+		TracksByStatus:       statusCounts(tracks),
+		RegistrationsByState: statusCounts(registrations),
+	}, nil
+}
+
+func statusCounts(counts []*repositories.StatusCount) map[string]int {
+	result := make(map[string]int, len(counts))
+	for _, count := range counts {
+		result[count.Status] = count.Count
+	}
+
+	return result
+}
@@ -0,0 +1,346 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"event_service/internal/models"
+	"event_service/internal/schemas"
+	"event_service/pkg/tracing"
+	"github.com/go-pg/pg/v10"
+	"time"
+)
+
+// RescheduleEvent moves an event, its tracks with their registration windows,
+// their timeline deadlines with the per-team deadline overrides and the
+// agenda sessions in one transaction. Date rows can be shared between
+// events, so every edited date is cloned and the event and tracks are pointed
+// at the clones. With preview set nothing is written and the plan only shows
+// what would change.
+func (s *EventService) RescheduleEvent(ctx context.Context, eventId int, request schemas.EventReschedule, version int,
+	preview bool) (_ *schemas.ReschedulePlan, err error) {
+	ctx, span := tracing.Start(ctx, "EventService.RescheduleEvent")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil || preview {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	event, err := s.repo.GetEventWithDateByID(tx, eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	if version > 0 && event.Version != version {
+		return nil, ErrStaleVersion
+	}
+
+	if !hasDate(event.Date) {
+		return nil, Validation("event %d has no date to reschedule", eventId)
+	}
+
+	move, err := rescheduleFunc(event.Date, request)
+	if err != nil {
+		return nil, err
+	}
+
+	tracks, err := s.trackRepository.GetTracksWithDateByEventID(tx, eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	trackIds := make([]int, 0, len(tracks))
+	for _, track := range tracks {
+		trackIds = append(trackIds, track.ID)
+	}
+
+	timelines, err := s.timelineRepository.GetTimelinesByTrackIDs(tx, trackIds)
+	if err != nil {
+		return nil, err
+	}
+
+	timelineIds := make([]int, 0, len(timelines))
+	for _, timeline := range timelines {
+		timelineIds = append(timelineIds, timeline.ID)
+	}
+
+	overrides, err := s.overrideRepository.GetDeadlineOverridesByTimelineIDs(tx, timelineIds)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.sessionRepository.GetSessions(tx, &schemas.SessionFilter{EventID: eventId})
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &schemas.ReschedulePlan{
+		EventID:       eventId,
+		Preview:       preview,
+		Dates:         []schemas.DateChange{dateChange(AuditEntityEvent, event.ID, event.Date, move)},
+		Registrations: make([]schemas.RegistrationChange, 0),
+		Deadlines:     make([]schemas.DeadlineChange, 0, len(timelines)),
+		Overrides:     make([]schemas.OverrideChange, 0, len(overrides)),
+		Sessions:      make([]schemas.SessionChange, 0, len(sessions)),
+	}
+
+	for _, track := range tracks {
+		if hasDate(track.Date) {
+			plan.Dates = append(plan.Dates, dateChange(AuditEntityTrack, track.ID, track.Date, move))
+		}
+
+		if !track.RegistrationOpensAt.IsZero() || !track.RegistrationClosesAt.IsZero() {
+			plan.Registrations = append(plan.Registrations, schemas.RegistrationChange{
+				TrackID:     track.ID,
+				OldOpensAt:  optionalTime(track.RegistrationOpensAt),
+				OldClosesAt: optionalTime(track.RegistrationClosesAt),
+				NewOpensAt:  optionalTime(moveSet(track.RegistrationOpensAt, move)),
+				NewClosesAt: optionalTime(moveSet(track.RegistrationClosesAt, move)),
+			})
+		}
+	}
+
+	for _, timeline := range timelines {
+		plan.Deadlines = append(plan.Deadlines, schemas.DeadlineChange{
+			TimelineID:  timeline.ID,
+			TrackID:     timeline.TrackID,
+			OldDeadline: timeline.Deadline,
+			NewDeadline: move(timeline.Deadline),
+		})
+	}
+
+	for _, override := range overrides {
+		plan.Overrides = append(plan.Overrides, schemas.OverrideChange{
+			TimelineID:  override.TimelineID,
+			TrackTeamID: override.TrackTeamID,
+			OldDeadline: override.Deadline,
+			NewDeadline: move(override.Deadline),
+		})
+	}
+
+	for _, session := range sessions {
+		plan.Sessions = append(plan.Sessions, schemas.SessionChange{
+			SessionID:    session.ID,
+			OldDateStart: session.DateStart,
+			OldDateEnd:   session.DateEnd,
+			NewDateStart: move(session.DateStart),
+			NewDateEnd:   move(session.DateEnd),
+		})
+	}
+
+	if preview {
+		return plan, nil
+	}
+
+	if err = s.applyReschedule(ctx, tx, event, tracks, timelines, overrides, sessions, plan, version); err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+func (s *EventService) applyReschedule(ctx context.Context, tx *pg.Tx, event *models.Event, tracks []*models.Track,
+	timelines []*models.Timeline, overrides []*models.TimelineOverride, sessions []*models.Session, plan *schemas.ReschedulePlan,
+	version int) error {
+	clones := make(map[int]int)
+
+	for i := range plan.Dates {
+		change := &plan.Dates[i]
+
+		cloneId, ok := clones[change.OldDateID]
+		if !ok {
+			clone, err := s.dateRepository.Create(tx, &models.Date{DateStart: change.NewDateStart, DateEnd: change.NewDateEnd})
+			if err != nil {
+				return err
+			}
+
+			cloneId = clone.ID
+			clones[change.OldDateID] = cloneId
+		}
+
+		change.NewDateID = cloneId
+	}
+
+	before := *event
+	event.DateID = clones[event.DateID]
+
+	updated, err := s.repo.UpdateEvent(tx, event.ID, event, version)
+	if errors.Is(err, pg.ErrNoRows) && version > 0 {
+		return ErrStaleVersion
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err = s.audit.Record(ctx, tx, AuditEntityEvent, AuditID(event.ID), AuditActionReschedule, &before, updated); err != nil {
+		return err
+	}
+
+	registrations := make(map[int]schemas.RegistrationChange, len(plan.Registrations))
+	for _, change := range plan.Registrations {
+		registrations[change.TrackID] = change
+	}
+
+	for _, track := range tracks {
+		registration, moveRegistration := registrations[track.ID]
+		if !hasDate(track.Date) && !moveRegistration {
+			continue
+		}
+
+		before := *track
+		if hasDate(track.Date) {
+			track.DateID = clones[track.DateID]
+		}
+
+		if moveRegistration {
+			track.RegistrationOpensAt = timeValue(registration.NewOpensAt)
+			track.RegistrationClosesAt = timeValue(registration.NewClosesAt)
+		}
+
+		updated, err := s.trackRepository.UpdateTrack(tx, track.ID, track, 0)
+		if err != nil {
+			return err
+		}
+
+		if err = s.audit.Record(ctx, tx, AuditEntityTrack, AuditID(track.ID), AuditActionReschedule, &before, updated); err != nil {
+			return err
+		}
+	}
+
+	for i, timeline := range timelines {
+		before := *timeline
+		timeline.Deadline = plan.Deadlines[i].NewDeadline
+
+		updated, err := s.timelineRepository.UpdateTimeline(tx, timeline.ID, timeline, 0)
+		if err != nil {
+			return err
+		}
+
+		if err = s.audit.Record(ctx, tx, AuditEntityTimeline, AuditID(timeline.ID), AuditActionReschedule, &before, updated); err != nil {
+			return err
+		}
+	}
+
+	for i, override := range overrides {
+		updated, err := s.overrideRepository.UpdateDeadline(tx, override.ID, plan.Overrides[i].NewDeadline)
+		if err != nil {
+			return err
+		}
+
+		auditId := AuditID(override.TimelineID, override.TrackTeamID)
+		if err = s.audit.Record(ctx, tx, AuditEntityTimelineOverride, auditId, AuditActionReschedule, override, updated); err != nil {
+			return err
+		}
+	}
+
+	moved := make([]*models.Session, 0, len(sessions))
+	for i, session := range sessions {
+		before := *session
+		session.DateStart = plan.Sessions[i].NewDateStart
+		session.DateEnd = plan.Sessions[i].NewDateEnd
+
+		updated, err := s.sessionRepository.UpdateSession(tx, session.ID, session)
+		if err != nil {
+			return err
+		}
+
+		if err = s.audit.Record(ctx, tx, AuditEntitySession, AuditID(session.ID), AuditActionReschedule, &before, updated); err != nil {
+			return err
+		}
+
+		moved = append(moved, updated)
+	}
+
+	trackIds := make([]int, 0, len(tracks))
+	for _, track := range tracks {
+		trackIds = append(trackIds, track.ID)
+	}
+
+	if err = s.bookingRepository.SyncTrackBookings(tx, trackIds); err != nil {
+		return err
+	}
+
+	if err = checkLocationConflicts(tx, s.sessionRepository, s.bookingRepository, moved...); err != nil {
+		return err
+	}
+
+	return s.schedule.ValidateEvent(tx, event.ID)
+}
+
+func rescheduleFunc(date *models.Date, request schemas.EventReschedule) (func(time.Time) time.Time, error) {
+	switch request.Mode {
+	case schemas.RescheduleModeShift:
+		shift, err := time.ParseDuration(request.Shift)
+		if err != nil {
+			return nil, Validation("invalid shift %q, expected a duration such as 168h", request.Shift)
+		}
+
+		return func(t time.Time) time.Time {
+			return t.Add(shift)
+		}, nil
+	case schemas.RescheduleModeScale:
+		if request.DateStart.IsZero() || !request.DateEnd.After(request.DateStart) {
+			return nil, Validation("scaling requires date_start and a later date_end")
+		}
+
+		length := date.DateEnd.Sub(date.DateStart)
+		if length <= 0 {
+			return nil, Validation("event date %d is empty and cannot be scaled", date.ID)
+		}
+
+		ratio := float64(request.DateEnd.Sub(request.DateStart)) / float64(length)
+
+		return func(t time.Time) time.Time {
+			return request.DateStart.Add(time.Duration(float64(t.Sub(date.DateStart)) * ratio)).Round(time.Second)
+		}, nil
+	default:
+		return nil, Validation("unknown reschedule mode %q", request.Mode)
+	}
+}
+
+// moveSet moves t unless it is unset.
+func moveSet(t time.Time, move func(time.Time) time.Time) time.Time {
+	if t.IsZero() {
+		return t
+	}
+
+	return move(t)
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
+}
+
+func timeValue(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+
+	return *t
+}
+
+func dateChange(entity string, entityId int, date *models.Date, move func(time.Time) time.Time) schemas.DateChange {
+	return schemas.DateChange{
+		Entity:       entity,
+		EntityID:     entityId,
+		OldDateID:    date.ID,
+		OldDateStart: date.DateStart,
+		OldDateEnd:   date.DateEnd,
+		NewDateStart: move(date.DateStart),
+		NewDateEnd:   move(date.DateEnd),
+	}
+}
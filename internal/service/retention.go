@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"event_service/internal/repositories"
+	"event_service/internal/schemas"
+	"event_service/pkg/blob"
+	"event_service/pkg/tracing"
+	"github.com/go-pg/pg/v10"
+	"time"
+)
+
+type RetentionService struct {
+	eventRepo    *repositories.EventRepository
+	trackRepo    *repositories.TrackRepository
+	timelineRepo *repositories.TimelineRepository
+	locationRepo *repositories.LocationRepository
+	fileRepo     *repositories.SubmissionFileRepository
+
+	blobs  blob.Store
+	period time.Duration
+
+	db *pg.DB
+}
+
+func NewRetentionService(eventRepo *repositories.EventRepository, trackRepo *repositories.TrackRepository,
+	timelineRepo *repositories.TimelineRepository, locationRepo *repositories.LocationRepository,
+	fileRepo *repositories.SubmissionFileRepository, blobs blob.Store, period time.Duration, db *pg.DB) *RetentionService {
+	return &RetentionService{
+		eventRepo:    eventRepo,
+		trackRepo:    trackRepo,
+		timelineRepo: timelineRepo,
+		locationRepo: locationRepo,
+		fileRepo:     fileRepo,
+		blobs:        blobs,
+		period:       period,
+		db:           db,
+	}
+}
+
+// PurgeDeleted permanently removes soft deleted rows older than the retention
+// period. Children are purged before their parents to satisfy foreign keys.
+// Submission files go with their rows, their blobs are removed after commit.
+func (s *RetentionService) PurgeDeleted(ctx context.Context) (_ *schemas.PurgeResult, err error) {
+	ctx, span := tracing.Start(ctx, "RetentionService.PurgeDeleted")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0)
+
+	// Registered before the commit below, so it runs once the rows are gone.
+	defer func() {
+		if err == nil {
+			s.removeBlobs(keys)
+		}
+	}()
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	before := time.Now().Add(-s.period)
+	result := &schemas.PurgeResult{}
+
+	if keys, err = s.fileRepo.GetPurgedStorageKeys(tx, before); err != nil {
+		return nil, err
+	}
+
+	if result.Timelines, err = s.timelineRepo.PurgeDeletedBefore(tx, before); err != nil {
+		return nil, err
+	}
+
+	if result.Tracks, err = s.trackRepo.PurgeDeletedBefore(tx, before); err != nil {
+		return nil, err
+	}
+
+	if result.Events, err = s.eventRepo.PurgeDeletedBefore(tx, before); err != nil {
+		return nil, err
+	}
+
+	if result.Locations, err = s.locationRepo.PurgeDeletedBefore(tx, before); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// removeBlobs ignores failures the way TeamActionStatusService.removeBlobs
+// does, a blob left behind is only wasted space.
+func (s *RetentionService) removeBlobs(keys []string) {
+	for _, key := range keys {
+		_ = s.blobs.Delete(context.Background(), key)
+	}
+}
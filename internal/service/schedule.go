@@ -0,0 +1,307 @@
+package service
+
+import (
+	"event_service/internal/models"
+	"event_service/internal/repositories"
+	"event_service/internal/schemas"
+	"fmt"
+	"github.com/go-pg/pg/v10"
+	"time"
+)
+
+const (
+	RuleDateRange       = "date_range"
+	RuleWithinEvent     = "within_event"
+	RuleWithinTrack     = "within_track"
+	RuleOrderedDeadline = "ordered_deadline"
+	RuleSameEvent       = "same_event"
+)
+
+// ScheduleValidator checks that dates nest properly: a track runs inside its
+// event, timeline deadlines and agenda sessions fall inside their track, and
+// deadlines follow the order of timeline statuses. It is run inside the caller's transaction after
+// the change is written, so it always sees the state that would be committed.
+type ScheduleValidator struct {
+	dateRepo     *repositories.DateRepository
+	eventRepo    *repositories.EventRepository
+	trackRepo    *repositories.TrackRepository
+	timelineRepo *repositories.TimelineRepository
+	sessionRepo  *repositories.SessionRepository
+}
+
+func NewScheduleValidator(dateRepo *repositories.DateRepository, eventRepo *repositories.EventRepository,
+	trackRepo *repositories.TrackRepository, timelineRepo *repositories.TimelineRepository,
+	sessionRepo *repositories.SessionRepository) *ScheduleValidator {
+	return &ScheduleValidator{
+		dateRepo:     dateRepo,
+		eventRepo:    eventRepo,
+		trackRepo:    trackRepo,
+		timelineRepo: timelineRepo,
+		sessionRepo:  sessionRepo,
+	}
+}
+
+func (v *ScheduleValidator) ValidateDate(tx *pg.Tx, dateId int) error {
+	date, err := v.dateRepo.GetDateById(tx, dateId)
+	if err != nil {
+		return err
+	}
+
+	var violations []Violation
+	if date.DateEnd.Before(date.DateStart) {
+		violations = append(violations, Violation{
+			Field:   fmt.Sprintf("date[%d].date_end", date.ID),
+			Rule:    RuleDateRange,
+			Message: fmt.Sprintf("date %d ends before it starts", date.ID),
+		})
+	}
+
+	trackIds, err := v.trackRepo.GetTrackIDsByDateID(tx, dateId)
+	if err != nil {
+		return err
+	}
+
+	trackViolations, err := v.trackViolations(tx, trackIds)
+	if err != nil {
+		return err
+	}
+
+	return scheduleError(append(violations, trackViolations...))
+}
+
+func (v *ScheduleValidator) ValidateEvent(tx *pg.Tx, eventId int) error {
+	tracks, err := v.trackRepo.GetAllTracksByEventID(tx, eventId)
+	if err != nil {
+		return err
+	}
+
+	trackIds := make([]int, 0, len(tracks))
+	for _, track := range tracks {
+		trackIds = append(trackIds, track.ID)
+	}
+
+	violations, err := v.trackViolations(tx, trackIds)
+	if err != nil {
+		return err
+	}
+
+	sessions, err := v.sessionRepo.GetSessions(tx, &schemas.SessionFilter{EventID: eventId})
+	if err != nil {
+		return err
+	}
+
+	sessionViolations, err := v.sessionViolations(tx, sessions)
+	if err != nil {
+		return err
+	}
+
+	return scheduleError(append(violations, sessionViolations...))
+}
+
+func (v *ScheduleValidator) ValidateSessions(tx *pg.Tx, sessions ...*models.Session) error {
+	violations, err := v.sessionViolations(tx, sessions)
+	if err != nil {
+		return err
+	}
+
+	return scheduleError(violations)
+}
+
+func (v *ScheduleValidator) ValidateTracks(tx *pg.Tx, trackIds ...int) error {
+	violations, err := v.trackViolations(tx, trackIds)
+	if err != nil {
+		return err
+	}
+
+	return scheduleError(violations)
+}
+
+func (v *ScheduleValidator) trackViolations(tx *pg.Tx, trackIds []int) ([]Violation, error) {
+	tracks, err := v.trackRepo.GetTracksWithDateByIDs(tx, trackIds)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(map[int]*models.Event)
+	violations := make([]Violation, 0)
+
+	for _, track := range tracks {
+		event, ok := events[track.EventID]
+		if !ok {
+			if event, err = v.eventRepo.GetEventWithDateByID(tx, track.EventID); err != nil {
+				return nil, err
+			}
+
+			events[track.EventID] = event
+		}
+
+		if hasDate(track.Date) && hasDate(event.Date) && !dateWithin(track.Date, event.Date) {
+			violations = append(violations, Violation{
+				Field: fmt.Sprintf("track[%d].date_id", track.ID),
+				Rule:  RuleWithinEvent,
+				Message: fmt.Sprintf("track %d runs %s, outside of event %d dates %s",
+					track.ID, formatDate(track.Date), event.ID, formatDate(event.Date)),
+			})
+		}
+
+		timelines, err := v.timelineRepo.GetTimelinesByTrackID(tx, track.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		violations = append(violations, timelineViolations(track, timelines)...)
+	}
+
+	sessions, err := v.sessionRepo.GetSessionsByTrackIDs(tx, trackIds)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionViolations, err := v.sessionViolations(tx, sessions)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(violations, sessionViolations...), nil
+}
+
+// sessionViolations checks that every session runs inside its event and, if
+// it belongs to one, inside a track of the same event.
+func (v *ScheduleValidator) sessionViolations(tx *pg.Tx, sessions []*models.Session) ([]Violation, error) {
+	events := make(map[int]*models.Event)
+	trackIds := make([]int, 0, len(sessions))
+	violations := make([]Violation, 0)
+
+	for _, session := range sessions {
+		if session.TrackID != 0 {
+			trackIds = append(trackIds, session.TrackID)
+		}
+	}
+
+	tracks, err := v.trackRepo.GetTracksWithDateByIDs(tx, trackIds)
+	if err != nil {
+		return nil, err
+	}
+
+	tracksById := make(map[int]*models.Track, len(tracks))
+	for _, track := range tracks {
+		tracksById[track.ID] = track
+	}
+
+	for _, session := range sessions {
+		period := &models.Date{DateStart: session.DateStart, DateEnd: session.DateEnd}
+
+		if !session.DateEnd.After(session.DateStart) {
+			violations = append(violations, Violation{
+				Field:   fmt.Sprintf("session[%d].date_end", session.ID),
+				Rule:    RuleDateRange,
+				Message: fmt.Sprintf("session %d does not end after it starts", session.ID),
+			})
+		}
+
+		event, ok := events[session.EventID]
+		if !ok {
+			if event, err = v.eventRepo.GetEventWithDateByID(tx, session.EventID); err != nil {
+				return nil, err
+			}
+
+			events[session.EventID] = event
+		}
+
+		if hasDate(event.Date) && !dateWithin(period, event.Date) {
+			violations = append(violations, Violation{
+				Field: fmt.Sprintf("session[%d].date_start", session.ID),
+				Rule:  RuleWithinEvent,
+				Message: fmt.Sprintf("session %d runs %s, outside of event %d dates %s",
+					session.ID, formatDate(period), event.ID, formatDate(event.Date)),
+			})
+		}
+
+		track, ok := tracksById[session.TrackID]
+		if !ok {
+			continue
+		}
+
+		if track.EventID != session.EventID {
+			violations = append(violations, Violation{
+				Field:   fmt.Sprintf("session[%d].track_id", session.ID),
+				Rule:    RuleSameEvent,
+				Message: fmt.Sprintf("track %d belongs to event %d, not to event %d", track.ID, track.EventID, session.EventID),
+			})
+		}
+
+		if hasDate(track.Date) && !dateWithin(period, track.Date) {
+			violations = append(violations, Violation{
+				Field: fmt.Sprintf("session[%d].date_start", session.ID),
+				Rule:  RuleWithinTrack,
+				Message: fmt.Sprintf("session %d runs %s, outside of track %d dates %s",
+					session.ID, formatDate(period), track.ID, formatDate(track.Date)),
+			})
+		}
+	}
+
+	return violations, nil
+}
+
+// timelineViolations expects timelines in the order of their statuses, as
+// returned by TimelineRepository.GetTimelinesByTrackID.
+func timelineViolations(track *models.Track, timelines []*models.Timeline) []Violation {
+	violations := make([]Violation, 0)
+
+	var previous *models.Timeline
+	for _, timeline := range timelines {
+		if timeline.Deadline.IsZero() {
+			continue
+		}
+
+		field := fmt.Sprintf("timeline[%d].deadline", timeline.ID)
+
+		if hasDate(track.Date) && (timeline.Deadline.Before(track.Date.DateStart) || timeline.Deadline.After(track.Date.DateEnd)) {
+			violations = append(violations, Violation{
+				Field: field,
+				Rule:  RuleWithinTrack,
+				Message: fmt.Sprintf("timeline %d deadline %s is outside of track %d dates %s",
+					timeline.ID, timeline.Deadline.Format(time.RFC3339), track.ID, formatDate(track.Date)),
+			})
+		}
+
+		if previous != nil && timeline.Deadline.Before(previous.Deadline) {
+			violations = append(violations, Violation{
+				Field: field,
+				Rule:  RuleOrderedDeadline,
+				Message: fmt.Sprintf("timeline %d deadline %s is earlier than deadline %s of preceding timeline %d",
+					timeline.ID, timeline.Deadline.Format(time.RFC3339), previous.Deadline.Format(time.RFC3339), previous.ID),
+			})
+		}
+
+		previous = timeline
+	}
+
+	return violations
+}
+
+// hasDate reports whether a Date relation was actually joined, a missing
+// date_id leaves it nil or empty depending on the query.
+func hasDate(date *models.Date) bool {
+	return date != nil && date.ID != 0
+}
+
+func dateWithin(inner *models.Date, outer *models.Date) bool {
+	return !inner.DateStart.Before(outer.DateStart) && !inner.DateEnd.After(outer.DateEnd)
+}
+
+func formatDate(date *models.Date) string {
+	return date.DateStart.Format(time.RFC3339) + " - " + date.DateEnd.Format(time.RFC3339)
+}
+
+func scheduleError(violations []Violation) error {
+	if len(violations) == 0 {
+		return nil
+	}
+
+	return &Error{
+		Kind:       ErrValidation,
+		Detail:     "schedule dates are inconsistent",
+		Violations: violations,
+	}
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"event_service/internal/repositories"
+	"event_service/internal/schemas"
+	"event_service/pkg/tracing"
+	"github.com/go-pg/pg/v10"
+	"slices"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+var searchTypes = []string{schemas.SearchTypeEvent, schemas.SearchTypeTrack, schemas.SearchTypeTimeline}
+
+type SearchService struct {
+	repo *repositories.SearchRepository
+	db   *pg.DB
+}
+
+func NewSearchService(repo *repositories.SearchRepository, db *pg.DB) *SearchService {
+	return &SearchService{
+		repo: repo,
+		db:   db,
+	}
+}
+
+func (s *SearchService) Search(ctx context.Context, filter *schemas.SearchFilter) (_ []*schemas.SearchResult, err error) {
+	ctx, span := tracing.Start(ctx, "SearchService.Search")
+	defer func() { tracing.End(span, err) }()
+
+	if filter.Query == "" {
+		return nil, Validation("search query is empty")
+	}
+
+	if len(filter.Types) == 0 {
+		filter.Types = searchTypes
+	}
+
+	for _, searchType := range filter.Types {
+		if !slices.Contains(searchTypes, searchType) {
+			return nil, Validation("unknown search type %q, expected event, track or timeline", searchType)
+		}
+	}
+
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.To.Before(filter.From) {
+		return nil, Validation("search range must end after it starts")
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultSearchLimit
+	}
+
+	filter.Limit = min(filter.Limit, maxSearchLimit)
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	return s.repo.Search(tx, filter)
+}
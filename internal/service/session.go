@@ -0,0 +1,410 @@
+package service
+
+import (
+	"context"
+	"event_service/internal/models"
+	"event_service/internal/repositories"
+	"event_service/internal/schemas"
+	"event_service/pkg/tracing"
+	"fmt"
+	"github.com/go-pg/pg/v10"
+	"time"
+)
+
+const RuleLocationAvailable = "location_available"
+
+type SessionService struct {
+	repo *repositories.SessionRepository
+
+	sessionSpeakerRepo *repositories.SessionSpeakerRepository
+	locationRepo       *repositories.LocationRepository
+	bookingRepo        *repositories.LocationBookingRepository
+
+	audit    *AuditService
+	schedule *ScheduleValidator
+
+	db *pg.DB
+}
+
+func NewSessionService(repo *repositories.SessionRepository, sessionSpeakerRepo *repositories.SessionSpeakerRepository,
+	locationRepo *repositories.LocationRepository, bookingRepo *repositories.LocationBookingRepository, audit *AuditService,
+	schedule *ScheduleValidator, db *pg.DB) *SessionService {
+	return &SessionService{
+		repo:               repo,
+		sessionSpeakerRepo: sessionSpeakerRepo,
+		locationRepo:       locationRepo,
+		bookingRepo:        bookingRepo,
+		audit:              audit,
+		schedule:           schedule,
+		db:                 db,
+	}
+}
+
+func (s *SessionService) GetSessions(ctx context.Context, filter *schemas.SessionFilter) (_ []*models.Session, err error) {
+	ctx, span := tracing.Start(ctx, "SessionService.GetSessions")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	return s.repo.GetSessions(tx, filter)
+}
+
+func (s *SessionService) GetSessionById(ctx context.Context, sessionId int) (_ *models.Session, err error) {
+	ctx, span := tracing.Start(ctx, "SessionService.GetSessionById")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	return s.repo.GetSessionByID(tx, sessionId)
+}
+
+func (s *SessionService) GetConflicts(ctx context.Context, filter *schemas.SessionFilter) (_ []*schemas.SessionConflict, err error) {
+	ctx, span := tracing.Start(ctx, "SessionService.GetConflicts")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	return s.repo.GetConflicts(tx, filter)
+}
+
+func (s *SessionService) CreateSession(ctx context.Context, session schemas.Session) (_ *models.Session, err error) {
+	ctx, span := tracing.Start(ctx, "SessionService.CreateSession")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	model := &models.Session{
+		Title:       session.Title,
+		Description: session.Description,
+		Kind:        session.Kind,
+		DateStart:   session.DateStart,
+		DateEnd:     session.DateEnd,
+		EventID:     session.EventID,
+		TrackID:     session.TrackID,
+		LocationID:  session.LocationID,
+		Attendees:   session.Attendees,
+	}
+
+	if model.Kind == "" {
+		model.Kind = schemas.SessionKindOther
+	}
+
+	created, err := s.repo.Create(tx, model)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.schedule.ValidateSessions(tx, created); err != nil {
+		return nil, err
+	}
+
+	if err = checkLocationConflicts(tx, s.repo, s.bookingRepo, created); err != nil {
+		return nil, err
+	}
+
+	if err = s.checkCapacity(tx, created); err != nil {
+		return nil, err
+	}
+
+	if err = s.audit.Record(ctx, tx, AuditEntitySession, AuditID(created.ID), AuditActionCreate, nil, created); err != nil {
+		return nil, err
+	}
+
+	for _, speakerId := range session.SpeakerIDs {
+		if err = s.addSpeaker(ctx, tx, created.ID, speakerId); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.repo.GetSessionByID(tx, created.ID)
+}
+
+func (s *SessionService) UpdateSession(ctx context.Context, sessionId int, newSession schemas.SessionUpdate) (*models.Session, error) {
+	return s.PatchSession(ctx, sessionId, newSession.Patch())
+}
+
+func (s *SessionService) PatchSession(ctx context.Context, sessionId int, patch schemas.SessionPatch) (_ *models.Session, err error) {
+	ctx, span := tracing.Start(ctx, "SessionService.PatchSession")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	session, err := s.repo.GetSessionByID(tx, sessionId)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *session
+
+	patch.Title.ApplyTo(&session.Title)
+	patch.Description.ApplyTo(&session.Description)
+	patch.Kind.ApplyTo(&session.Kind)
+	patch.DateStart.ApplyTo(&session.DateStart)
+	patch.DateEnd.ApplyTo(&session.DateEnd)
+	patch.TrackID.ApplyTo(&session.TrackID)
+	patch.LocationID.ApplyTo(&session.LocationID)
+	patch.Attendees.ApplyTo(&session.Attendees)
+
+	updated, err := s.repo.UpdateSession(tx, sessionId, session)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.schedule.ValidateSessions(tx, updated); err != nil {
+		return nil, err
+	}
+
+	if err = checkLocationConflicts(tx, s.repo, s.bookingRepo, updated); err != nil {
+		return nil, err
+	}
+
+	if err = s.checkCapacity(tx, updated); err != nil {
+		return nil, err
+	}
+
+	if err = s.audit.Record(ctx, tx, AuditEntitySession, AuditID(sessionId), AuditActionUpdate, &before, updated); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetSessionByID(tx, sessionId)
+}
+
+func (s *SessionService) DeleteSession(ctx context.Context, sessionId int) (err error) {
+	ctx, span := tracing.Start(ctx, "SessionService.DeleteSession")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	session, err := s.repo.GetSessionByID(tx, sessionId)
+	if err != nil {
+		return err
+	}
+
+	if err = s.repo.DeleteSession(tx, sessionId); err != nil {
+		return err
+	}
+
+	return s.audit.Record(ctx, tx, AuditEntitySession, AuditID(sessionId), AuditActionDelete, session, nil)
+}
+
+func (s *SessionService) AddSpeaker(ctx context.Context, sessionId int, speakerId int) (_ *models.Session, err error) {
+	ctx, span := tracing.Start(ctx, "SessionService.AddSpeaker")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	if _, err = s.repo.GetSessionByID(tx, sessionId); err != nil {
+		return nil, err
+	}
+
+	if err = s.addSpeaker(ctx, tx, sessionId, speakerId); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetSessionByID(tx, sessionId)
+}
+
+func (s *SessionService) RemoveSpeaker(ctx context.Context, sessionId int, speakerId int) (err error) {
+	ctx, span := tracing.Start(ctx, "SessionService.RemoveSpeaker")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	if err = s.sessionSpeakerRepo.DeleteSessionSpeaker(tx, sessionId, speakerId); err != nil {
+		return err
+	}
+
+	sessionSpeaker := &models.SessionSpeaker{SessionID: sessionId, SpeakerID: speakerId}
+	auditId := AuditID(sessionId, speakerId)
+	return s.audit.Record(ctx, tx, AuditEntitySessionSpeaker, auditId, AuditActionDelete, sessionSpeaker, nil)
+}
+
+func (s *SessionService) addSpeaker(ctx context.Context, tx *pg.Tx, sessionId int, speakerId int) error {
+	created, err := s.sessionSpeakerRepo.Create(tx, &models.SessionSpeaker{SessionID: sessionId, SpeakerID: speakerId})
+	if err != nil {
+		return err
+	}
+
+	auditId := AuditID(sessionId, speakerId)
+	return s.audit.Record(ctx, tx, AuditEntitySessionSpeaker, auditId, AuditActionCreate, nil, created)
+}
+
+// checkLocationConflicts rejects sessions that share a location with a session
+// of any event at an overlapping time, or that fall into a booking of another
+// event or track. It runs after the sessions are written, so sessions moved
+// together are checked against each other's new times.
+func checkLocationConflicts(tx *pg.Tx, repo *repositories.SessionRepository, bookingRepo *repositories.LocationBookingRepository,
+	sessions ...*models.Session) error {
+	violations := make([]Violation, 0)
+
+	for _, session := range sessions {
+		if session.LocationID == 0 {
+			continue
+		}
+
+		overlapping, err := repo.GetOverlapping(tx, session.LocationID, session.DateStart, session.DateEnd, session.ID)
+		if err != nil {
+			return err
+		}
+
+		for _, other := range overlapping {
+			violations = append(violations, Violation{
+				Field: fmt.Sprintf("session[%d].location_id", session.ID),
+				Rule:  RuleLocationAvailable,
+				Message: fmt.Sprintf("location %d is booked by session %d of event %d from %s to %s",
+					other.LocationID, other.ID, other.EventID,
+					other.DateStart.Format(time.RFC3339), other.DateEnd.Format(time.RFC3339)),
+			})
+		}
+
+		bookings, err := bookingRepo.GetConflicting(tx, session.LocationID, session.DateStart, session.DateEnd,
+			session.EventID, session.TrackID)
+		if err != nil {
+			return err
+		}
+
+		for _, booking := range bookings {
+			violations = append(violations, Violation{
+				Field: fmt.Sprintf("session[%d].location_id", session.ID),
+				Rule:  RuleLocationAvailable,
+				Message: fmt.Sprintf("location %d is reserved by booking %d %q from %s to %s",
+					booking.LocationID, booking.ID, booking.Title,
+					booking.DateStart.Format(time.RFC3339), booking.DateEnd.Format(time.RFC3339)),
+			})
+		}
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+
+	return &Error{
+		Kind:       ErrConflict,
+		Detail:     "location is double-booked",
+		Violations: violations,
+	}
+}
+
+// checkCapacity rejects sessions expecting more attendees than the location
+// seats. Locations without a capacity are not limited.
+func (s *SessionService) checkCapacity(tx *pg.Tx, session *models.Session) error {
+	if session.LocationID == 0 || session.Attendees == 0 {
+		return nil
+	}
+
+	location, err := s.locationRepo.GetLocationById(tx, session.LocationID)
+	if err != nil {
+		return err
+	}
+
+	if location.Capacity == 0 || session.Attendees <= location.Capacity {
+		return nil
+	}
+
+	return &Error{
+		Kind:   ErrValidation,
+		Detail: "location capacity exceeded",
+		Violations: []Violation{{
+			Field: "attendees",
+			Rule:  RuleCapacity,
+			Message: fmt.Sprintf("location %d seats %d people, session expects %d",
+				location.ID, location.Capacity, session.Attendees),
+		}},
+	}
+}
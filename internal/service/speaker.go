@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"event_service/internal/models"
+	"event_service/internal/repositories"
+	"event_service/internal/schemas"
+	"event_service/pkg/teams"
+	"event_service/pkg/tracing"
+	"github.com/go-pg/pg/v10"
+)
+
+type SpeakerService struct {
+	repo  *repositories.SpeakerRepository
+	audit *AuditService
+	teams teams.Client
+	db    *pg.DB
+}
+
+func NewSpeakerService(repo *repositories.SpeakerRepository, audit *AuditService, teams teams.Client, db *pg.DB) *SpeakerService {
+	return &SpeakerService{
+		repo:  repo,
+		audit: audit,
+		teams: teams,
+		db:    db,
+	}
+}
+
+func (s *SpeakerService) GetAllSpeakers(ctx context.Context) (_ []*models.Speaker, err error) {
+	ctx, span := tracing.Start(ctx, "SpeakerService.GetAllSpeakers")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	return s.repo.GetAllSpeakers(tx)
+}
+
+func (s *SpeakerService) GetSpeakerById(ctx context.Context, speakerId int) (_ *models.Speaker, err error) {
+	ctx, span := tracing.Start(ctx, "SpeakerService.GetSpeakerById")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	return s.repo.GetSpeakerByID(tx, speakerId)
+}
+
+func (s *SpeakerService) CreateSpeaker(ctx context.Context, speaker schemas.Speaker) (_ *models.Speaker, err error) {
+	ctx, span := tracing.Start(ctx, "SpeakerService.CreateSpeaker")
+	defer func() { tracing.End(span, err) }()
+
+	if speaker.UserID != 0 {
+		if err = checkUserExists(ctx, s.teams, speaker.UserID); err != nil {
+			return nil, err
+		}
+	}
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	model := &models.Speaker{
+		Name:    speaker.Name,
+		Bio:     speaker.Bio,
+		Company: speaker.Company,
+		UserID:  speaker.UserID,
+	}
+
+	created, err := s.repo.Create(tx, model)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.audit.Record(ctx, tx, AuditEntitySpeaker, AuditID(created.ID), AuditActionCreate, nil, created); err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (s *SpeakerService) UpdateSpeaker(ctx context.Context, speakerId int, newSpeaker schemas.SpeakerUpdate) (*models.Speaker, error) {
+	return s.PatchSpeaker(ctx, speakerId, newSpeaker.Patch())
+}
+
+func (s *SpeakerService) PatchSpeaker(ctx context.Context, speakerId int, patch schemas.SpeakerPatch) (_ *models.Speaker, err error) {
+	ctx, span := tracing.Start(ctx, "SpeakerService.PatchSpeaker")
+	defer func() { tracing.End(span, err) }()
+
+	if patch.UserID.Set && !patch.UserID.Null {
+		if err = checkUserExists(ctx, s.teams, patch.UserID.Value); err != nil {
+			return nil, err
+		}
+	}
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	speaker, err := s.repo.GetSpeakerByID(tx, speakerId)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *speaker
+
+	patch.Name.ApplyTo(&speaker.Name)
+	patch.Bio.ApplyTo(&speaker.Bio)
+	patch.Company.ApplyTo(&speaker.Company)
+	patch.UserID.ApplyTo(&speaker.UserID)
+
+	updated, err := s.repo.UpdateSpeaker(tx, speakerId, speaker)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.audit.Record(ctx, tx, AuditEntitySpeaker, AuditID(speakerId), AuditActionUpdate, &before, updated); err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// DeleteSpeaker fails while the speaker is still assigned to a session, the
+// assignments have to be removed from the agenda first.
+func (s *SpeakerService) DeleteSpeaker(ctx context.Context, speakerId int) (err error) {
+	ctx, span := tracing.Start(ctx, "SpeakerService.DeleteSpeaker")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	speaker, err := s.repo.GetSpeakerByID(tx, speakerId)
+	if err != nil {
+		return err
+	}
+
+	if err = s.repo.DeleteSpeaker(tx, speakerId); err != nil {
+		return err
+	}
+
+	return s.audit.Record(ctx, tx, AuditEntitySpeaker, AuditID(speakerId), AuditActionDelete, speaker, nil)
+}
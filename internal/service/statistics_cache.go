@@ -0,0 +1,62 @@
+package service
+
+import (
+	"event_service/internal/schemas"
+	"sync"
+	"time"
+)
+
+// StatisticsCache keeps computed track statistics in memory. Submissions
+// invalidate their track right away, the ttl bounds how long changes to
+// registrations, timelines or overrides take to show up.
+type StatisticsCache struct {
+	mu  sync.Mutex
+	ttl time.Duration
+
+	entries     map[int]*schemas.TrackStatistics
+	generations map[int]uint64
+}
+
+func NewStatisticsCache(ttl time.Duration) *StatisticsCache {
+	return &StatisticsCache{
+		ttl:         ttl,
+		entries:     make(map[int]*schemas.TrackStatistics),
+		generations: make(map[int]uint64),
+	}
+}
+
+// Get returns the cached statistics of a track, or nil together with the
+// generation to hand back to Put once they are computed.
+func (c *StatisticsCache) Get(trackId int) (*schemas.TrackStatistics, uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats, ok := c.entries[trackId]
+	if ok && time.Since(stats.ComputedAt) < c.ttl {
+		return stats, c.generations[trackId]
+	}
+
+	delete(c.entries, trackId)
+	return nil, c.generations[trackId]
+}
+
+// Put stores statistics unless the track was invalidated after the
+// computation started.
+func (c *StatisticsCache) Put(trackId int, generation uint64, stats *schemas.TrackStatistics) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.generations[trackId] != generation {
+		return
+	}
+
+	c.entries[trackId] = stats
+}
+
+func (c *StatisticsCache) Invalidate(trackId int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, trackId)
+	c.generations[trackId]++
+}
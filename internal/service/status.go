@@ -1,28 +1,40 @@
 package service
 
 import (
+	"context"
 	status_api "event_service/gen/status"
 	"event_service/internal/models"
 	"event_service/internal/repositories"
+	"event_service/internal/schemas"
+	"event_service/pkg/tracing"
 	"github.com/go-pg/pg/v10"
 )
 
 type StatusService struct {
-	repo *repositories.StatusRepository
-	db   *pg.DB
+	repo  *repositories.StatusRepository
+	audit *AuditService
+	db    *pg.DB
 }
 
-func NewStatusService(repo *repositories.StatusRepository, db *pg.DB) *StatusService {
+func NewStatusService(repo *repositories.StatusRepository, audit *AuditService, db *pg.DB) *StatusService {
 	return &StatusService{
-		repo: repo,
-		db:   db,
+		repo:  repo,
+		audit: audit,
+		db:    db,
 	}
 }
 
 func SingleStatusConvert(model *models.Status) *status_api.StatusResponse {
-	return &status_api.StatusResponse{
+	response := &status_api.StatusResponse{
+		Id:    &model.ID,
 		Title: model.Title,
 	}
+
+	if model.Description != "" {
+		response.Description = &model.Description
+	}
+
+	return response
 }
 
 func MultipleStatusConvert(models []*models.Status) []*status_api.StatusResponse {
@@ -35,8 +47,11 @@ func MultipleStatusConvert(models []*models.Status) []*status_api.StatusResponse
 	return responses
 }
 
-func (s *StatusService) GetAllStatuses() (_ []*status_api.StatusResponse, err error) {
-	tx, err := s.db.Begin()
+func (s *StatusService) GetAllStatuses(ctx context.Context) (_ []*status_api.StatusResponse, err error) {
+	ctx, span := tracing.Start(ctx, "StatusService.GetAllStatuses")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -58,8 +73,11 @@ func (s *StatusService) GetAllStatuses() (_ []*status_api.StatusResponse, err er
 	return MultipleStatusConvert(statusModels), nil
 }
 
-func (s *StatusService) GetStatusById(statusId int) (_ *status_api.StatusResponse, err error) {
-	tx, err := s.db.Begin()
+func (s *StatusService) GetStatusById(ctx context.Context, statusId int) (_ *status_api.StatusResponse, err error) {
+	ctx, span := tracing.Start(ctx, "StatusService.GetStatusById")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -81,8 +99,11 @@ func (s *StatusService) GetStatusById(statusId int) (_ *status_api.StatusRespons
 	return SingleStatusConvert(statusModel), nil
 }
 
-func (s *StatusService) CreateStatus(status status_api.Status) (_ *status_api.StatusResponse, err error) {
-	tx, err := s.db.Begin()
+func (s *StatusService) CreateStatus(ctx context.Context, status status_api.Status) (_ *status_api.StatusResponse, err error) {
+	ctx, span := tracing.Start(ctx, "StatusService.CreateStatus")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -100,16 +121,34 @@ func (s *StatusService) CreateStatus(status status_api.Status) (_ *status_api.St
 		Title: status.Title,
 	}
 
+	if status.Description != nil {
+		model.Description = *status.Description
+	}
+
 	statusModel, err := s.repo.Create(tx, model)
 	if err != nil {
 		return nil, err
 	}
 
+	if err = s.audit.Record(ctx, tx, AuditEntityStatus, AuditID(statusModel.ID), AuditActionCreate, nil, statusModel); err != nil {
+		return nil, err
+	}
+
 	return SingleStatusConvert(statusModel), nil
 }
 
-func (s *StatusService) UpdateStatus(eventId int, newStatus status_api.StatusUpdate) (_ *status_api.StatusResponse, err error) {
-	tx, err := s.db.Begin()
+func (s *StatusService) UpdateStatus(ctx context.Context, statusId int, newStatus status_api.StatusUpdate) (*status_api.StatusResponse, error) {
+	return s.PatchStatus(ctx, statusId, schemas.StatusPatch{
+		Title:       schemas.FromPointer(newStatus.Title),
+		Description: schemas.NullableFromPointer(newStatus.Description),
+	})
+}
+
+func (s *StatusService) PatchStatus(ctx context.Context, statusId int, patch schemas.StatusPatch) (_ *status_api.StatusResponse, err error) {
+	ctx, span := tracing.Start(ctx, "StatusService.PatchStatus")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -123,30 +162,32 @@ func (s *StatusService) UpdateStatus(eventId int, newStatus status_api.StatusUpd
 		err = tx.Commit()
 	}()
 
-	status, err := s.GetStatusById(eventId)
+	before, err := s.repo.GetStatusById(tx, statusId)
 	if err != nil {
-		return status, err
+		return nil, err
 	}
 
-	if newStatus.Title != nil {
-		status.Title = *newStatus.Title
-	}
+	model := *before
+	patch.Title.ApplyTo(&model.Title)
+	patch.Description.ApplyTo(&model.Description)
 
-	model := &models.Status{
-		ID:    eventId,
-		Title: status.Title,
+	statusModel, err := s.repo.UpdateStatus(tx, &model)
+	if err != nil {
+		return nil, err
 	}
 
-	statusModel, err := s.repo.UpdateStatus(tx, model)
-	if err != nil {
+	if err = s.audit.Record(ctx, tx, AuditEntityStatus, AuditID(statusId), AuditActionUpdate, before, statusModel); err != nil {
 		return nil, err
 	}
 
 	return SingleStatusConvert(statusModel), nil
 }
 
-func (s *StatusService) DeleteStatus(statusId int) (err error) {
-	tx, err := s.db.Begin()
+func (s *StatusService) DeleteStatus(ctx context.Context, statusId int) (err error) {
+	ctx, span := tracing.Start(ctx, "StatusService.DeleteStatus")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return err
 	}
@@ -160,5 +201,14 @@ func (s *StatusService) DeleteStatus(statusId int) (err error) {
 		err = tx.Commit()
 	}()
 
-	return s.repo.DeleteStatus(tx, statusId)
+	status, err := s.repo.GetStatusById(tx, statusId)
+	if err != nil {
+		return err
+	}
+
+	if err = s.repo.DeleteStatus(tx, statusId); err != nil {
+		return err
+	}
+
+	return s.audit.Record(ctx, tx, AuditEntityStatus, AuditID(statusId), AuditActionDelete, status, nil)
 }
@@ -0,0 +1,435 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"event_service/internal/models"
+	"event_service/internal/schemas"
+	"event_service/pkg/blob"
+	"event_service/pkg/tracing"
+	"event_service/pkg/utils"
+	"fmt"
+	"github.com/go-pg/pg/v10"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	RuleFileSize = "file_size"
+	RuleFileType = "file_type"
+
+	sniffLength     = 512
+	maxFileNameSize = 255
+)
+
+// SubmissionPolicy limits what teams may upload and how long download links
+// stay valid. DownloadURL is a format string taking the file id.
+type SubmissionPolicy struct {
+	MaxSize      int64
+	AllowedTypes []string
+	LinkTTL      time.Duration
+	DownloadURL  string
+}
+
+func (s *TeamActionStatusService) GetSubmissionFiles(ctx context.Context, timelineId int, teamId int) (_ []*models.SubmissionFile, err error) {
+	ctx, span := tracing.Start(ctx, "TeamActionStatusService.GetSubmissionFiles")
+	defer func() { tracing.End(span, err) }()
+
+	if err = s.authorizeFiles(ctx, teamId); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	if _, err = s.repo.GetTeamActionStatusByTeamIDAndTimelineID(tx, teamId, timelineId); err != nil {
+		return nil, err
+	}
+
+	return s.fileRepo.GetFiles(tx, teamId, timelineId)
+}
+
+// UploadSubmissionFile spools the upload to a temporary file first, which
+// gives its size and checksums before anything reaches the blob store.
+func (s *TeamActionStatusService) UploadSubmissionFile(ctx context.Context, timelineId int, teamId int, fileName string,
+	content io.Reader) (_ *models.SubmissionFile, err error) {
+	ctx, span := tracing.Start(ctx, "TeamActionStatusService.UploadSubmissionFile")
+	defer func() { tracing.End(span, err) }()
+
+	if err = s.authorizeFiles(ctx, teamId); err != nil {
+		return nil, err
+	}
+
+	spool, err := os.CreateTemp("", "submission-*")
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = spool.Close()
+		_ = os.Remove(spool.Name())
+	}()
+
+	reader := bufio.NewReaderSize(content, sniffLength)
+	head, err := reader.Peek(sniffLength)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, err
+	}
+
+	contentType, _, err := mime.ParseMediaType(http.DetectContentType(head))
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(s.policy.AllowedTypes, contentType) {
+		return nil, fileViolation(RuleFileType, fmt.Sprintf("files of type %s are not accepted", contentType))
+	}
+
+	sha256Hash, md5Hash := sha256.New(), md5.New()
+
+	size, err := io.Copy(io.MultiWriter(spool, sha256Hash, md5Hash), io.LimitReader(reader, s.policy.MaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if size > s.policy.MaxSize {
+		return nil, fileViolation(RuleFileSize, fmt.Sprintf("file is larger than %d bytes", s.policy.MaxSize))
+	}
+
+	if _, err = spool.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	model := &models.SubmissionFile{
+		TrackTeamID: teamId,
+		TimelineID:  timelineId,
+		StorageKey:  submissionKey(timelineId, teamId),
+		FileName:    cleanFileName(fileName),
+		ContentType: contentType,
+		Size:        size,
+		SHA256:      hex.EncodeToString(sha256Hash.Sum(nil)),
+		MD5:         hex.EncodeToString(md5Hash.Sum(nil)),
+	}
+
+	if actorId, ok := utils.ActorFromContext(ctx); ok {
+		model.UploadedBy = actorId
+	}
+
+	// The blob goes first so the transfer does not hold a transaction open.
+	// Registered before the commit below, the cleanup also covers a failed
+	// commit.
+	if err = s.blobs.Put(ctx, model.StorageKey, spool, size, contentType); err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			s.removeBlobs(model)
+		}
+	}()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	trackId, submitted := 0, true
+	defer s.afterCommit(&err, &trackId, &submitted)
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	teamActionStatus, err := s.repo.GetTeamActionStatusByTeamIDAndTimelineID(tx, teamId, timelineId)
+	if err != nil {
+		return nil, err
+	}
+
+	timeline, err := teamTimeline(tx, s.timelineRepo, s.overrideRepo, timelineId, teamId)
+	if err != nil {
+		return nil, err
+	}
+	trackId = timeline.TrackID
+
+	// A file is a submission of its own, so it is checked as one made now
+	// without touching the stored status.
+	upload := *teamActionStatus
+	upload.SubmittedAt = time.Now()
+
+	if err = applyLatePolicy(timeline, &upload, true); err != nil {
+		return nil, err
+	}
+
+	created, err := s.fileRepo.Create(tx, model)
+	if err != nil {
+		return nil, err
+	}
+
+	auditId := AuditID(timelineId, teamId, created.ID)
+	if err = s.audit.Record(ctx, tx, AuditEntitySubmissionFile, auditId, AuditActionCreate, nil, created); err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (s *TeamActionStatusService) DeleteSubmissionFile(ctx context.Context, timelineId int, teamId int, fileId int) (err error) {
+	ctx, span := tracing.Start(ctx, "TeamActionStatusService.DeleteSubmissionFile")
+	defer func() { tracing.End(span, err) }()
+
+	if err = s.authorizeFiles(ctx, teamId); err != nil {
+		return err
+	}
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	var file *models.SubmissionFile
+
+	defer func() {
+		if err == nil {
+			s.removeBlobs(file)
+		}
+	}()
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	if file, err = s.fileRepo.GetFile(tx, teamId, timelineId, fileId); err != nil {
+		return err
+	}
+
+	if err = s.fileRepo.DeleteFile(tx, fileId); err != nil {
+		return err
+	}
+
+	auditId := AuditID(timelineId, teamId, fileId)
+	return s.audit.Record(ctx, tx, AuditEntitySubmissionFile, auditId, AuditActionDelete, file, nil)
+}
+
+// GetSubmissionFileLink issues a download link to team members and track
+// roles that may view results. The link itself carries the authorization,
+// so it can be handed to a browser.
+func (s *TeamActionStatusService) GetSubmissionFileLink(ctx context.Context, timelineId int, teamId int, fileId int) (_ *schemas.SubmissionLink, err error) {
+	ctx, span := tracing.Start(ctx, "TeamActionStatusService.GetSubmissionFileLink")
+	defer func() { tracing.End(span, err) }()
+
+	if err = s.authorizeFiles(ctx, teamId); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	file, err := s.fileRepo.GetFile(tx, teamId, timelineId, fileId)
+	if err != nil {
+		return nil, err
+	}
+
+	expires := time.Now().Add(s.policy.LinkTTL).Truncate(time.Second)
+
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires.Unix(), 10))
+	query.Set("signature", s.links.Sign(file.StorageKey, expires))
+
+	return &schemas.SubmissionLink{
+		URL:       fmt.Sprintf(s.policy.DownloadURL, file.ID) + "?" + query.Encode(),
+		ExpiresAt: expires.UTC(),
+	}, nil
+}
+
+// OpenSubmissionFile checks a download link and opens the file it points to.
+// The caller closes the returned reader.
+func (s *TeamActionStatusService) OpenSubmissionFile(ctx context.Context, fileId int, expires time.Time, signature string) (_ *models.SubmissionFile, _ io.ReadCloser, err error) {
+	ctx, span := tracing.Start(ctx, "TeamActionStatusService.OpenSubmissionFile")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	file, err := s.fileRepo.GetFileByID(tx, fileId)
+	if errors.Is(err, pg.ErrNoRows) {
+		return nil, nil, Forbidden(blob.ErrInvalidLink.Error())
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = s.links.Verify(file.StorageKey, expires, signature, time.Now()); err != nil {
+		return nil, nil, Forbidden(blob.ErrInvalidLink.Error())
+	}
+
+	content, err := s.blobs.Get(ctx, file.StorageKey)
+	if errors.Is(err, blob.ErrNotFound) {
+		return nil, nil, NotFound("content of file %d is missing", fileId)
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return file, content, nil
+}
+
+// authorizeFiles lets members of the team and track roles that may view
+// results at the files of a registration. Membership is asked from the user
+// and teams service once the role lookup is over.
+func (s *TeamActionStatusService) authorizeFiles(ctx context.Context, teamId int) error {
+	actorId, ok := utils.ActorFromContext(ctx)
+	if !ok {
+		return Forbidden("submission files are open to authenticated users only")
+	}
+
+	trackTeam, role, err := s.actorTeamRole(ctx, teamId, actorId)
+	if err != nil {
+		return err
+	}
+
+	if role != nil && role.CanViewResults {
+		return nil
+	}
+
+	member, err := isTeamMember(ctx, s.teams, trackTeam.TeamID, actorId)
+	if err != nil {
+		return err
+	}
+
+	if !member {
+		return Forbidden("submission files of team %d are open to its members and track organizers only", teamId)
+	}
+
+	return nil
+}
+
+// actorTeamRole loads a registration with the actor's role in its track, the
+// role is nil when the actor has none.
+func (s *TeamActionStatusService) actorTeamRole(ctx context.Context, teamId int, actorId int) (_ *models.TrackTeam, _ *models.TrackRole, err error) {
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	trackTeam, err := s.trackTeamRepo.GetTrackTeamByID(tx, teamId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	role, err := s.trackRoleRepo.GetRole(tx, trackTeam.TrackID, actorId)
+	if errors.Is(err, pg.ErrNoRows) {
+		return trackTeam, nil, nil
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return trackTeam, role, nil
+}
+
+// removeBlobs runs after the rows are gone. A blob that fails to go is only
+// wasted space, so errors are ignored rather than failing the request.
+func (s *TeamActionStatusService) removeBlobs(files ...*models.SubmissionFile) {
+	for _, file := range files {
+		if file != nil {
+			_ = s.blobs.Delete(context.Background(), file.StorageKey)
+		}
+	}
+}
+
+func submissionKey(timelineId int, teamId int) string {
+	suffix := make([]byte, 16)
+	_, _ = rand.Read(suffix)
+
+	return fmt.Sprintf("submissions/%d/%d/%s", timelineId, teamId, hex.EncodeToString(suffix))
+}
+
+func cleanFileName(name string) string {
+	name = filepath.Base(filepath.Clean("/" + name))
+	if name == "/" || name == "." {
+		return "file"
+	}
+
+	for len(name) > maxFileNameSize {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+
+	return name
+}
+
+func fileViolation(rule string, message string) error {
+	return &Error{
+		Kind:       ErrValidation,
+		Detail:     "file cannot be accepted",
+		Violations: []Violation{{Field: "file", Rule: rule, Message: message}},
+	}
+}
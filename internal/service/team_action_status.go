@@ -1,27 +1,64 @@
 package service
 
 import (
+	"context"
 	"event_service/internal/models"
 	"event_service/internal/repositories"
 	"event_service/internal/schemas"
+	"event_service/pkg/blob"
+	"event_service/pkg/teams"
+	"event_service/pkg/tracing"
+	"event_service/pkg/utils"
 	"github.com/go-pg/pg/v10"
-	"strconv"
+	"time"
 )
 
 type TeamActionStatusService struct {
-	db   *pg.DB
-	repo *repositories.TeamActionStatusRepository
+	db            *pg.DB
+	repo          *repositories.TeamActionStatusRepository
+	revisionRepo  *repositories.TeamActionStatusRevisionRepository
+	timelineRepo  *repositories.TimelineRepository
+	overrideRepo  *repositories.TimelineOverrideRepository
+	fileRepo      *repositories.SubmissionFileRepository
+	trackTeamRepo *repositories.TrackTeamRepository
+	trackRoleRepo *repositories.TrackRoleRepository
+	audit         *AuditService
+	teams         teams.Client
+
+	blobs  blob.Store
+	links  *blob.Signer
+	policy SubmissionPolicy
+	stats  *StatisticsCache
 }
 
-func NewTeamActionStatusService(repo *repositories.TeamActionStatusRepository, db *pg.DB) *TeamActionStatusService {
+func NewTeamActionStatusService(repo *repositories.TeamActionStatusRepository, revisionRepo *repositories.TeamActionStatusRevisionRepository,
+	timelineRepo *repositories.TimelineRepository, overrideRepo *repositories.TimelineOverrideRepository,
+	fileRepo *repositories.SubmissionFileRepository, trackTeamRepo *repositories.TrackTeamRepository,
+	trackRoleRepo *repositories.TrackRoleRepository, audit *AuditService, teams teams.Client, blobs blob.Store, links *blob.Signer,
+	policy SubmissionPolicy, stats *StatisticsCache, db *pg.DB) *TeamActionStatusService {
 	return &TeamActionStatusService{
-		repo: repo,
-		db:   db,
+		repo:          repo,
+		revisionRepo:  revisionRepo,
+		timelineRepo:  timelineRepo,
+		overrideRepo:  overrideRepo,
+		fileRepo:      fileRepo,
+		trackTeamRepo: trackTeamRepo,
+		trackRoleRepo: trackRoleRepo,
+		audit:         audit,
+		teams:         teams,
+		blobs:         blobs,
+		links:         links,
+		policy:        policy,
+		stats:         stats,
+		db:            db,
 	}
 }
 
-func (s *TeamActionStatusService) GetTeamActionStatusByTeamId(teamId int) (_ []*models.TeamActionStatus, err error) {
-	tx, err := s.db.Begin()
+func (s *TeamActionStatusService) GetTeamActionStatusByTeamId(ctx context.Context, teamId int) (_ []*models.TeamActionStatus, err error) {
+	ctx, span := tracing.Start(ctx, "TeamActionStatusService.GetTeamActionStatusByTeamId")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -38,8 +75,11 @@ func (s *TeamActionStatusService) GetTeamActionStatusByTeamId(teamId int) (_ []*
 	return s.repo.GetTeamActionStatusByTeamID(tx, teamId)
 }
 
-func (s *TeamActionStatusService) GetTeamActionStatusByTimelineId(timelineId int) (_ []*models.TeamActionStatus, err error) {
-	tx, err := s.db.Begin()
+func (s *TeamActionStatusService) GetTeamActionStatusByTimelineId(ctx context.Context, timelineId int) (_ []*models.TeamActionStatus, err error) {
+	ctx, span := tracing.Start(ctx, "TeamActionStatusService.GetTeamActionStatusByTimelineId")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -56,8 +96,11 @@ func (s *TeamActionStatusService) GetTeamActionStatusByTimelineId(timelineId int
 	return s.repo.GetTeamActionStatusByTeamID(tx, timelineId)
 }
 
-func (s *TeamActionStatusService) GetTeamActionStatus(timelineId int, teamId int) (_ *models.TeamActionStatus, err error) {
-	tx, err := s.db.Begin()
+func (s *TeamActionStatusService) GetTeamActionStatus(ctx context.Context, timelineId int, teamId int) (_ *models.TeamActionStatus, err error) {
+	ctx, span := tracing.Start(ctx, "TeamActionStatusService.GetTeamActionStatus")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -74,12 +117,18 @@ func (s *TeamActionStatusService) GetTeamActionStatus(timelineId int, teamId int
 	return s.repo.GetTeamActionStatusByTeamIDAndTimelineID(tx, teamId, timelineId)
 }
 
-func (s *TeamActionStatusService) CreateTeamActionStatus(teamActionStatus *schemas.TeamActionStatus) (_ *models.TeamActionStatus, err error) {
-	tx, err := s.db.Begin()
+func (s *TeamActionStatusService) CreateTeamActionStatus(ctx context.Context, teamActionStatus *schemas.TeamActionStatus) (_ *models.TeamActionStatus, err error) {
+	ctx, span := tracing.Start(ctx, "TeamActionStatusService.CreateTeamActionStatus")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	trackId, submitted := 0, true
+	defer s.afterCommit(&err, &trackId, &submitted)
+
 	defer func() {
 		if err != nil {
 			_ = tx.Rollback()
@@ -96,17 +145,52 @@ func (s *TeamActionStatusService) CreateTeamActionStatus(teamActionStatus *schem
 		ResultValue:    teamActionStatus.ResultValue,
 		CompletedAt:    teamActionStatus.CompletedAt,
 		Notes:          teamActionStatus.Notes,
+		SubmittedAt:    time.Now(),
+	}
+
+	timeline, err := teamTimeline(tx, s.timelineRepo, s.overrideRepo, model.TimelineID, model.TrackTeamID)
+	if err != nil {
+		return nil, err
+	}
+	trackId = timeline.TrackID
+
+	if err = applyLatePolicy(timeline, model, true); err != nil {
+		return nil, err
+	}
+
+	created, err := s.repo.Create(tx, model)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.recordRevision(ctx, tx, nil, created); err != nil {
+		return nil, err
 	}
 
-	return s.repo.Create(tx, model)
+	auditId := AuditID(created.TimelineID, created.TrackTeamID)
+	if err = s.audit.Record(ctx, tx, AuditEntityTeamActionStatus, auditId, AuditActionCreate, nil, created); err != nil {
+		return nil, err
+	}
+
+	return created, nil
 }
 
-func (s *TeamActionStatusService) UpdateTeamActionStatus(timelineId int, teamId int, newTeamActionStatus *schemas.TeamActionStatusUpdate) (_ *models.TeamActionStatus, err error) {
-	tx, err := s.db.Begin()
+func (s *TeamActionStatusService) UpdateTeamActionStatus(ctx context.Context, timelineId int, teamId int, newTeamActionStatus *schemas.TeamActionStatusUpdate) (*models.TeamActionStatus, error) {
+	return s.PatchTeamActionStatus(ctx, timelineId, teamId, newTeamActionStatus.Patch())
+}
+
+func (s *TeamActionStatusService) PatchTeamActionStatus(ctx context.Context, timelineId int, teamId int, patch schemas.TeamActionStatusPatch) (_ *models.TeamActionStatus, err error) {
+	ctx, span := tracing.Start(ctx, "TeamActionStatusService.PatchTeamActionStatus")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	trackId, submitted := 0, false
+	defer s.afterCommit(&err, &trackId, &submitted)
+
 	defer func() {
 		if err != nil {
 			_ = tx.Rollback()
@@ -116,41 +200,73 @@ func (s *TeamActionStatusService) UpdateTeamActionStatus(timelineId int, teamId
 		err = tx.Commit()
 	}()
 
-	teamActionStatus, err := s.GetTeamActionStatus(timelineId, teamId)
+	teamActionStatus, err := s.repo.GetTeamActionStatusByTeamIDAndTimelineID(tx, teamId, timelineId)
 	if err != nil {
 		return nil, err
 	}
 
-	if newTeamActionStatus.ResolutionLink != "" {
-		teamActionStatus.ResolutionLink = newTeamActionStatus.ResolutionLink
+	before := *teamActionStatus
+
+	patch.ResultValue.ApplyTo(&teamActionStatus.ResultValue)
+	patch.ResolutionLink.ApplyTo(&teamActionStatus.ResolutionLink)
+	patch.CompletedAt.ApplyTo(&teamActionStatus.CompletedAt)
+	patch.Notes.ApplyTo(&teamActionStatus.Notes)
+
+	// A new link or completion time is a resubmission, a score or notes
+	// change is not.
+	submitted = teamActionStatus.ResolutionLink != before.ResolutionLink || !teamActionStatus.CompletedAt.Equal(before.CompletedAt)
+	if submitted {
+		teamActionStatus.SubmittedAt = time.Now()
 	}
 
-	if newTeamActionStatus.Notes != "" {
-		teamActionStatus.Notes = newTeamActionStatus.Notes
+	timeline, err := teamTimeline(tx, s.timelineRepo, s.overrideRepo, timelineId, teamId)
+	if err != nil {
+		return nil, err
 	}
+	trackId = timeline.TrackID
 
-	if !newTeamActionStatus.CompletedAt.IsZero() {
-		teamActionStatus.CompletedAt = newTeamActionStatus.CompletedAt
+	if err = applyLatePolicy(timeline, teamActionStatus, submitted); err != nil {
+		return nil, err
 	}
 
-	if newTeamActionStatus.ResultValue != "" {
-		converted, err := strconv.Atoi(newTeamActionStatus.ResultValue)
-		if err != nil {
-			return nil, err
-		}
+	updated, err := s.repo.UpdateTeamActionStatus(tx, teamId, timelineId, teamActionStatus)
+	if err != nil {
+		return nil, err
+	}
 
-		teamActionStatus.ResultValue = converted
+	if err = s.recordRevision(ctx, tx, &before, updated); err != nil {
+		return nil, err
 	}
 
-	return s.repo.UpdateTeamActionStatus(tx, teamId, timelineId, teamActionStatus)
+	auditId := AuditID(timelineId, teamId)
+	if err = s.audit.Record(ctx, tx, AuditEntityTeamActionStatus, auditId, AuditActionUpdate, &before, updated); err != nil {
+		return nil, err
+	}
+
+	return updated, nil
 }
 
-func (s *TeamActionStatusService) DeleteTeamActionStatus(timelineId int, teamId int) (err error) {
-	tx, err := s.db.Begin()
+func (s *TeamActionStatusService) DeleteTeamActionStatus(ctx context.Context, timelineId int, teamId int) (err error) {
+	ctx, span := tracing.Start(ctx, "TeamActionStatusService.DeleteTeamActionStatus")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return err
 	}
 
+	files := make([]*models.SubmissionFile, 0)
+
+	// Registered before the commit below, so it runs once the rows are gone.
+	defer func() {
+		if err == nil {
+			s.removeBlobs(files...)
+		}
+	}()
+
+	trackId, submitted := 0, false
+	defer s.afterCommit(&err, &trackId, &submitted)
+
 	defer func() {
 		if err != nil {
 			_ = tx.Rollback()
@@ -160,5 +276,46 @@ func (s *TeamActionStatusService) DeleteTeamActionStatus(timelineId int, teamId
 		err = tx.Commit()
 	}()
 
-	return s.repo.DeleteTeamActionStatus(tx, teamId, timelineId)
+	teamActionStatus, err := s.repo.GetTeamActionStatusByTeamIDAndTimelineID(tx, teamId, timelineId)
+	if err != nil {
+		return err
+	}
+
+	if files, err = s.fileRepo.GetFiles(tx, teamId, timelineId); err != nil {
+		return err
+	}
+
+	timeline, err := s.timelineRepo.GetTimelineByID(tx, timelineId)
+	if err != nil {
+		return err
+	}
+	trackId = timeline.TrackID
+
+	if err = s.recordDeletion(ctx, tx, teamActionStatus); err != nil {
+		return err
+	}
+
+	if err = s.repo.DeleteTeamActionStatus(tx, teamId, timelineId); err != nil {
+		return err
+	}
+
+	auditId := AuditID(timelineId, teamId)
+	return s.audit.Record(ctx, tx, AuditEntityTeamActionStatus, auditId, AuditActionDelete, teamActionStatus, nil)
+}
+
+// afterCommit drops the cached statistics of the track and counts the
+// submission once the surrounding transaction has committed. Defer it before
+// the commit.
+func (s *TeamActionStatusService) afterCommit(err *error, trackId *int, submitted *bool) {
+	if *err != nil {
+		return
+	}
+
+	if *trackId != 0 {
+		s.stats.Invalidate(*trackId)
+	}
+
+	if *submitted {
+		utils.Submissions.Inc()
+	}
 }
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"event_service/internal/models"
+	"event_service/internal/schemas"
+	"event_service/pkg/tracing"
+	"event_service/pkg/utils"
+	"github.com/go-pg/pg/v10"
+)
+
+func (s *TeamActionStatusService) GetTeamActionStatusRevisions(ctx context.Context, timelineId int, teamId int) (_ []*models.TeamActionStatusRevision, err error) {
+	ctx, span := tracing.Start(ctx, "TeamActionStatusService.GetTeamActionStatusRevisions")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	// Revisions outlive a deleted action, so only an action without any
+	// history is reported missing.
+	revisions, err := s.revisionRepo.GetRevisions(tx, teamId, timelineId)
+	if err != nil || len(revisions) > 0 {
+		return revisions, err
+	}
+
+	if _, err = s.repo.GetTeamActionStatusByTeamIDAndTimelineID(tx, teamId, timelineId); err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+func (s *TeamActionStatusService) DiffTeamActionStatusRevisions(ctx context.Context, timelineId int, teamId int, from int, to int) (_ *schemas.TeamActionStatusRevisionDiff, err error) {
+	ctx, span := tracing.Start(ctx, "TeamActionStatusService.DiffTeamActionStatusRevisions")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	fromRevision, err := s.revisionRepo.GetRevision(tx, teamId, timelineId, from)
+	if err != nil {
+		return nil, err
+	}
+
+	toRevision, err := s.revisionRepo.GetRevision(tx, teamId, timelineId, to)
+	if err != nil {
+		return nil, err
+	}
+
+	before, err := auditFields(revisionContent(fromRevision))
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := auditFields(revisionContent(toRevision))
+	if err != nil {
+		return nil, err
+	}
+
+	return &schemas.TeamActionStatusRevisionDiff{
+		From:    from,
+		To:      to,
+		Changes: auditDiff(before, after),
+	}, nil
+}
+
+// recordRevision stores the current state of an action as a new revision
+// unless it matches the latest one, so repeated saves do not pile up.
+func (s *TeamActionStatusService) recordRevision(ctx context.Context, tx *pg.Tx, before *models.TeamActionStatus,
+	after *models.TeamActionStatus) error {
+	if before != nil && sameRevisionContent(before, after) {
+		return nil
+	}
+
+	_, err := s.revisionRepo.Create(tx, newRevision(ctx, after))
+	return err
+}
+
+// recordDeletion closes the history of a deleted action with a revision
+// holding its last content, so who deleted it and what it was stays known.
+func (s *TeamActionStatusService) recordDeletion(ctx context.Context, tx *pg.Tx, deleted *models.TeamActionStatus) error {
+	revision := newRevision(ctx, deleted)
+	revision.Deleted = true
+
+	_, err := s.revisionRepo.Create(tx, revision)
+	return err
+}
+
+func newRevision(ctx context.Context, status *models.TeamActionStatus) *models.TeamActionStatusRevision {
+	revision := &models.TeamActionStatusRevision{
+		TrackTeamID:    status.TrackTeamID,
+		TimelineID:     status.TimelineID,
+		ResultValue:    status.ResultValue,
+		ResolutionLink: status.ResolutionLink,
+		CompletedAt:    status.CompletedAt,
+		Notes:          status.Notes,
+	}
+
+	if actorId, ok := utils.ActorFromContext(ctx); ok {
+		revision.ActorID = actorId
+	}
+
+	return revision
+}
+
+func sameRevisionContent(a *models.TeamActionStatus, b *models.TeamActionStatus) bool {
+	return a.ResultValue == b.ResultValue && a.ResolutionLink == b.ResolutionLink &&
+		a.CompletedAt.Equal(b.CompletedAt) && a.Notes == b.Notes
+}
+
+// revisionContent keeps only the submitted and scored fields, so a diff does
+// not report revision numbers or timestamps.
+func revisionContent(revision *models.TeamActionStatusRevision) *models.TeamActionStatus {
+	return &models.TeamActionStatus{
+		TrackTeamID:    revision.TrackTeamID,
+		TimelineID:     revision.TimelineID,
+		ResultValue:    revision.ResultValue,
+		ResolutionLink: revision.ResolutionLink,
+		CompletedAt:    revision.CompletedAt,
+		Notes:          revision.Notes,
+	}
+}
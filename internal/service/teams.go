@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"event_service/pkg/teams"
+	"fmt"
+	"slices"
+)
+
+const (
+	RuleTeamExists = "team_exists"
+	RuleUserExists = "user_exists"
+)
+
+func getTeam(ctx context.Context, client teams.Client, teamId int) (*teams.Team, error) {
+	team, err := client.GetTeam(ctx, teamId)
+	if err != nil {
+		return nil, referenceError(err, "team_id", RuleTeamExists, fmt.Sprintf("team %d does not exist", teamId))
+	}
+
+	return team, nil
+}
+
+// isTeamMember asks the user and teams service whether the user belongs to
+// the team. It goes over the network, so call it outside transactions.
+func isTeamMember(ctx context.Context, client teams.Client, teamId int, userId int) (bool, error) {
+	team, err := client.GetTeam(ctx, teamId)
+	if errors.Is(err, teams.ErrNotFound) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, &Error{Kind: ErrUnavailable, Detail: "user and teams service is unavailable", Err: err}
+	}
+
+	return slices.ContainsFunc(team.Members, func(member teams.Member) bool {
+		return member.ID == userId
+	}), nil
+}
+
+func checkUserExists(ctx context.Context, client teams.Client, userId int) error {
+	_, err := client.GetUser(ctx, userId)
+	return referenceError(err, "user_id", RuleUserExists, fmt.Sprintf("user %d does not exist", userId))
+}
+
+// referenceError fails closed: a reference that cannot be checked because
+// the service is down is not accepted either.
+func referenceError(err error, field string, rule string, message string) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, teams.ErrNotFound):
+		return &Error{
+			Kind:       ErrValidation,
+			Detail:     "referenced entity does not exist",
+			Violations: []Violation{{Field: field, Rule: rule, Message: message}},
+		}
+	default:
+		return &Error{Kind: ErrUnavailable, Detail: "user and teams service is unavailable", Err: err}
+	}
+}
@@ -1,30 +1,48 @@
 package service
 
 import (
+	"context"
+	"errors"
 	timeline_api "event_service/gen/timeline"
 	"event_service/internal/models"
 	"event_service/internal/repositories"
+	"event_service/internal/schemas"
+	"event_service/pkg/tracing"
 	"github.com/go-pg/pg/v10"
 	"strconv"
+	"time"
 )
 
 type TimelineService struct {
 	repo               *repositories.TimelineRepository
 	timelineStatusRepo *repositories.TimelineStatusRepository
+	trackRepo          *repositories.TrackRepository
+	trackTeamRepo      *repositories.TrackTeamRepository
+	overrideRepo       *repositories.TimelineOverrideRepository
+	audit              *AuditService
+	schedule           *ScheduleValidator
 	db                 *pg.DB
 }
 
 func NewTimelineService(repo *repositories.TimelineRepository, timelineStatusRepo *repositories.TimelineStatusRepository,
+	trackRepo *repositories.TrackRepository, trackTeamRepo *repositories.TrackTeamRepository,
+	overrideRepo *repositories.TimelineOverrideRepository, audit *AuditService, schedule *ScheduleValidator,
 	db *pg.DB) *TimelineService {
 	return &TimelineService{
 		repo:               repo,
 		timelineStatusRepo: timelineStatusRepo,
+		trackRepo:          trackRepo,
+		trackTeamRepo:      trackTeamRepo,
+		overrideRepo:       overrideRepo,
+		audit:              audit,
+		schedule:           schedule,
 		db:                 db,
 	}
 }
 
-func SingleTimelineConvert(model *models.Timeline) *timeline_api.TimelineResponse {
-	return &timeline_api.TimelineResponse{
+func SingleTimelineConvert(model *models.Timeline, zone string) *timeline_api.TimelineResponse {
+	response := &timeline_api.TimelineResponse{
+		Id:               &model.ID,
 		Deadline:         model.Deadline,
 		Description:      model.Description,
 		IsBlocking:       model.IsBlocking,
@@ -32,14 +50,42 @@ func SingleTimelineConvert(model *models.Timeline) *timeline_api.TimelineRespons
 		Title:            model.Title,
 		TrackId:          model.TrackID,
 		TimelineStatusId: model.TimelineStatusID,
+		Version:          &model.Version,
 	}
+
+	if model.CountedRevision != "" {
+		countedRevision := timeline_api.TimelineResponseCountedRevision(model.CountedRevision)
+		response.CountedRevision = &countedRevision
+	}
+
+	if model.LatePolicy != "" {
+		latePolicy := timeline_api.TimelineResponseLatePolicy(model.LatePolicy)
+		response.LatePolicy = &latePolicy
+		response.LateGraceMinutes = &model.LateGraceMinutes
+		response.LatePenalty = &model.LatePenalty
+	}
+
+	if model.LateStepMinutes != 0 {
+		response.LateStepMinutes = &model.LateStepMinutes
+	}
+
+	if zone != "" {
+		response.TimeZone = &zone
+	}
+
+	if deadline, ok := localTimes(zone, map[string]time.Time{"Deadline": model.Deadline})["Deadline"]; ok {
+		response.DeadlineLocal = &deadline
+	}
+
+	return response
 }
 
-func MultipleTimelineConvert(models []*models.Timeline) []*timeline_api.TimelineResponse {
+// MultipleTimelineConvert takes the event time zones keyed by track id.
+func MultipleTimelineConvert(models []*models.Timeline, zones map[int]string) []*timeline_api.TimelineResponse {
 	responses := make([]*timeline_api.TimelineResponse, 0)
 
 	for _, model := range models {
-		responses = append(responses, SingleTimelineConvert(model))
+		responses = append(responses, SingleTimelineConvert(model, zones[model.TrackID]))
 	}
 
 	return responses
@@ -57,8 +103,11 @@ func MultipleTimelineStatusConvert(statusesModels []*models.TimelineStatus) []*t
 	return responses
 }
 
-func (s *TimelineService) GetAllTimelines(trackId int) (_ []*timeline_api.TimelineResponse, err error) {
-	tx, err := s.db.Begin()
+func (s *TimelineService) GetAllTimelines(ctx context.Context, trackId int) (_ []*timeline_api.TimelineResponse, err error) {
+	ctx, span := tracing.Start(ctx, "TimelineService.GetAllTimelines")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -76,11 +125,19 @@ func (s *TimelineService) GetAllTimelines(trackId int) (_ []*timeline_api.Timeli
 		return nil, err
 	}
 
-	return MultipleTimelineConvert(timelineModels), nil
+	zones, err := s.timeZones(tx, timelineModels...)
+	if err != nil {
+		return nil, err
+	}
+
+	return MultipleTimelineConvert(timelineModels, zones), nil
 }
 
-func (s *TimelineService) GetAllTimelinesWithStatus(trackId int, Status string) (_ []*timeline_api.TimelineResponse, err error) {
-	tx, err := s.db.Begin()
+func (s *TimelineService) GetAllTimelinesWithStatus(ctx context.Context, trackId int, Status string) (_ []*timeline_api.TimelineResponse, err error) {
+	ctx, span := tracing.Start(ctx, "TimelineService.GetAllTimelinesWithStatus")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -98,11 +155,19 @@ func (s *TimelineService) GetAllTimelinesWithStatus(trackId int, Status string)
 		return nil, err
 	}
 
-	return MultipleTimelineConvert(timelineModels), err
+	zones, err := s.timeZones(tx, timelineModels...)
+	if err != nil {
+		return nil, err
+	}
+
+	return MultipleTimelineConvert(timelineModels, zones), nil
 }
 
-func (s *TimelineService) GetTimelineById(timelineId int) (_ *timeline_api.TimelineResponse, err error) {
-	tx, err := s.db.Begin()
+func (s *TimelineService) GetTimelineById(ctx context.Context, timelineId int) (_ *timeline_api.TimelineResponse, err error) {
+	ctx, span := tracing.Start(ctx, "TimelineService.GetTimelineById")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -120,11 +185,19 @@ func (s *TimelineService) GetTimelineById(timelineId int) (_ *timeline_api.Timel
 		return nil, err
 	}
 
-	return SingleTimelineConvert(timelineModel), nil
+	zones, err := s.timeZones(tx, timelineModel)
+	if err != nil {
+		return nil, err
+	}
+
+	return SingleTimelineConvert(timelineModel, zones[timelineModel.TrackID]), nil
 }
 
-func (s *TimelineService) CreateTimeline(timeline *timeline_api.Timeline) (_ *timeline_api.TimelineResponse, err error) {
-	tx, err := s.db.Begin()
+func (s *TimelineService) CreateTimeline(ctx context.Context, timeline *timeline_api.Timeline) (_ *timeline_api.TimelineResponse, err error) {
+	ctx, span := tracing.Start(ctx, "TimelineService.CreateTimeline")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -150,16 +223,82 @@ func (s *TimelineService) CreateTimeline(timeline *timeline_api.Timeline) (_ *ti
 		TimelineStatusID: nextIndex,
 	}
 
+	if timeline.CountedRevision != nil {
+		model.CountedRevision = string(*timeline.CountedRevision)
+	}
+
+	if timeline.LatePolicy != nil {
+		model.LatePolicy = string(*timeline.LatePolicy)
+	}
+
+	schemas.FromPointer(timeline.LateGraceMinutes).ApplyTo(&model.LateGraceMinutes)
+	schemas.FromPointer(timeline.LatePenalty).ApplyTo(&model.LatePenalty)
+	schemas.FromPointer(timeline.LateStepMinutes).ApplyTo(&model.LateStepMinutes)
+
+	if err = validateTimelinePolicies(model); err != nil {
+		return nil, err
+	}
+
 	timelineModel, err := s.repo.Create(tx, model)
 	if err != nil {
 		return nil, err
 	}
 
-	return SingleTimelineConvert(timelineModel), nil
+	if err = s.schedule.ValidateTracks(tx, timelineModel.TrackID); err != nil {
+		return nil, err
+	}
+
+	if err = s.audit.Record(ctx, tx, AuditEntityTimeline, AuditID(timelineModel.ID), AuditActionCreate, nil, timelineModel); err != nil {
+		return nil, err
+	}
+
+	zones, err := s.timeZones(tx, timelineModel)
+	if err != nil {
+		return nil, err
+	}
+
+	return SingleTimelineConvert(timelineModel, zones[timelineModel.TrackID]), nil
+}
+
+func (s *TimelineService) UpdateTimeline(ctx context.Context, timelineId int, newTimeline *timeline_api.TimelineUpdate, version int) (*timeline_api.TimelineResponse, error) {
+	patch := schemas.TimelinePatch{
+		Title:            schemas.FromPointer(newTimeline.Title),
+		Description:      schemas.NullableFromPointer(newTimeline.Description),
+		Deadline:         schemas.NullableFromPointer(newTimeline.Deadline),
+		Status:           schemas.FromPointer(newTimeline.Status),
+		TrackID:          schemas.FromPointer(newTimeline.TrackId),
+		TimelineStatusID: schemas.FromPointer(newTimeline.TimelineStatusId),
+	}
+
+	if newTimeline.CountedRevision != nil {
+		patch.CountedRevision = schemas.Optional[string]{Set: true, Value: string(*newTimeline.CountedRevision)}
+	}
+
+	if newTimeline.LatePolicy != nil {
+		patch.LatePolicy = schemas.Optional[string]{Set: true, Value: string(*newTimeline.LatePolicy)}
+	}
+
+	patch.LateGraceMinutes = schemas.FromPointer(newTimeline.LateGraceMinutes)
+	patch.LatePenalty = schemas.FromPointer(newTimeline.LatePenalty)
+	patch.LateStepMinutes = schemas.NullableFromPointer(newTimeline.LateStepMinutes)
+
+	if newTimeline.IsBlocking != nil {
+		isBlocking, err := strconv.ParseBool(*newTimeline.IsBlocking)
+		if err != nil {
+			return nil, Validation("invalid value for is_blocking: %s", *newTimeline.IsBlocking)
+		}
+
+		patch.IsBlocking = schemas.Optional[bool]{Set: true, Value: isBlocking}
+	}
+
+	return s.PatchTimeline(ctx, timelineId, patch, version)
 }
 
-func (s *TimelineService) UpdateTimeline(timelineId int, newTimeline *timeline_api.TimelineUpdate) (_ *timeline_api.TimelineResponse, err error) {
-	tx, err := s.db.Begin()
+func (s *TimelineService) PatchTimeline(ctx context.Context, timelineId int, patch schemas.TimelinePatch, version int) (_ *timeline_api.TimelineResponse, err error) {
+	ctx, span := tracing.Start(ctx, "TimelineService.PatchTimeline")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -172,64 +311,125 @@ func (s *TimelineService) UpdateTimeline(timelineId int, newTimeline *timeline_a
 		err = tx.Commit()
 	}()
 
-	timeline, err := s.GetTimelineById(timelineId)
+	before, err := s.repo.GetTimelineByID(tx, timelineId)
 	if err != nil {
 		return nil, err
 	}
 
-	if newTimeline.Title != nil {
-		timeline.Title = *newTimeline.Title
+	if version > 0 && before.Version != version {
+		return nil, ErrStaleVersion
 	}
 
-	if newTimeline.Description != nil {
-		timeline.Description = *newTimeline.Description
+	model := *before
+
+	patch.Title.ApplyTo(&model.Title)
+	patch.Description.ApplyTo(&model.Description)
+	patch.Deadline.ApplyTo(&model.Deadline)
+	patch.IsBlocking.ApplyTo(&model.IsBlocking)
+	patch.Status.ApplyTo(&model.Status)
+	patch.TrackID.ApplyTo(&model.TrackID)
+	patch.TimelineStatusID.ApplyTo(&model.TimelineStatusID)
+	patch.CountedRevision.ApplyTo(&model.CountedRevision)
+	patch.LatePolicy.ApplyTo(&model.LatePolicy)
+	patch.LateGraceMinutes.ApplyTo(&model.LateGraceMinutes)
+	patch.LatePenalty.ApplyTo(&model.LatePenalty)
+	patch.LateStepMinutes.ApplyTo(&model.LateStepMinutes)
+
+	if err = validateTimelinePolicies(&model); err != nil {
+		return nil, err
 	}
 
-	if !newTimeline.Deadline.IsZero() {
-		timeline.Deadline = *newTimeline.Deadline
+	timelineModel, err := s.repo.UpdateTimeline(tx, timelineId, &model, version)
+	if errors.Is(err, pg.ErrNoRows) && version > 0 {
+		return nil, ErrStaleVersion
 	}
 
-	converted, err := strconv.ParseBool(*newTimeline.IsBlocking)
 	if err != nil {
 		return nil, err
 	}
 
-	timeline.IsBlocking = converted
+	if err = s.schedule.ValidateTracks(tx, before.TrackID, timelineModel.TrackID); err != nil {
+		return nil, err
+	}
 
-	if newTimeline.TrackId != nil {
-		timeline.TrackId = *newTimeline.TrackId
+	if err = s.audit.Record(ctx, tx, AuditEntityTimeline, AuditID(timelineId), AuditActionUpdate, before, timelineModel); err != nil {
+		return nil, err
 	}
 
-	if newTimeline.TimelineStatusId != nil {
-		timeline.TimelineStatusId = *newTimeline.TimelineStatusId
+	zones, err := s.timeZones(tx, timelineModel)
+	if err != nil {
+		return nil, err
 	}
 
-	if newTimeline.Status != nil {
-		timeline.Status = *newTimeline.Status
+	return SingleTimelineConvert(timelineModel, zones[timelineModel.TrackID]), nil
+}
+
+func (s *TimelineService) DeleteTimeline(ctx context.Context, timelineId int) (err error) {
+	ctx, span := tracing.Start(ctx, "TimelineService.DeleteTimeline")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return err
 	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
 
-	model := &models.Timeline{
-		Title:            timeline.Title,
-		Description:      timeline.Description,
-		Deadline:         timeline.Deadline,
-		IsBlocking:       timeline.IsBlocking,
-		Status:           timeline.Status,
-		TrackID:          timeline.TrackId,
-		TimelineStatusID: timeline.TimelineStatusId,
+		err = tx.Commit()
+	}()
+
+	timeline, err := s.repo.GetTimelineByID(tx, timelineId)
+	if err != nil {
+		return err
 	}
 
-	timelineModel, err := s.repo.UpdateTimeline(tx, timelineId, model)
+	if err = s.repo.DeleteTimeline(tx, timelineId, time.Now()); err != nil {
+		return err
+	}
+
+	return s.audit.Record(ctx, tx, AuditEntityTimeline, AuditID(timelineId), AuditActionDelete, timeline, nil)
+}
+
+func (s *TimelineService) GetDeletedTimelines(ctx context.Context) (_ []*timeline_api.TimelineResponse, err error) {
+	ctx, span := tracing.Start(ctx, "TimelineService.GetDeletedTimelines")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	timelineModels, err := s.repo.GetDeletedTimelines(tx)
 	if err != nil {
 		return nil, err
 	}
 
-	return SingleTimelineConvert(timelineModel), nil
+	zones, err := s.timeZones(tx, timelineModels...)
+	if err != nil {
+		return nil, err
+	}
+
+	return MultipleTimelineConvert(timelineModels, zones), nil
 }
 
-func (s *TimelineService) DeleteTimeline(timelineId int) (err error) {
-	tx, err := s.db.Begin()
+func (s *TimelineService) RestoreTimeline(ctx context.Context, timelineId int) (_ *timeline_api.TimelineResponse, err error) {
+	ctx, span := tracing.Start(ctx, "TimelineService.RestoreTimeline")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer func() {
 		if err != nil {
@@ -240,11 +440,38 @@ func (s *TimelineService) DeleteTimeline(timelineId int) (err error) {
 		err = tx.Commit()
 	}()
 
-	return s.repo.DeleteTimeline(tx, timelineId)
+	timelineModel, err := s.repo.RestoreTimeline(tx, timelineId)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = s.trackRepo.GetTrackByID(tx, timelineModel.TrackID); errors.Is(err, pg.ErrNoRows) {
+		return nil, Conflict("track %d of timeline is deleted, restore it first", timelineModel.TrackID)
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err = s.schedule.ValidateTracks(tx, timelineModel.TrackID); err != nil {
+		return nil, err
+	}
+
+	if err = s.audit.Record(ctx, tx, AuditEntityTimeline, AuditID(timelineId), AuditActionRestore, nil, timelineModel); err != nil {
+		return nil, err
+	}
+
+	zones, err := s.timeZones(tx, timelineModel)
+	if err != nil {
+		return nil, err
+	}
+
+	return SingleTimelineConvert(timelineModel, zones[timelineModel.TrackID]), nil
 }
 
-func (s *TimelineService) GetAllTimelineStatuses() (_ []*timeline_api.TimelineStatusResponse, err error) {
-	tx, err := s.db.Begin()
+func (s *TimelineService) GetAllTimelineStatuses(ctx context.Context) (_ []*timeline_api.TimelineStatusResponse, err error) {
+	ctx, span := tracing.Start(ctx, "TimelineService.GetAllTimelineStatuses")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -265,8 +492,11 @@ func (s *TimelineService) GetAllTimelineStatuses() (_ []*timeline_api.TimelineSt
 	return MultipleTimelineStatusConvert(timelineModels), nil
 }
 
-func (s *TimelineService) CreateTimelineStatus(timelineStatus *timeline_api.TimelineStatusResponse) (_ *timeline_api.TimelineStatusResponse, err error) {
-	tx, err := s.db.Begin()
+func (s *TimelineService) CreateTimelineStatus(ctx context.Context, timelineStatus *timeline_api.TimelineStatusResponse) (_ *timeline_api.TimelineStatusResponse, err error) {
+	ctx, span := tracing.Start(ctx, "TimelineService.CreateTimelineStatus")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -288,5 +518,75 @@ func (s *TimelineService) CreateTimelineStatus(timelineStatus *timeline_api.Time
 		return nil, err
 	}
 
+	auditId := AuditID(timelineModel.ID)
+	if err = s.audit.Record(ctx, tx, AuditEntityTimelineStatus, auditId, AuditActionCreate, nil, timelineModel); err != nil {
+		return nil, err
+	}
+
 	return &timeline_api.TimelineStatusResponse{CountNum: timelineModel.CountNum}, nil
 }
+
+func (s *TimelineService) timeZones(tx *pg.Tx, timelines ...*models.Timeline) (map[int]string, error) {
+	trackIds := make([]int, 0, len(timelines))
+	for _, timeline := range timelines {
+		trackIds = append(trackIds, timeline.TrackID)
+	}
+
+	return s.trackRepo.GetTimeZones(tx, trackIds)
+}
+
+// validateTimelinePolicies guards gen bodies, which are not checked by tags.
+// Empty policies leave the column defaults in place.
+func validateTimelinePolicies(model *models.Timeline) error {
+	violations := make([]Violation, 0)
+
+	switch model.CountedRevision {
+	case "", models.CountedRevisionFirst, models.CountedRevisionLast, models.CountedRevisionBest:
+	default:
+		violations = append(violations, Violation{
+			Field:   "counted_revision",
+			Rule:    "oneof",
+			Message: "counted_revision must be one of first, last, best",
+		})
+	}
+
+	switch model.LatePolicy {
+	case "", models.LatePolicyReject, models.LatePolicyGrace, models.LatePolicyLinear, models.LatePolicyFlag:
+	case models.LatePolicyStepwise:
+		if model.LateStepMinutes <= 0 {
+			violations = append(violations, Violation{
+				Field:   "late_step_minutes",
+				Rule:    RuleLatePolicy,
+				Message: "stepwise policy needs a positive late_step_minutes",
+			})
+		}
+	default:
+		violations = append(violations, Violation{
+			Field:   "late_policy",
+			Rule:    "oneof",
+			Message: "late_policy must be one of reject, grace, linear, stepwise, flag",
+		})
+	}
+
+	if model.LateGraceMinutes < 0 || model.LateStepMinutes < 0 {
+		violations = append(violations, Violation{
+			Field:   "late_grace_minutes",
+			Rule:    RuleLatePolicy,
+			Message: "late_grace_minutes and late_step_minutes cannot be negative",
+		})
+	}
+
+	if model.LatePenalty < 0 || model.LatePenalty > 100 {
+		violations = append(violations, Violation{
+			Field:   "late_penalty",
+			Rule:    RuleLatePolicy,
+			Message: "late_penalty must be between 0 and 100 percent",
+		})
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+
+	return &Error{Kind: ErrValidation, Violations: violations}
+}
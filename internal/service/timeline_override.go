@@ -0,0 +1,293 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"event_service/internal/models"
+	"event_service/internal/repositories"
+	"event_service/internal/schemas"
+	"event_service/pkg/tracing"
+	"event_service/pkg/utils"
+	"fmt"
+	"github.com/go-pg/pg/v10"
+	"time"
+)
+
+const RuleOverrideTeam = "override_team"
+
+func (s *TimelineService) GetTimelineOverrides(ctx context.Context, timelineId int) (_ []*models.TimelineOverride, err error) {
+	ctx, span := tracing.Start(ctx, "TimelineService.GetTimelineOverrides")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	if _, err = s.repo.GetTimelineByID(tx, timelineId); err != nil {
+		return nil, err
+	}
+
+	return s.overrideRepo.GetOverridesByTimelineID(tx, timelineId)
+}
+
+// SetTimelineOverride creates or replaces the override of one team. The
+// caller becomes its author, so anonymous requests are refused.
+func (s *TimelineService) SetTimelineOverride(ctx context.Context, timelineId int, trackTeamId int,
+	override schemas.TimelineOverride) (_ *models.TimelineOverride, err error) {
+	ctx, span := tracing.Start(ctx, "TimelineService.SetTimelineOverride")
+	defer func() { tracing.End(span, err) }()
+
+	authorId, ok := utils.ActorFromContext(ctx)
+	if !ok {
+		return nil, Forbidden("overrides need an authenticated author")
+	}
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	timeline, err := s.repo.GetTimelineByID(tx, timelineId)
+	if err != nil {
+		return nil, err
+	}
+
+	trackTeam, err := s.trackTeamRepo.GetTrackTeamByID(tx, trackTeamId)
+	if err != nil {
+		return nil, err
+	}
+
+	if trackTeam.TrackID != timeline.TrackID {
+		return nil, &Error{
+			Kind:   ErrValidation,
+			Detail: fmt.Sprintf("team %d is not registered for track %d", trackTeamId, timeline.TrackID),
+			Violations: []Violation{{
+				Field:   "team_id",
+				Rule:    RuleOverrideTeam,
+				Message: "team is not registered for the track of this timeline",
+			}},
+		}
+	}
+
+	before, err := s.overrideRepo.GetOverride(tx, timelineId, trackTeamId)
+	if errors.Is(err, pg.ErrNoRows) {
+		before, err = nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	model := &models.TimelineOverride{
+		TimelineID:  timelineId,
+		TrackTeamID: trackTeamId,
+		IsBlocking:  override.IsBlocking,
+		Reason:      override.Reason,
+		AuthorID:    authorId,
+	}
+
+	if override.Deadline != nil {
+		model.Deadline = *override.Deadline
+	}
+
+	saved, err := s.overrideRepo.Upsert(tx, model)
+	if err != nil {
+		return nil, err
+	}
+
+	action := AuditActionUpdate
+	if before == nil {
+		action = AuditActionCreate
+	}
+
+	auditId := AuditID(timelineId, trackTeamId)
+	if err = s.audit.Record(ctx, tx, AuditEntityTimelineOverride, auditId, action, before, saved); err != nil {
+		return nil, err
+	}
+
+	return saved, nil
+}
+
+func (s *TimelineService) DeleteTimelineOverride(ctx context.Context, timelineId int, trackTeamId int) (err error) {
+	ctx, span := tracing.Start(ctx, "TimelineService.DeleteTimelineOverride")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	before, err := s.overrideRepo.GetOverride(tx, timelineId, trackTeamId)
+	if err != nil {
+		return err
+	}
+
+	if err = s.overrideRepo.DeleteOverride(tx, timelineId, trackTeamId); err != nil {
+		return err
+	}
+
+	auditId := AuditID(timelineId, trackTeamId)
+	return s.audit.Record(ctx, tx, AuditEntityTimelineOverride, auditId, AuditActionDelete, before, nil)
+}
+
+// GetTeamTimelines lists the timelines of a registered team's track with the
+// team's own deadlines, which is what its dashboard shows.
+func (s *TimelineService) GetTeamTimelines(ctx context.Context, trackTeamId int) (_ []*schemas.TeamTimeline, err error) {
+	ctx, span := tracing.Start(ctx, "TimelineService.GetTeamTimelines")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	trackTeam, err := s.trackTeamRepo.GetTrackTeamByID(tx, trackTeamId)
+	if err != nil {
+		return nil, err
+	}
+
+	timelines, err := s.repo.GetTimelinesByTrackIDs(tx, []int{trackTeam.TrackID})
+	if err != nil {
+		return nil, err
+	}
+
+	overrides, err := s.overrideRepo.GetOverridesByTrackTeamIDs(tx, []int{trackTeamId})
+	if err != nil {
+		return nil, err
+	}
+
+	byTimeline := overridesByTimeline(overrides)
+	now := time.Now()
+	result := make([]*schemas.TeamTimeline, 0, len(timelines))
+
+	for _, timeline := range timelines {
+		override := byTimeline[timeline.ID]
+		effective := applyOverride(timeline, override)
+
+		result = append(result, &schemas.TeamTimeline{
+			TimelineID: effective.ID,
+			Title:      effective.Title,
+			Status:     effective.Status,
+			Deadline:   effective.Deadline,
+			IsBlocking: effective.IsBlocking,
+			IsExpired:  !effective.Deadline.IsZero() && !effective.Deadline.After(now),
+			Override:   override,
+		})
+	}
+
+	return result, nil
+}
+
+func (s *TimelineService) GetAllTimelinesToExpire(ctx context.Context) (_ []*models.Timeline, err error) {
+	ctx, span := tracing.Start(ctx, "TimelineService.GetAllTimelinesToExpire")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	return s.repo.GetAllTimelinesToExpire(tx)
+}
+
+func (s *TimelineService) ExpireTimeline(ctx context.Context, timelineId int) error {
+	patch := schemas.TimelinePatch{Status: schemas.Optional[string]{Set: true, Value: "expired"}}
+
+	_, err := s.PatchTimeline(ctx, timelineId, patch, 0)
+	return err
+}
+
+// teamTimeline loads a timeline with the override of the given team applied.
+func teamTimeline(tx *pg.Tx, timelineRepo *repositories.TimelineRepository, overrideRepo *repositories.TimelineOverrideRepository,
+	timelineId int, trackTeamId int) (*models.Timeline, error) {
+	timeline, err := timelineRepo.GetTimelineByID(tx, timelineId)
+	if err != nil {
+		return nil, err
+	}
+
+	override, err := overrideRepo.GetOverride(tx, timelineId, trackTeamId)
+	if errors.Is(err, pg.ErrNoRows) {
+		return timeline, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return applyOverride(timeline, override), nil
+}
+
+// applyOverride returns a copy of the timeline with the fields set by the
+// override replaced. A nil override leaves the timeline as it is.
+func applyOverride(timeline *models.Timeline, override *models.TimelineOverride) *models.Timeline {
+	effective := *timeline
+
+	if override == nil {
+		return &effective
+	}
+
+	if !override.Deadline.IsZero() {
+		effective.Deadline = override.Deadline
+	}
+
+	if override.IsBlocking != nil {
+		effective.IsBlocking = *override.IsBlocking
+	}
+
+	return &effective
+}
+
+func overridesByTimeline(overrides []*models.TimelineOverride) map[int]*models.TimelineOverride {
+	byTimeline := make(map[int]*models.TimelineOverride, len(overrides))
+	for _, override := range overrides {
+		byTimeline[override.TimelineID] = override
+	}
+
+	return byTimeline
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"event_service/internal/models"
+	"time"
+)
+
+const DefaultTimeZone = "UTC"
+
+// LoadTimeZone resolves an IANA zone name, an empty name means UTC.
+func LoadTimeZone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, Validation("unknown time zone %q", name)
+	}
+
+	return location, nil
+}
+
+func validateTimeZone(name string) error {
+	_, err := LoadTimeZone(name)
+	return err
+}
+
+// localTimes renders the given columns in zone, skipping unset ones. Zones
+// are validated on write, so a zone that fails to load falls back to UTC.
+func localTimes(zone string, times map[string]time.Time) models.LocalTimes {
+	location, err := LoadTimeZone(zone)
+	if err != nil {
+		location = time.UTC
+	}
+
+	local := make(models.LocalTimes, len(times))
+	for name, value := range times {
+		if !value.IsZero() {
+			local[name] = value.In(location)
+		}
+	}
+
+	return local
+}
+
+func localizeEvent(event *models.Event) {
+	times := map[string]time.Time{
+		"CreatedAt": event.CreatedAt,
+		"UpdatedAt": event.UpdatedAt,
+	}
+
+	if hasDate(event.Date) {
+		times["DateStart"] = event.Date.DateStart
+		times["DateEnd"] = event.Date.DateEnd
+	}
+
+	event.Local = localTimes(event.TimeZone, times)
+}
+
+func localizeEvents(events []*models.Event) []*models.Event {
+	for _, event := range events {
+		localizeEvent(event)
+	}
+
+	return events
+}
+
+func localizeTrack(track *models.Track, zone string) {
+	times := map[string]time.Time{
+		"CreatedAt": track.CreatedAt,
+		"UpdatedAt": track.UpdatedAt,
+	}
+
+	if hasDate(track.Date) {
+		times["DateStart"] = track.Date.DateStart
+		times["DateEnd"] = track.Date.DateEnd
+	}
+
+	track.Local = localTimes(zone, times)
+}
@@ -1,32 +1,78 @@
 package service
 
 import (
+	"context"
+	"errors"
+	status_api "event_service/gen/status"
 	"event_service/internal/models"
 	"event_service/internal/repositories"
 	"event_service/internal/schemas"
+	"event_service/pkg/teams"
+	"event_service/pkg/tracing"
 	"fmt"
 	"github.com/go-pg/pg/v10"
-	"strconv"
+	"slices"
+	"time"
 )
 
+const RuleRegistrationState = "registration_state"
+
+var trackTeamTransitions = map[string][]string{
+	models.TrackTeamPending:    {models.TrackTeamApproved, models.TrackTeamRejected, models.TrackTeamWithdrawn},
+	models.TrackTeamWaitlisted: {models.TrackTeamApproved, models.TrackTeamRejected, models.TrackTeamWithdrawn},
+	models.TrackTeamApproved:   {models.TrackTeamRejected, models.TrackTeamWithdrawn},
+}
+
 type TrackService struct {
 	repo *repositories.TrackRepository
 
+	eventRepo    *repositories.EventRepository
+	timelineRepo *repositories.TimelineRepository
+
 	locationTrackRepo *repositories.LocationTrackRepository
 	trackTeamRepo     *repositories.TrackTeamRepository
+	bookingRepo       *repositories.LocationBookingRepository
+	statusTrackRepo   *repositories.StatusTrackRepository
+	eligibilityRepo   *repositories.TrackEligibilityRepository
+	trackRoleRepo     *repositories.TrackRoleRepository
+
+	audit    *AuditService
+	schedule *ScheduleValidator
+	teams    teams.Client
+	stats    *StatisticsCache
 
 	db *pg.DB
 }
 
-func NewTrackService(repo *repositories.TrackRepository, db *pg.DB) *TrackService {
+func NewTrackService(repo *repositories.TrackRepository, eventRepo *repositories.EventRepository,
+	timelineRepo *repositories.TimelineRepository, locationTrackRepo *repositories.LocationTrackRepository,
+	trackTeamRepo *repositories.TrackTeamRepository, bookingRepo *repositories.LocationBookingRepository,
+	statusTrackRepo *repositories.StatusTrackRepository, eligibilityRepo *repositories.TrackEligibilityRepository,
+	trackRoleRepo *repositories.TrackRoleRepository, audit *AuditService, schedule *ScheduleValidator, teams teams.Client,
+	stats *StatisticsCache, db *pg.DB) *TrackService {
 	return &TrackService{
-		repo: repo,
-		db:   db,
+		repo:              repo,
+		eventRepo:         eventRepo,
+		timelineRepo:      timelineRepo,
+		locationTrackRepo: locationTrackRepo,
+		trackTeamRepo:     trackTeamRepo,
+		bookingRepo:       bookingRepo,
+		statusTrackRepo:   statusTrackRepo,
+		eligibilityRepo:   eligibilityRepo,
+		trackRoleRepo:     trackRoleRepo,
+		audit:             audit,
+		schedule:          schedule,
+		teams:             teams,
+		stats:             stats,
+		db:                db,
 	}
 }
 
-func (s *TrackService) GetAllTracks() ([]*models.Track, error) {
-	tx, err := s.db.Begin()
+func (s *TrackService) GetAllTracks(ctx context.Context, filter *schemas.TrackFilter) (_ []*models.Track, err error) {
+	ctx, span := tracing.Start(ctx, "TrackService.GetAllTracks")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -40,11 +86,23 @@ func (s *TrackService) GetAllTracks() ([]*models.Track, error) {
 		err = tx.Commit()
 	}()
 
-	return s.repo.GetAllTracks(tx)
+	tracks, err := s.repo.GetAllTracks(tx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.localizeTracks(tx, tracks...); err != nil {
+		return nil, err
+	}
+
+	return tracks, nil
 }
 
-func (s *TrackService) GetTrackById(trackId int) (_ *models.Track, err error) {
-	tx, err := s.db.Begin()
+func (s *TrackService) GetTrackById(ctx context.Context, trackId int) (_ *models.Track, err error) {
+	ctx, span := tracing.Start(ctx, "TrackService.GetTrackById")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -58,11 +116,27 @@ func (s *TrackService) GetTrackById(trackId int) (_ *models.Track, err error) {
 		err = tx.Commit()
 	}()
 
-	return s.repo.GetTrackByID(tx, trackId)
+	tracks, err := s.repo.GetTracksWithDateByIDs(tx, []int{trackId})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tracks) == 0 {
+		return nil, pg.ErrNoRows
+	}
+
+	if err = s.localizeTracks(tx, tracks...); err != nil {
+		return nil, err
+	}
+
+	return tracks[0], nil
 }
 
-func (s *TrackService) GetTracksByEventId(eventId int) (_ []*models.Track, err error) {
-	tx, err := s.db.Begin()
+func (s *TrackService) GetTracksByEventId(ctx context.Context, eventId int) (_ []*models.Track, err error) {
+	ctx, span := tracing.Start(ctx, "TrackService.GetTracksByEventId")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -76,11 +150,23 @@ func (s *TrackService) GetTracksByEventId(eventId int) (_ []*models.Track, err e
 		err = tx.Commit()
 	}()
 
-	return s.repo.GetAllTracksByEventID(tx, eventId)
+	tracks, err := s.repo.GetAllTracksByEventID(tx, eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.localizeTracks(tx, tracks...); err != nil {
+		return nil, err
+	}
+
+	return tracks, nil
 }
 
-func (s *TrackService) StartTrack(trackId int) (_ *models.Track, err error) {
-	tx, err := s.db.Begin()
+func (s *TrackService) StartTrack(ctx context.Context, trackId int) (_ *models.Track, err error) {
+	ctx, span := tracing.Start(ctx, "TrackService.StartTrack")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -94,11 +180,14 @@ func (s *TrackService) StartTrack(trackId int) (_ *models.Track, err error) {
 		_ = tx.Commit()
 	}()
 
-	return s.UpdateTrack(trackId, schemas.TrackUpdate{Status: "in_process"})
+	return s.UpdateTrack(ctx, trackId, schemas.TrackUpdate{Status: "in_process"}, 0)
 }
 
-func (s *TrackService) EndTrack(trackId int) (_ *models.Track, err error) {
-	tx, err := s.db.Begin()
+func (s *TrackService) EndTrack(ctx context.Context, trackId int) (_ *models.Track, err error) {
+	ctx, span := tracing.Start(ctx, "TrackService.EndTrack")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -112,11 +201,14 @@ func (s *TrackService) EndTrack(trackId int) (_ *models.Track, err error) {
 		_ = tx.Commit()
 	}()
 
-	return s.UpdateTrack(trackId, schemas.TrackUpdate{Status: "completed"})
+	return s.UpdateTrack(ctx, trackId, schemas.TrackUpdate{Status: "completed"}, 0)
 }
 
-func (s *TrackService) GetAllTracksToStart() (_ []*models.Track, err error) {
-	tx, err := s.db.Begin()
+func (s *TrackService) GetAllTracksToStart(ctx context.Context) (_ []*models.Track, err error) {
+	ctx, span := tracing.Start(ctx, "TrackService.GetAllTracksToStart")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -133,8 +225,11 @@ func (s *TrackService) GetAllTracksToStart() (_ []*models.Track, err error) {
 	return s.repo.GetAllTracksToStart(tx)
 }
 
-func (s *TrackService) GetAllTracksToEnd() (_ []*models.Track, err error) {
-	tx, err := s.db.Begin()
+func (s *TrackService) GetAllTracksToEnd(ctx context.Context) (_ []*models.Track, err error) {
+	ctx, span := tracing.Start(ctx, "TrackService.GetAllTracksToEnd")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -151,8 +246,11 @@ func (s *TrackService) GetAllTracksToEnd() (_ []*models.Track, err error) {
 	return s.repo.GetAllTracksToEnd(tx)
 }
 
-func (s *TrackService) CreateTrack(track schemas.Track) (_ *models.Track, err error) {
-	tx, err := s.db.Begin()
+func (s *TrackService) CreateTrack(ctx context.Context, track schemas.Track) (_ *models.Track, err error) {
+	ctx, span := tracing.Start(ctx, "TrackService.CreateTrack")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -172,13 +270,41 @@ func (s *TrackService) CreateTrack(track schemas.Track) (_ *models.Track, err er
 		IsScoreBased: track.IsScoreBased,
 		EventID:      track.EventID,
 		DateID:       track.DateID,
+
+		RegistrationOpensAt:  track.RegistrationOpensAt,
+		RegistrationClosesAt: track.RegistrationClosesAt,
+		MaxTeams:             track.MaxTeams,
 	}
 
-	return s.repo.Create(tx, trackModel)
+	created, err := s.repo.Create(tx, trackModel)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.schedule.ValidateTracks(tx, created.ID); err != nil {
+		return nil, err
+	}
+
+	if err = s.audit.Record(ctx, tx, AuditEntityTrack, AuditID(created.ID), AuditActionCreate, nil, created); err != nil {
+		return nil, err
+	}
+
+	if err = s.localizeTracks(tx, created); err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (s *TrackService) UpdateTrack(ctx context.Context, trackId int, newTrack schemas.TrackUpdate, version int) (*models.Track, error) {
+	return s.PatchTrack(ctx, trackId, newTrack.Patch(), version)
 }
 
-func (s *TrackService) UpdateTrack(trackId int, newTrack schemas.TrackUpdate) (*models.Track, error) {
-	tx, err := s.db.Begin()
+func (s *TrackService) PatchTrack(ctx context.Context, trackId int, patch schemas.TrackPatch, version int) (_ *models.Track, err error) {
+	ctx, span := tracing.Start(ctx, "TrackService.PatchTrack")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -192,45 +318,146 @@ func (s *TrackService) UpdateTrack(trackId int, newTrack schemas.TrackUpdate) (*
 		err = tx.Commit()
 	}()
 
-	track, err := s.GetTrackById(trackId)
+	track, err := s.GetTrackById(ctx, trackId)
 	if err != nil {
 		return nil, err
 	}
 
-	if newTrack.Title != "" {
-		track.Title = newTrack.Title
+	if version > 0 && track.Version != version {
+		return nil, ErrStaleVersion
 	}
 
-	if newTrack.Description != "" {
-		track.Description = newTrack.Description
+	before := *track
+
+	patch.Title.ApplyTo(&track.Title)
+	patch.Description.ApplyTo(&track.Description)
+	patch.IsScoreBased.ApplyTo(&track.IsScoreBased)
+	patch.EventID.ApplyTo(&track.EventID)
+	patch.DateID.ApplyTo(&track.DateID)
+	patch.Status.ApplyTo(&track.Status)
+	patch.RegistrationOpensAt.ApplyTo(&track.RegistrationOpensAt)
+	patch.RegistrationClosesAt.ApplyTo(&track.RegistrationClosesAt)
+	patch.MaxTeams.ApplyTo(&track.MaxTeams)
+
+	if err = validateRegistrationWindow(track); err != nil {
+		return nil, err
 	}
 
-	if newTrack.IsScoreBased != "" {
-		isScoreBased, err := strconv.ParseBool(newTrack.IsScoreBased)
-		if err != nil {
-			return nil, fmt.Errorf("invalid value for IsScoreBased: %v", err)
+	updated, err := s.repo.UpdateTrack(tx, trackId, track, version)
+	if errors.Is(err, pg.ErrNoRows) && version > 0 {
+		return nil, ErrStaleVersion
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.schedule.ValidateTracks(tx, trackId); err != nil {
+		return nil, err
+	}
+
+	if updated.DateID != before.DateID || updated.Title != before.Title {
+		if err = s.bookingRepo.SyncTrackBookings(tx, []int{trackId}); err != nil {
+			return nil, err
 		}
+	}
 
-		track.IsScoreBased = isScoreBased
+	if err = s.audit.Record(ctx, tx, AuditEntityTrack, AuditID(trackId), AuditActionUpdate, &before, updated); err != nil {
+		return nil, err
 	}
 
-	if newTrack.EventID != 0 {
-		track.EventID = newTrack.EventID
+	if updated.MaxTeams != before.MaxTeams {
+		if err = s.promoteWaitlisted(ctx, tx, updated); err != nil {
+			return nil, err
+		}
 	}
 
-	if newTrack.DateID != 0 {
-		track.DateID = newTrack.DateID
+	if err = s.localizeTracks(tx, updated); err != nil {
+		return nil, err
 	}
 
-	return s.repo.UpdateTrack(tx, trackId, track)
+	return updated, nil
 }
 
-func (s *TrackService) DeleteTrack(trackId int) error {
-	tx, err := s.db.Begin()
+func (s *TrackService) DeleteTrack(ctx context.Context, trackId int) (err error) {
+	ctx, span := tracing.Start(ctx, "TrackService.DeleteTrack")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	track, err := s.repo.GetTrackByID(tx, trackId)
 	if err != nil {
 		return err
 	}
 
+	deletedAt := time.Now()
+
+	if err = s.repo.DeleteTrack(tx, trackId, deletedAt); err != nil {
+		return err
+	}
+
+	if err = s.timelineRepo.DeleteTimelinesByTrackIDs(tx, []int{trackId}, deletedAt); err != nil {
+		return err
+	}
+
+	if err = s.bookingRepo.SyncTrackBookings(tx, []int{trackId}); err != nil {
+		return err
+	}
+
+	return s.audit.Record(ctx, tx, AuditEntityTrack, AuditID(trackId), AuditActionDelete, track, nil)
+}
+
+func (s *TrackService) GetDeletedTracks(ctx context.Context) (_ []*models.Track, err error) {
+	ctx, span := tracing.Start(ctx, "TrackService.GetDeletedTracks")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	tracks, err := s.repo.GetDeletedTracks(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.localizeTracks(tx, tracks...); err != nil {
+		return nil, err
+	}
+
+	return tracks, nil
+}
+
+func (s *TrackService) RestoreTrack(ctx context.Context, trackId int) (_ *models.Track, err error) {
+	ctx, span := tracing.Start(ctx, "TrackService.RestoreTrack")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	defer func() {
 		if err != nil {
 			_ = tx.Rollback()
@@ -240,11 +467,50 @@ func (s *TrackService) DeleteTrack(trackId int) error {
 		err = tx.Commit()
 	}()
 
-	return s.repo.DeleteTrack(tx, trackId)
+	deleted, err := s.repo.GetDeletedTrackByID(tx, trackId)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = s.eventRepo.GetEventByID(tx, deleted.EventID); errors.Is(err, pg.ErrNoRows) {
+		return nil, Conflict("event %d of track is deleted, restore it first", deleted.EventID)
+	} else if err != nil {
+		return nil, err
+	}
+
+	track, err := s.repo.RestoreTrack(tx, trackId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.timelineRepo.RestoreTimelinesByTrackIDs(tx, []int{trackId}, deleted.DeletedAt); err != nil {
+		return nil, err
+	}
+
+	if err = s.bookingRepo.SyncTrackBookings(tx, []int{trackId}); err != nil {
+		return nil, err
+	}
+
+	if err = s.schedule.ValidateTracks(tx, trackId); err != nil {
+		return nil, err
+	}
+
+	if err = s.audit.Record(ctx, tx, AuditEntityTrack, AuditID(trackId), AuditActionRestore, deleted, track); err != nil {
+		return nil, err
+	}
+
+	if err = s.localizeTracks(tx, track); err != nil {
+		return nil, err
+	}
+
+	return track, nil
 }
 
-func (s *TrackService) GetAllTrackLocations(trackId int) (_ []*models.Location, err error) {
-	tx, err := s.db.Begin()
+func (s *TrackService) GetAllTrackLocations(ctx context.Context, trackId int) (_ []*models.Location, err error) {
+	ctx, span := tracing.Start(ctx, "TrackService.GetAllTrackLocations")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -261,8 +527,11 @@ func (s *TrackService) GetAllTrackLocations(trackId int) (_ []*models.Location,
 	return s.locationTrackRepo.GetAllTracksLocations(tx, trackId)
 }
 
-func (s *TrackService) AddLocationToTrack(locationTrackSchema *schemas.LocationTrack) (_ *models.LocationTrack, err error) {
-	tx, err := s.db.Begin()
+func (s *TrackService) AddLocationToTrack(ctx context.Context, locationTrackSchema *schemas.LocationTrack) (_ *models.LocationTrack, err error) {
+	ctx, span := tracing.Start(ctx, "TrackService.AddLocationToTrack")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -281,11 +550,32 @@ func (s *TrackService) AddLocationToTrack(locationTrackSchema *schemas.LocationT
 		LocationId: locationTrackSchema.LocationId,
 	}
 
-	return s.locationTrackRepo.Create(tx, statusEventModel)
+	created, err := s.locationTrackRepo.Create(tx, statusEventModel)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.checkTeamCapacity(tx, created.TrackId); err != nil {
+		return nil, err
+	}
+
+	if err = s.bookingRepo.SyncTrackBookings(tx, []int{created.TrackId}); err != nil {
+		return nil, err
+	}
+
+	auditId := AuditID(created.TrackId, created.LocationId)
+	if err = s.audit.Record(ctx, tx, AuditEntityLocationTrack, auditId, AuditActionCreate, nil, created); err != nil {
+		return nil, err
+	}
+
+	return created, nil
 }
 
-func (s *TrackService) RemoveLocationFromTrack(locationTrackSchema *schemas.LocationTrack) (err error) {
-	tx, err := s.db.Begin()
+func (s *TrackService) RemoveLocationFromTrack(ctx context.Context, locationTrackSchema *schemas.LocationTrack) (err error) {
+	ctx, span := tracing.Start(ctx, "TrackService.RemoveLocationFromTrack")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return err
 	}
@@ -299,11 +589,57 @@ func (s *TrackService) RemoveLocationFromTrack(locationTrackSchema *schemas.Loca
 		_ = tx.Commit()
 	}()
 
-	return s.locationTrackRepo.DeleteTrackLocation(tx, locationTrackSchema.TrackId, locationTrackSchema.LocationId)
+	if err = s.locationTrackRepo.DeleteTrackLocation(tx, locationTrackSchema.TrackId, locationTrackSchema.LocationId); err != nil {
+		return err
+	}
+
+	if err = s.bookingRepo.SyncTrackBookings(tx, []int{locationTrackSchema.TrackId}); err != nil {
+		return err
+	}
+
+	auditId := AuditID(locationTrackSchema.TrackId, locationTrackSchema.LocationId)
+	return s.audit.Record(ctx, tx, AuditEntityLocationTrack, auditId, AuditActionDelete, locationTrackSchema, nil)
+}
+
+// GetRegisteredTeams lists registrations of a track. With withTeams the
+// teams are resolved through the user and teams service on a best effort
+// basis, registrations are returned as is when the service is unavailable.
+func (s *TrackService) GetRegisteredTeams(ctx context.Context, trackId int, state string, withTeams bool) (_ []*schemas.RegisteredTeam, err error) {
+	ctx, span := tracing.Start(ctx, "TrackService.GetRegisteredTeams")
+	defer func() { tracing.End(span, err) }()
+
+	trackTeams, err := s.getTrackTeams(ctx, trackId, state)
+	if err != nil {
+		return nil, err
+	}
+
+	registered := make([]*schemas.RegisteredTeam, 0, len(trackTeams))
+	teamIds := make([]int, 0, len(trackTeams))
+	for _, trackTeam := range trackTeams {
+		registered = append(registered, &schemas.RegisteredTeam{TrackTeam: trackTeam})
+		teamIds = append(teamIds, trackTeam.TeamID)
+	}
+
+	if !withTeams || len(teamIds) == 0 {
+		return registered, nil
+	}
+
+	// Teams are resolved once the read is committed, so a slow user and
+	// teams service does not hold a transaction open.
+	found, err := s.teams.GetTeams(ctx, teamIds)
+	if err != nil {
+		return registered, nil
+	}
+
+	for _, team := range registered {
+		team.Team = found[team.TeamID]
+	}
+
+	return registered, nil
 }
 
-func (s *TrackService) GetRegisteredTeams(trackId int) (_ []*models.TrackTeam, err error) {
-	tx, err := s.db.Begin()
+func (s *TrackService) getTrackTeams(ctx context.Context, trackId int, state string) (_ []*models.TrackTeam, err error) {
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -317,11 +653,21 @@ func (s *TrackService) GetRegisteredTeams(trackId int) (_ []*models.TrackTeam, e
 		_ = tx.Commit()
 	}()
 
-	return s.trackTeamRepo.GetTeamsByTrackID(tx, trackId)
+	return s.trackTeamRepo.GetTeamsByTrackID(tx, trackId, state)
 }
 
-func (s *TrackService) RegisterTeam(trackTeam *schemas.TrackTeam) (_ *models.TrackTeam, err error) {
-	tx, err := s.db.Begin()
+// RegisterTeam files a pending registration, or puts the team on the
+// waitlist when pending and approved registrations already take every slot.
+func (s *TrackService) RegisterTeam(ctx context.Context, trackTeam *schemas.TrackTeam) (_ *models.TrackTeam, err error) {
+	ctx, span := tracing.Start(ctx, "TrackService.RegisterTeam")
+	defer func() { tracing.End(span, err) }()
+
+	team, err := getTeam(ctx, s.teams, trackTeam.TeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -332,20 +678,67 @@ func (s *TrackService) RegisterTeam(trackTeam *schemas.TrackTeam) (_ *models.Tra
 			return
 		}
 
-		_ = tx.Commit()
+		err = tx.Commit()
 	}()
 
+	track, err := s.repo.LockTrackByID(tx, trackTeam.TrackID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkRegistrationWindow(track, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if _, err = s.trackTeamRepo.GetByTrackIDAndTeamID(tx, track.ID, trackTeam.TeamID); err == nil {
+		return nil, Conflict("team %d is already registered for track %d", trackTeam.TeamID, track.ID)
+	} else if !errors.Is(err, pg.ErrNoRows) {
+		return nil, err
+	}
+
+	// The team may be registering for another track at the same time, so its
+	// other registrations are counted under a lock on the team.
+	if err = s.trackTeamRepo.LockTeam(tx, trackTeam.TeamID); err != nil {
+		return nil, err
+	}
+
+	if err = s.checkEligibility(tx, track.ID, team); err != nil {
+		return nil, err
+	}
+
+	held, err := s.trackTeamRepo.CountHeldSlots(tx, track.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	model := &models.TrackTeam{
-		TrackID:  trackTeam.TrackID,
-		TeamID:   trackTeam.TeamID,
-		IsActive: trackTeam.IsActive,
+		TrackID: track.ID,
+		TeamID:  trackTeam.TeamID,
+		State:   models.TrackTeamPending,
+	}
+
+	if track.MaxTeams > 0 && held >= track.MaxTeams {
+		model.State = models.TrackTeamWaitlisted
 	}
 
-	return s.trackTeamRepo.Create(tx, model)
+	created, err := s.trackTeamRepo.Create(tx, model)
+	if err != nil {
+		return nil, err
+	}
+
+	auditId := AuditID(created.TrackID, created.TeamID)
+	if err = s.audit.Record(ctx, tx, AuditEntityTrackTeam, auditId, AuditActionCreate, nil, created); err != nil {
+		return nil, err
+	}
+
+	return created, nil
 }
 
-func (s *TrackService) GetCertainRegisteredTeam(trackId int, teamId int) (_ *models.TrackTeam, err error) {
-	tx, err := s.db.Begin()
+func (s *TrackService) GetCertainRegisteredTeam(ctx context.Context, trackId int, teamId int) (_ *models.TrackTeam, err error) {
+	ctx, span := tracing.Start(ctx, "TrackService.GetCertainRegisteredTeam")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -362,8 +755,15 @@ func (s *TrackService) GetCertainRegisteredTeam(trackId int, teamId int) (_ *mod
 	return s.trackTeamRepo.GetRoleByTrackIDAndTeamID(tx, trackId, teamId)
 }
 
-func (s *TrackService) UpdateRegisteredTeam(trackId int, teamId int, newTrackTeam schemas.TrackTeamUpdate) (_ *models.TrackTeam, err error) {
-	tx, err := s.db.Begin()
+func (s *TrackService) UpdateRegisteredTeam(ctx context.Context, trackId int, teamId int, newTrackTeam schemas.TrackTeamUpdate) (*models.TrackTeam, error) {
+	return s.PatchRegisteredTeam(ctx, trackId, teamId, newTrackTeam.Patch())
+}
+
+func (s *TrackService) PatchRegisteredTeam(ctx context.Context, trackId int, teamId int, patch schemas.TrackTeamPatch) (_ *models.TrackTeam, err error) {
+	ctx, span := tracing.Start(ctx, "TrackService.PatchRegisteredTeam")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -374,28 +774,177 @@ func (s *TrackService) UpdateRegisteredTeam(trackId int, teamId int, newTrackTea
 			return
 		}
 
-		_ = tx.Commit()
+		err = tx.Commit()
 	}()
 
-	trackTeam, err := s.GetCertainRegisteredTeam(trackId, teamId)
+	track, err := s.repo.LockTrackByID(tx, trackId)
+	if err != nil {
+		return nil, err
+	}
+
+	trackTeam, err := s.trackTeamRepo.GetByTrackIDAndTeamID(tx, trackId, teamId)
 	if err != nil {
 		return nil, err
 	}
 
-	if newTrackTeam.IsActive != "" {
-		converted, err := strconv.ParseBool(newTrackTeam.IsActive)
+	before := *trackTeam
+
+	if patch.State.Set && patch.State.Value != trackTeam.State {
+		if err = transitionTrackTeam(trackTeam, patch.State.Value, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	patch.IsActive.ApplyTo(&trackTeam.IsActive)
+
+	if trackTeam.IsActive && trackTeam.State != models.TrackTeamApproved {
+		return nil, &Error{
+			Kind:   ErrValidation,
+			Detail: "registration cannot be applied",
+			Violations: []Violation{{
+				Field:   "is_active",
+				Rule:    RuleRegistrationState,
+				Message: fmt.Sprintf("only approved teams can be active, team %d is %s", teamId, trackTeam.State),
+			}},
+		}
+	}
+
+	updated, err := s.trackTeamRepo.UpdateTrackTeam(tx, trackId, teamId, trackTeam)
+	if err != nil {
+		return nil, err
+	}
+
+	if before.State == models.TrackTeamWaitlisted && updated.State == models.TrackTeamApproved {
+		if err = s.checkMaxTeams(tx, track); err != nil {
+			return nil, err
+		}
+	}
+
+	if updated.IsActive && !before.IsActive {
+		if err = s.checkTeamCapacity(tx, trackId); err != nil {
+			return nil, err
+		}
+	}
+
+	auditId := AuditID(trackId, teamId)
+	if err = s.audit.Record(ctx, tx, AuditEntityTrackTeam, auditId, AuditActionUpdate, &before, updated); err != nil {
+		return nil, err
+	}
+
+	if holdsSlot(before.State) && !holdsSlot(updated.State) {
+		if err = s.promoteWaitlisted(ctx, tx, track); err != nil {
+			return nil, err
+		}
+	}
+
+	return updated, nil
+}
+
+// ApproveRegisteredTeams approves the given registrations together, either
+// all of them or none. Teams that are approved already are left as they are.
+func (s *TrackService) ApproveRegisteredTeams(ctx context.Context, trackId int, approval schemas.TrackTeamApproval) (_ []*models.TrackTeam, err error) {
+	ctx, span := tracing.Start(ctx, "TrackService.ApproveRegisteredTeams")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	track, err := s.repo.LockTrackByID(tx, trackId)
+	if err != nil {
+		return nil, err
+	}
+
+	trackTeams, err := s.trackTeamRepo.GetByTrackIDAndTeamIDs(tx, trackId, approval.TeamIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[int]*models.TrackTeam, len(trackTeams))
+	for _, trackTeam := range trackTeams {
+		found[trackTeam.TeamID] = trackTeam
+	}
+
+	for _, teamId := range approval.TeamIDs {
+		if _, ok := found[teamId]; !ok {
+			return nil, NotFound("team %d is not registered for track %d", teamId, trackId)
+		}
+	}
+
+	now := time.Now()
+	violations := make([]Violation, 0)
+	changed := make([]*models.TrackTeam, 0, len(trackTeams))
+	befores := make(map[int]models.TrackTeam, len(trackTeams))
+
+	for _, trackTeam := range trackTeams {
+		if trackTeam.State == models.TrackTeamApproved {
+			continue
+		}
+
+		befores[trackTeam.TeamID] = *trackTeam
+
+		if err := transitionTrackTeam(trackTeam, models.TrackTeamApproved, now); err != nil {
+			var domainErr *Error
+			if !errors.As(err, &domainErr) {
+				return nil, err
+			}
+
+			violations = append(violations, domainErr.Violations...)
+			continue
+		}
+
+		changed = append(changed, trackTeam)
+	}
+
+	if len(violations) > 0 {
+		return nil, &Error{
+			Kind:       ErrValidation,
+			Detail:     "registrations cannot be approved",
+			Violations: violations,
+		}
+	}
+
+	for _, trackTeam := range changed {
+		updated, err := s.trackTeamRepo.UpdateTrackTeam(tx, trackId, trackTeam.TeamID, trackTeam)
 		if err != nil {
 			return nil, err
 		}
 
-		trackTeam.IsActive = converted
+		before := befores[trackTeam.TeamID]
+		auditId := AuditID(trackId, trackTeam.TeamID)
+		if err = s.audit.Record(ctx, tx, AuditEntityTrackTeam, auditId, AuditActionUpdate, &before, updated); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = s.checkMaxTeams(tx, track); err != nil {
+		return nil, err
+	}
+
+	if len(changed) > 0 {
+		if err = s.checkTeamCapacity(tx, trackId); err != nil {
+			return nil, err
+		}
 	}
 
-	return s.trackTeamRepo.UpdateTrackTeam(tx, trackId, teamId, trackTeam)
+	return trackTeams, nil
 }
 
-func (s *TrackService) DeleteRegisteredTeam(trackId int, teamId int) error {
-	tx, err := s.db.Begin()
+func (s *TrackService) DeleteRegisteredTeam(ctx context.Context, trackId int, teamId int) (err error) {
+	ctx, span := tracing.Start(ctx, "TrackService.DeleteRegisteredTeam")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return err
 	}
@@ -406,8 +955,285 @@ func (s *TrackService) DeleteRegisteredTeam(trackId int, teamId int) error {
 			return
 		}
 
-		_ = tx.Commit()
+		err = tx.Commit()
 	}()
 
-	return s.trackTeamRepo.DeleteTrackTeam(tx, trackId, teamId)
+	track, err := s.repo.LockTrackByID(tx, trackId)
+	if err != nil {
+		return err
+	}
+
+	trackTeam, err := s.trackTeamRepo.GetRoleByTrackIDAndTeamID(tx, trackId, teamId)
+	if err != nil {
+		return err
+	}
+
+	if err = s.trackTeamRepo.DeleteTrackTeam(tx, trackId, teamId); err != nil {
+		return err
+	}
+
+	auditId := AuditID(trackId, teamId)
+	if err = s.audit.Record(ctx, tx, AuditEntityTrackTeam, auditId, AuditActionDelete, trackTeam, nil); err != nil {
+		return err
+	}
+
+	if !holdsSlot(trackTeam.State) {
+		return nil
+	}
+
+	return s.promoteWaitlisted(ctx, tx, track)
+}
+
+// promoteWaitlisted moves the oldest waitlisted teams into pending while the
+// track has free slots. Organizers still approve them as usual.
+func (s *TrackService) promoteWaitlisted(ctx context.Context, tx *pg.Tx, track *models.Track) error {
+	limit := 0
+	if track.MaxTeams > 0 {
+		held, err := s.trackTeamRepo.CountHeldSlots(tx, track.ID)
+		if err != nil {
+			return err
+		}
+
+		if limit = track.MaxTeams - held; limit <= 0 {
+			return nil
+		}
+	}
+
+	waitlisted, err := s.trackTeamRepo.GetWaitlisted(tx, track.ID, limit)
+	if err != nil {
+		return err
+	}
+
+	for _, trackTeam := range waitlisted {
+		before := *trackTeam
+		trackTeam.State = models.TrackTeamPending
+
+		updated, err := s.trackTeamRepo.UpdateTrackTeam(tx, track.ID, trackTeam.TeamID, trackTeam)
+		if err != nil {
+			return err
+		}
+
+		auditId := AuditID(track.ID, trackTeam.TeamID)
+		if err = s.audit.Record(ctx, tx, AuditEntityTrackTeam, auditId, AuditActionUpdate, &before, updated); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *TrackService) checkMaxTeams(tx *pg.Tx, track *models.Track) error {
+	if track.MaxTeams == 0 {
+		return nil
+	}
+
+	held, err := s.trackTeamRepo.CountHeldSlots(tx, track.ID)
+	if err != nil {
+		return err
+	}
+
+	if held > track.MaxTeams {
+		return Conflict("track %d takes at most %d teams, %d registrations would hold a slot", track.ID, track.MaxTeams, held)
+	}
+
+	return nil
+}
+
+func checkRegistrationWindow(track *models.Track, now time.Time) error {
+	if !track.RegistrationOpensAt.IsZero() && now.Before(track.RegistrationOpensAt) {
+		return Conflict("registration for track %d opens at %s", track.ID, track.RegistrationOpensAt.Format(time.RFC3339))
+	}
+
+	if !track.RegistrationClosesAt.IsZero() && !now.Before(track.RegistrationClosesAt) {
+		return Conflict("registration for track %d closed at %s", track.ID, track.RegistrationClosesAt.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+func validateRegistrationWindow(track *models.Track) error {
+	if track.RegistrationOpensAt.IsZero() || track.RegistrationClosesAt.IsZero() ||
+		track.RegistrationClosesAt.After(track.RegistrationOpensAt) {
+		return nil
+	}
+
+	return &Error{
+		Kind:   ErrValidation,
+		Detail: "track cannot be applied",
+		Violations: []Violation{{
+			Field:   "registration_closes_at",
+			Rule:    RuleDateRange,
+			Message: "registration must close after it opens",
+		}},
+	}
+}
+
+// transitionTrackTeam moves a registration to the next state. Approval makes
+// the team active, every other decision deactivates it.
+func transitionTrackTeam(trackTeam *models.TrackTeam, state string, now time.Time) error {
+	if !slices.Contains(trackTeamTransitions[trackTeam.State], state) {
+		return &Error{
+			Kind:   ErrValidation,
+			Detail: "registration cannot be applied",
+			Violations: []Violation{{
+				Field:   fmt.Sprintf("team[%d].state", trackTeam.TeamID),
+				Rule:    RuleRegistrationState,
+				Message: fmt.Sprintf("team %d cannot move from %s to %s", trackTeam.TeamID, trackTeam.State, state),
+			}},
+		}
+	}
+
+	trackTeam.State = state
+	trackTeam.IsActive = state == models.TrackTeamApproved
+	trackTeam.DecidedAt = now
+
+	return nil
+}
+
+func holdsSlot(state string) bool {
+	return state == models.TrackTeamPending || state == models.TrackTeamApproved
+}
+
+func (s *TrackService) localizeTracks(tx *pg.Tx, tracks ...*models.Track) error {
+	trackIds := make([]int, 0, len(tracks))
+	for _, track := range tracks {
+		trackIds = append(trackIds, track.ID)
+	}
+
+	zones, err := s.repo.GetTimeZones(tx, trackIds)
+	if err != nil {
+		return err
+	}
+
+	for _, track := range tracks {
+		localizeTrack(track, zones[track.ID])
+	}
+
+	return nil
+}
+
+// checkTeamCapacity runs after the change is written, so the counts already
+// include the team or location being added.
+func (s *TrackService) checkTeamCapacity(tx *pg.Tx, trackId int) error {
+	loads, err := s.locationTrackRepo.GetLocationLoads(tx, trackId)
+	if err != nil {
+		return err
+	}
+
+	violations := make([]Violation, 0)
+	for _, load := range loads {
+		if load.Teams <= load.TeamCapacity {
+			continue
+		}
+
+		violations = append(violations, Violation{
+			Field:   fmt.Sprintf("location[%d].team_capacity", load.LocationID),
+			Rule:    RuleCapacity,
+			Message: fmt.Sprintf("location %d hosts %d teams, %d active", load.LocationID, load.TeamCapacity, load.Teams),
+		})
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+
+	return &Error{
+		Kind:       ErrConflict,
+		Detail:     "location team capacity exceeded",
+		Violations: violations,
+	}
+}
+
+func (s *TrackService) GetTrackStatuses(ctx context.Context, trackId int) (_ []*status_api.StatusResponse, err error) {
+	ctx, span := tracing.Start(ctx, "TrackService.GetTrackStatuses")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	if _, err = s.repo.GetTrackByID(tx, trackId); err != nil {
+		return nil, err
+	}
+
+	statuses, err := s.statusTrackRepo.GetStatusesByTrackID(tx, trackId)
+	if err != nil {
+		return nil, err
+	}
+
+	return MultipleStatusConvert(statuses), nil
+}
+
+func (s *TrackService) AddStatusToTrack(ctx context.Context, statusTrack *schemas.StatusTrack) (_ *models.StatusTrack, err error) {
+	ctx, span := tracing.Start(ctx, "TrackService.AddStatusToTrack")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	if _, err = s.repo.GetTrackByID(tx, statusTrack.TrackID); err != nil {
+		return nil, err
+	}
+
+	created, err := s.statusTrackRepo.Create(tx, &models.StatusTrack{
+		StatusID: statusTrack.StatusID,
+		TrackID:  statusTrack.TrackID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	auditId := AuditID(created.TrackID, created.StatusID)
+	if err = s.audit.Record(ctx, tx, AuditEntityStatusTrack, auditId, AuditActionCreate, nil, created); err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (s *TrackService) RemoveStatusFromTrack(ctx context.Context, statusTrack *schemas.StatusTrack) (err error) {
+	ctx, span := tracing.Start(ctx, "TrackService.RemoveStatusFromTrack")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	if err = s.statusTrackRepo.DeleteStatusTrack(tx, statusTrack.StatusID, statusTrack.TrackID); err != nil {
+		return err
+	}
+
+	auditId := AuditID(statusTrack.TrackID, statusTrack.StatusID)
+	return s.audit.Record(ctx, tx, AuditEntityStatusTrack, auditId, AuditActionDelete, statusTrack, nil)
 }
@@ -0,0 +1,310 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"event_service/internal/models"
+	"event_service/internal/schemas"
+	"event_service/pkg/teams"
+	"event_service/pkg/tracing"
+	"fmt"
+	"github.com/go-pg/pg/v10"
+)
+
+const (
+	RuleTeamSize     = "team_size"
+	RuleStudentsOnly = "students_only"
+	RuleOnePerEvent  = "one_per_event"
+	RuleTrackOverlap = "track_overlap"
+)
+
+func (s *TrackService) GetTrackEligibility(ctx context.Context, trackId int) (_ *models.TrackEligibility, err error) {
+	ctx, span := tracing.Start(ctx, "TrackService.GetTrackEligibility")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	if _, err = s.repo.GetTrackByID(tx, trackId); err != nil {
+		return nil, err
+	}
+
+	rules, err := s.eligibilityRepo.GetByTrackID(tx, trackId)
+	if errors.Is(err, pg.ErrNoRows) {
+		return &models.TrackEligibility{TrackID: trackId}, nil
+	}
+
+	return rules, err
+}
+
+func (s *TrackService) SetTrackEligibility(ctx context.Context, trackId int, eligibility schemas.TrackEligibility) (_ *models.TrackEligibility, err error) {
+	ctx, span := tracing.Start(ctx, "TrackService.SetTrackEligibility")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	if _, err = s.repo.GetTrackByID(tx, trackId); err != nil {
+		return nil, err
+	}
+
+	action := AuditActionUpdate
+	before, err := s.eligibilityRepo.GetByTrackID(tx, trackId)
+	if errors.Is(err, pg.ErrNoRows) {
+		action, before = AuditActionCreate, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.eligibilityRepo.Upsert(tx, &models.TrackEligibility{
+		TrackID:      trackId,
+		MinTeamSize:  eligibility.MinTeamSize,
+		MaxTeamSize:  eligibility.MaxTeamSize,
+		StudentsOnly: eligibility.StudentsOnly,
+		OnePerEvent:  eligibility.OnePerEvent,
+		NoOverlap:    eligibility.NoOverlap,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.audit.Record(ctx, tx, AuditEntityTrackEligibility, AuditID(trackId), action, before, updated); err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// CheckTrackEligibility evaluates every live registration of the track
+// against its current rules. Registrations are reported, not changed, so
+// organizers decide what to do with teams that no longer qualify. Teams are
+// fetched between the two reads so that no transaction waits on the user and
+// teams service.
+func (s *TrackService) CheckTrackEligibility(ctx context.Context, trackId int) (_ []*schemas.EligibilityReport, err error) {
+	ctx, span := tracing.Start(ctx, "TrackService.CheckTrackEligibility")
+	defer func() { tracing.End(span, err) }()
+
+	live, err := s.liveRegistrations(ctx, trackId)
+	if err != nil {
+		return nil, err
+	}
+
+	teamIds := make([]int, 0, len(live))
+	for _, trackTeam := range live {
+		teamIds = append(teamIds, trackTeam.TeamID)
+	}
+
+	found, err := s.teams.GetTeams(ctx, teamIds)
+	if err != nil {
+		return nil, referenceError(err, "team_id", RuleTeamExists, "")
+	}
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	rules, err := s.eligibilityRepo.GetByTrackID(tx, trackId)
+	if errors.Is(err, pg.ErrNoRows) {
+		rules = &models.TrackEligibility{TrackID: trackId}
+	} else if err != nil {
+		return nil, err
+	}
+
+	reports := make([]*schemas.EligibilityReport, 0, len(live))
+	for _, trackTeam := range live {
+		report := &schemas.EligibilityReport{
+			TeamID:  trackTeam.TeamID,
+			State:   trackTeam.State,
+			Reasons: make([]schemas.EligibilityReason, 0),
+		}
+
+		violations := []Violation{{
+			Field:   "team_id",
+			Rule:    RuleTeamExists,
+			Message: fmt.Sprintf("team %d does not exist", trackTeam.TeamID),
+		}}
+
+		if team, ok := found[trackTeam.TeamID]; ok {
+			if violations, err = s.eligibilityViolations(tx, rules, team); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, violation := range violations {
+			report.Reasons = append(report.Reasons, schemas.EligibilityReason{Rule: violation.Rule, Message: violation.Message})
+		}
+
+		report.Eligible = len(report.Reasons) == 0
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+// liveRegistrations lists the registrations of a track that are neither
+// rejected nor withdrawn.
+func (s *TrackService) liveRegistrations(ctx context.Context, trackId int) (_ []*models.TrackTeam, err error) {
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	if _, err = s.repo.GetTrackByID(tx, trackId); err != nil {
+		return nil, err
+	}
+
+	trackTeams, err := s.trackTeamRepo.GetTeamsByTrackID(tx, trackId, "")
+	if err != nil {
+		return nil, err
+	}
+
+	live := make([]*models.TrackTeam, 0, len(trackTeams))
+	for _, trackTeam := range trackTeams {
+		if trackTeam.State != models.TrackTeamRejected && trackTeam.State != models.TrackTeamWithdrawn {
+			live = append(live, trackTeam)
+		}
+	}
+
+	return live, nil
+}
+
+// checkEligibility rejects a registration that breaks any rule of the track,
+// listing every broken rule at once.
+func (s *TrackService) checkEligibility(tx *pg.Tx, trackId int, team *teams.Team) error {
+	rules, err := s.eligibilityRepo.GetByTrackID(tx, trackId)
+	if errors.Is(err, pg.ErrNoRows) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	violations, err := s.eligibilityViolations(tx, rules, team)
+	if err != nil {
+		return err
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+
+	return &Error{
+		Kind:       ErrValidation,
+		Detail:     fmt.Sprintf("team %d is not eligible for track %d", team.ID, trackId),
+		Violations: violations,
+	}
+}
+
+func (s *TrackService) eligibilityViolations(tx *pg.Tx, rules *models.TrackEligibility, team *teams.Team) ([]Violation, error) {
+	violations := make([]Violation, 0)
+
+	size := team.Size()
+	if rules.MinTeamSize > 0 && size < rules.MinTeamSize {
+		violations = append(violations, Violation{
+			Field:   "team_id",
+			Rule:    RuleTeamSize,
+			Message: fmt.Sprintf("team has %d members, at least %d are required", size, rules.MinTeamSize),
+		})
+	}
+
+	if rules.MaxTeamSize > 0 && size > rules.MaxTeamSize {
+		violations = append(violations, Violation{
+			Field:   "team_id",
+			Rule:    RuleTeamSize,
+			Message: fmt.Sprintf("team has %d members, at most %d are allowed", size, rules.MaxTeamSize),
+		})
+	}
+
+	if rules.StudentsOnly {
+		if len(team.Members) == 0 {
+			violations = append(violations, Violation{
+				Field:   "team_id",
+				Rule:    RuleStudentsOnly,
+				Message: "student status of team members is unknown",
+			})
+		}
+
+		for _, member := range team.Members {
+			if member.IsStudent {
+				continue
+			}
+
+			violations = append(violations, Violation{
+				Field:   "team_id",
+				Rule:    RuleStudentsOnly,
+				Message: fmt.Sprintf("member %d is not a student", member.ID),
+			})
+		}
+	}
+
+	if !rules.OnePerEvent && !rules.NoOverlap {
+		return violations, nil
+	}
+
+	registrations, err := s.trackTeamRepo.GetOtherRegistrations(tx, team.ID, rules.TrackID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, registration := range registrations {
+		if rules.OnePerEvent && registration.SameEvent {
+			violations = append(violations, Violation{
+				Field:   "team_id",
+				Rule:    RuleOnePerEvent,
+				Message: fmt.Sprintf("team is already registered for track %d of the same event", registration.TrackID),
+			})
+		}
+
+		if rules.NoOverlap && registration.Overlaps {
+			violations = append(violations, Violation{
+				Field:   "team_id",
+				Rule:    RuleTrackOverlap,
+				Message: fmt.Sprintf("dates overlap with track %d the team is registered for", registration.TrackID),
+			})
+		}
+	}
+
+	return violations, nil
+}
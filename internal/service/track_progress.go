@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"event_service/internal/models"
+	"event_service/internal/repositories"
+	"event_service/internal/schemas"
+	"event_service/pkg/tracing"
+	"event_service/pkg/utils"
+	"github.com/go-pg/pg/v10"
+	"time"
+)
+
+// GetTeamProgress shows a registered team every stage of the track with its
+// submission state and own deadlines. It is open to members of the team and
+// to holders of a track role, membership is asked from the user and teams
+// service once the read is over.
+func (s *TrackService) GetTeamProgress(ctx context.Context, trackId int, teamId int) (_ *schemas.TeamProgress, err error) {
+	ctx, span := tracing.Start(ctx, "TrackService.GetTeamProgress")
+	defer func() { tracing.End(span, err) }()
+
+	actorId, ok := utils.ActorFromContext(ctx)
+	if !ok {
+		return nil, Forbidden("team progress is open to authenticated users only")
+	}
+
+	progress, role, err := s.teamProgress(ctx, trackId, teamId)
+	if err != nil {
+		return nil, err
+	}
+
+	if role != nil {
+		return progress, nil
+	}
+
+	member, err := isTeamMember(ctx, s.teams, teamId, actorId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !member {
+		return nil, Forbidden("progress of team %d is open to its members and track organizers only", teamId)
+	}
+
+	return progress, nil
+}
+
+func (s *TrackService) teamProgress(ctx context.Context, trackId int, teamId int) (_ *schemas.TeamProgress, _ *models.TrackRole, err error) {
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	trackTeam, err := s.trackTeamRepo.GetByTrackIDAndTeamID(tx, trackId, teamId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	role, err := s.actorRole(ctx, tx, trackId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := s.trackTeamRepo.GetProgress(tx, trackId, teamId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	progress := &schemas.TeamProgress{
+		TrackID:     trackId,
+		TeamID:      trackTeam.TeamID,
+		TrackTeamID: trackTeam.ID,
+		State:       trackTeam.State,
+		IsActive:    trackTeam.IsActive,
+		Stages:      make([]schemas.StageProgress, 0, len(rows)),
+	}
+
+	now := time.Now()
+	for _, row := range rows {
+		progress.Stages = append(progress.Stages, stageProgress(row, role != nil && role.CanViewResults, now))
+	}
+
+	return progress, role, nil
+}
+
+// GetProgressMatrix is the organizer view of all registered teams against
+// all stages of the track, open to holders of a track role.
+func (s *TrackService) GetProgressMatrix(ctx context.Context, trackId int) (_ *schemas.ProgressMatrix, err error) {
+	ctx, span := tracing.Start(ctx, "TrackService.GetProgressMatrix")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	if _, err = s.repo.GetTrackByID(tx, trackId); err != nil {
+		return nil, err
+	}
+
+	role, err := s.actorRole(ctx, tx, trackId)
+	if err != nil {
+		return nil, err
+	}
+
+	if role == nil {
+		return nil, Forbidden("the progress matrix is open to track organizers only")
+	}
+
+	trackTeams, err := s.trackTeamRepo.GetTeamsByTrackID(tx, trackId, "")
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.trackTeamRepo.GetProgress(tx, trackId, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	matrix := &schemas.ProgressMatrix{
+		TrackID: trackId,
+		Stages:  make([]schemas.ProgressStage, 0),
+		Teams:   make([]*schemas.TeamProgress, 0, len(trackTeams)),
+	}
+
+	byTrackTeam := make(map[int]*schemas.TeamProgress, len(trackTeams))
+	for _, trackTeam := range trackTeams {
+		progress := &schemas.TeamProgress{
+			TrackID:     trackId,
+			TeamID:      trackTeam.TeamID,
+			TrackTeamID: trackTeam.ID,
+			State:       trackTeam.State,
+			IsActive:    trackTeam.IsActive,
+			Stages:      make([]schemas.StageProgress, 0),
+		}
+
+		byTrackTeam[trackTeam.ID] = progress
+		matrix.Teams = append(matrix.Teams, progress)
+	}
+
+	now := time.Now()
+	for _, row := range rows {
+		progress, ok := byTrackTeam[row.TrackTeamID]
+		if !ok {
+			continue
+		}
+
+		// Every team has one row per stage in stage order, so the rows of
+		// any single team give the columns.
+		if progress == matrix.Teams[0] {
+			matrix.Stages = append(matrix.Stages, schemas.ProgressStage{
+				TimelineID: row.TimelineID,
+				Title:      row.Title,
+				Order:      row.CountNum,
+			})
+		}
+
+		progress.Stages = append(progress.Stages, stageProgress(row, role.CanViewResults, now))
+	}
+
+	return matrix, nil
+}
+
+// actorRole returns the track role of the caller, nil when the caller is
+// anonymous or holds no role in the track.
+func (s *TrackService) actorRole(ctx context.Context, tx *pg.Tx, trackId int) (*models.TrackRole, error) {
+	actorId, ok := utils.ActorFromContext(ctx)
+	if !ok {
+		return nil, nil
+	}
+
+	role, err := s.trackRoleRepo.GetRole(tx, trackId, actorId)
+	if errors.Is(err, pg.ErrNoRows) {
+		return nil, nil
+	}
+
+	return role, err
+}
+
+func stageProgress(row *repositories.ProgressRow, canViewResults bool, now time.Time) schemas.StageProgress {
+	stage := schemas.StageProgress{
+		TimelineID: row.TimelineID,
+		Title:      row.Title,
+		Order:      row.CountNum,
+		Status:     row.TimelineStatus,
+		Deadline:   row.Deadline,
+		IsBlocking: row.IsBlocking,
+		Submission: schemas.SubmissionMissing,
+		IsLate:     row.IsLate,
+	}
+
+	if !row.OverrideDeadline.IsZero() {
+		stage.Deadline = row.OverrideDeadline
+		stage.IsOverridden = true
+	}
+
+	if row.OverrideBlocking != nil {
+		stage.IsBlocking = *row.OverrideBlocking
+		stage.IsOverridden = true
+	}
+
+	if !stage.Deadline.IsZero() && stage.Deadline.After(now) {
+		stage.RemainingSeconds = int64(stage.Deadline.Sub(now) / time.Second)
+	}
+
+	if !row.IsSubmitted {
+		return stage
+	}
+
+	stage.Submission = schemas.SubmissionSubmitted
+	if !row.SubmittedAt.IsZero() {
+		submittedAt := row.SubmittedAt
+		stage.SubmittedAt = &submittedAt
+	}
+
+	if row.IsScored {
+		stage.Submission = schemas.SubmissionScored
+
+		if canViewResults || row.TimelineStatus == "completed" {
+			score := row.CountedValue * (100 - row.PenaltyPercent) / 100
+			stage.Score = &score
+		}
+	}
+
+	return stage
+}
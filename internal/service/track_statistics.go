@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"event_service/internal/models"
+	"event_service/internal/repositories"
+	"event_service/internal/schemas"
+	"event_service/pkg/tracing"
+	"math"
+	"slices"
+	"time"
+)
+
+const histogramBuckets = 10
+
+// GetTrackStatistics reports registrations, submissions, scores and the
+// elimination funnel of a track to callers whose track role may view
+// statistics. Results are served from the cache until a submission of the
+// track changes.
+func (s *TrackService) GetTrackStatistics(ctx context.Context, trackId int) (_ *schemas.TrackStatistics, err error) {
+	ctx, span := tracing.Start(ctx, "TrackService.GetTrackStatistics")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
+
+	if _, err = s.repo.GetTrackByID(tx, trackId); err != nil {
+		return nil, err
+	}
+
+	role, err := s.actorRole(ctx, tx, trackId)
+	if err != nil {
+		return nil, err
+	}
+
+	if role == nil || !role.CanViewStatistics {
+		return nil, Forbidden("track statistics need the can_view_statistics role")
+	}
+
+	stats, generation := s.stats.Get(trackId)
+	if stats != nil {
+		return stats, nil
+	}
+
+	trackTeams, err := s.trackTeamRepo.GetTeamsByTrackID(tx, trackId, "")
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.trackTeamRepo.GetProgress(tx, trackId, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	stats = trackStatistics(trackId, trackTeams, rows, time.Now())
+	s.stats.Put(trackId, generation, stats)
+
+	return stats, nil
+}
+
+func trackStatistics(trackId int, trackTeams []*models.TrackTeam, rows []*repositories.ProgressRow, now time.Time) *schemas.TrackStatistics {
+	stats := &schemas.TrackStatistics{
+		TrackID:    trackId,
+		ComputedAt: now,
+		Teams: schemas.TeamCounts{
+			Registered: len(trackTeams),
+			ByState:    make(map[string]int),
+		},
+		Stages: make([]schemas.StageStatistics, 0),
+		Funnel: make([]schemas.FunnelStep, 0),
+	}
+
+	for _, trackTeam := range trackTeams {
+		stats.Teams.ByState[trackTeam.State]++
+
+		if trackTeam.State == models.TrackTeamApproved && trackTeam.IsActive {
+			stats.Teams.Active++
+		}
+	}
+
+	stages := make(map[int]*schemas.StageStatistics)
+	scores := make(map[int][]float64)
+	timeToSubmit := make(map[int][]float64)
+	funnel := make(map[int]*schemas.FunnelStep)
+
+	order := make([]int, 0)
+	alive := make(map[int]bool)
+
+	// Rows come team by team in stage order, so a team drops out of the
+	// funnel at its first missed blocking stage.
+	for _, row := range rows {
+		if row.State != models.TrackTeamApproved {
+			continue
+		}
+
+		stage, ok := stages[row.TimelineID]
+		if !ok {
+			stage = &schemas.StageStatistics{
+				TimelineID: row.TimelineID,
+				Title:      row.Title,
+				Order:      row.CountNum,
+				IsBlocking: row.IsBlocking,
+			}
+
+			stages[row.TimelineID] = stage
+			order = append(order, row.TimelineID)
+		}
+
+		if _, ok := alive[row.TrackTeamID]; !ok {
+			alive[row.TrackTeamID] = true
+		}
+
+		deadline, isBlocking := row.Deadline, row.IsBlocking
+		if !row.OverrideDeadline.IsZero() {
+			deadline = row.OverrideDeadline
+		}
+
+		if row.OverrideBlocking != nil {
+			isBlocking = *row.OverrideBlocking
+		}
+
+		stage.Teams++
+		if row.IsSubmitted {
+			stage.Submitted++
+
+			if row.IsLate {
+				stage.Late++
+			}
+
+			if row.IsScored {
+				scores[row.TimelineID] = append(scores[row.TimelineID], float64(row.CountedValue*(100-row.PenaltyPercent)/100))
+			}
+
+			if !row.SubmittedAt.IsZero() && !deadline.IsZero() {
+				timeToSubmit[row.TimelineID] = append(timeToSubmit[row.TimelineID], deadline.Sub(row.SubmittedAt).Minutes())
+			}
+		}
+
+		if !isBlocking {
+			continue
+		}
+
+		step, ok := funnel[row.TimelineID]
+		if !ok {
+			step = &schemas.FunnelStep{TimelineID: row.TimelineID, Title: row.Title}
+			funnel[row.TimelineID] = step
+		}
+
+		if !alive[row.TrackTeamID] {
+			continue
+		}
+
+		step.Entered++
+		switch {
+		case row.IsSubmitted:
+			step.Passed++
+		case !deadline.IsZero() && deadline.Before(now):
+			step.Eliminated++
+			alive[row.TrackTeamID] = false
+		default:
+			step.Pending++
+			alive[row.TrackTeamID] = false
+		}
+	}
+
+	for _, timelineId := range order {
+		stage := stages[timelineId]
+		if stage.Teams > 0 {
+			stage.SubmissionRate = round(float64(stage.Submitted) / float64(stage.Teams))
+		}
+
+		stage.Scores = distribution(scores[timelineId])
+		stage.TimeToSubmit = distribution(timeToSubmit[timelineId])
+		stats.Stages = append(stats.Stages, *stage)
+
+		if step, ok := funnel[timelineId]; ok {
+			stats.Funnel = append(stats.Funnel, *step)
+		}
+	}
+
+	return stats
+}
+
+func distribution(values []float64) schemas.Distribution {
+	result := schemas.Distribution{
+		Count:     len(values),
+		Histogram: make([]schemas.HistogramBucket, 0),
+	}
+
+	if len(values) == 0 {
+		return result
+	}
+
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+
+	sum := 0.0
+	for _, value := range sorted {
+		sum += value
+	}
+
+	result.Min = round(sorted[0])
+	result.Max = round(sorted[len(sorted)-1])
+	result.Mean = round(sum / float64(len(sorted)))
+	result.Median = round(percentile(sorted, 50))
+	result.P25 = round(percentile(sorted, 25))
+	result.P75 = round(percentile(sorted, 75))
+	result.P90 = round(percentile(sorted, 90))
+	result.Histogram = histogram(sorted)
+
+	return result
+}
+
+// percentile interpolates linearly between the closest ranks of sorted.
+func percentile(sorted []float64, p float64) float64 {
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
+
+// histogram splits the range of sorted into equal buckets, the last one
+// includes the maximum.
+func histogram(sorted []float64) []schemas.HistogramBucket {
+	low, high := sorted[0], sorted[len(sorted)-1]
+	if low == high {
+		return []schemas.HistogramBucket{{From: round(low), To: round(high), Count: len(sorted)}}
+	}
+
+	width := (high - low) / histogramBuckets
+	buckets := make([]schemas.HistogramBucket, histogramBuckets)
+	for i := range buckets {
+		buckets[i].From = round(low + width*float64(i))
+		buckets[i].To = round(low + width*float64(i+1))
+	}
+
+	for _, value := range sorted {
+		i := min(int((value-low)/width), histogramBuckets-1)
+		buckets[i].Count++
+	}
+
+	return buckets
+}
+
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -1,10 +1,11 @@
 package service
 
 import (
+	"context"
 	"event_service/internal/models"
 	"event_service/internal/repositories"
 	"event_service/internal/schemas"
-	"fmt"
+	"event_service/pkg/tracing"
 	"github.com/go-pg/pg/v10"
 )
 
@@ -15,21 +16,28 @@ type TrackWinnerService struct {
 	trackRepo            *repositories.TrackRepository
 	timelineRepo         *repositories.TimelineRepository
 
+	audit *AuditService
+
 	db *pg.DB
 }
 
-func NewTrackWinnerService(repo *repositories.TrackWinnerRepository, trackRepo *repositories.TrackRepository,
-	timelineRepo *repositories.TimelineRepository, db *pg.DB) *TrackWinnerService {
+func NewTrackWinnerService(repo *repositories.TrackWinnerRepository, teamActionStatusRepo *repositories.TeamActionStatusRepository,
+	trackRepo *repositories.TrackRepository, timelineRepo *repositories.TimelineRepository, audit *AuditService, db *pg.DB) *TrackWinnerService {
 	return &TrackWinnerService{
-		repo:         repo,
-		trackRepo:    trackRepo,
-		timelineRepo: timelineRepo,
-		db:           db,
+		repo:                 repo,
+		teamActionStatusRepo: teamActionStatusRepo,
+		trackRepo:            trackRepo,
+		timelineRepo:         timelineRepo,
+		audit:                audit,
+		db:                   db,
 	}
 }
 
-func (s *TrackWinnerService) GetWinnersOfTrack(trackId int) (_ []*models.TrackWinner, err error) {
-	tx, err := s.db.Begin()
+func (s *TrackWinnerService) GetWinnersOfTrack(ctx context.Context, trackId int) (_ []*models.TrackWinner, err error) {
+	ctx, span := tracing.Start(ctx, "TrackWinnerService.GetWinnersOfTrack")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -46,8 +54,11 @@ func (s *TrackWinnerService) GetWinnersOfTrack(trackId int) (_ []*models.TrackWi
 	return s.repo.GetAllWinnersByTrackID(tx, trackId)
 }
 
-func (s *TrackWinnerService) GetWinnerById(trackId int, trackTeamId int) (_ *models.TrackWinner, err error) {
-	tx, err := s.db.Begin()
+func (s *TrackWinnerService) GetWinnerById(ctx context.Context, trackId int, trackTeamId int) (_ *models.TrackWinner, err error) {
+	ctx, span := tracing.Start(ctx, "TrackWinnerService.GetWinnerById")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -64,8 +75,11 @@ func (s *TrackWinnerService) GetWinnerById(trackId int, trackTeamId int) (_ *mod
 	return s.repo.GetTrackWinnerByTrackIDAndTeamID(tx, trackId, trackTeamId)
 }
 
-func (s *TrackWinnerService) CreateWinnerOfTrack(trackWinner *schemas.TrackWinner) (_ *models.TrackWinner, err error) {
-	tx, err := s.db.Begin()
+func (s *TrackWinnerService) CreateWinnerOfTrack(ctx context.Context, trackWinner *schemas.TrackWinner) (_ *models.TrackWinner, err error) {
+	ctx, span := tracing.Start(ctx, "TrackWinnerService.CreateWinnerOfTrack")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -85,7 +99,7 @@ func (s *TrackWinnerService) CreateWinnerOfTrack(trackWinner *schemas.TrackWinne
 	}
 
 	if track.IsScoreBased {
-		return nil, fmt.Errorf("manually creation of winners of track forbidden")
+		return nil, Forbidden("manually creation of winners of track forbidden")
 	}
 
 	model := &models.TrackWinner{
@@ -95,11 +109,24 @@ func (s *TrackWinnerService) CreateWinnerOfTrack(trackWinner *schemas.TrackWinne
 		IsAwardee:   trackWinner.IsAwardee,
 	}
 
-	return s.repo.Create(tx, model)
+	created, err := s.repo.Create(tx, model)
+	if err != nil {
+		return nil, err
+	}
+
+	auditId := AuditID(created.TrackID, created.TrackTeamID)
+	if err = s.audit.Record(ctx, tx, AuditEntityTrackWinner, auditId, AuditActionCreate, nil, created); err != nil {
+		return nil, err
+	}
+
+	return created, nil
 }
 
-func (s *TrackWinnerService) CalculateRating(trackId int, limit int, offset int) ([]*repositories.AggregateResult, error) {
-	tx, err := s.db.Begin()
+func (s *TrackWinnerService) CalculateRating(ctx context.Context, trackId int, limit int, offset int) (_ []*repositories.AggregateResult, err error) {
+	ctx, span := tracing.Start(ctx, "TrackWinnerService.CalculateRating")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -116,8 +143,11 @@ func (s *TrackWinnerService) CalculateRating(trackId int, limit int, offset int)
 	return s.teamActionStatusRepo.AggregateResults(tx, trackId, limit, offset)
 }
 
-func (s *TrackWinnerService) SetResultsOfTrack(trackId int, threshold int, limit int) ([]*models.TrackWinner, error) {
-	tx, err := s.db.Begin()
+func (s *TrackWinnerService) SetResultsOfTrack(ctx context.Context, trackId int, threshold int, limit int) (_ []*models.TrackWinner, err error) {
+	ctx, span := tracing.Start(ctx, "TrackWinnerService.SetResultsOfTrack")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.db.BeginContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -137,10 +167,10 @@ func (s *TrackWinnerService) SetResultsOfTrack(trackId int, threshold int, limit
 	}
 
 	if !track.IsScoreBased || track.Status != "completed" {
-		return nil, fmt.Errorf("results can be generated only for completed tracks based on score")
+		return nil, Conflict("results can be generated only for completed tracks based on score")
 	}
 
-	aggregated, err := s.CalculateRating(trackId, limit, 0)
+	aggregated, err := s.CalculateRating(ctx, trackId, limit, 0)
 	if err != nil {
 		return nil, err
 	}
@@ -160,6 +190,11 @@ func (s *TrackWinnerService) SetResultsOfTrack(trackId int, threshold int, limit
 			return nil, err
 		}
 
+		auditId := AuditID(response.TrackID, response.TrackTeamID)
+		if err := s.audit.Record(ctx, tx, AuditEntityTrackWinner, auditId, AuditActionCreate, nil, response); err != nil {
+			return nil, err
+		}
+
 		result = append(result, response)
 	}
 
@@ -0,0 +1,11 @@
+DROP TRIGGER IF EXISTS trigger_update_timeline_updated_at ON timeline;
+
+ALTER TABLE timeline
+DROP COLUMN updated_at,
+DROP COLUMN created_at;
+
+DROP TRIGGER IF EXISTS trigger_update_track_updated_at ON track;
+
+ALTER TABLE track
+DROP COLUMN updated_at,
+DROP COLUMN created_at;
@@ -0,0 +1,20 @@
+ALTER TABLE track
+ADD created_at timestamptz NOT NULL DEFAULT now(),
+ADD updated_at timestamptz NOT NULL DEFAULT now();
+
+CREATE TRIGGER trigger_update_track_updated_at
+    BEFORE UPDATE ON track
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TABLE timeline
+ADD created_at timestamptz NOT NULL DEFAULT now(),
+ADD updated_at timestamptz NOT NULL DEFAULT now();
+
+CREATE TRIGGER trigger_update_timeline_updated_at
+    BEFORE UPDATE ON timeline
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TYPE event_status_type ADD VALUE IF NOT EXISTS 'cancelled';
+ALTER TYPE track_status_type ADD VALUE IF NOT EXISTS 'cancelled';
@@ -0,0 +1,16 @@
+DROP INDEX IF EXISTS idx_location_deleted_at;
+DROP INDEX IF EXISTS idx_timeline_deleted_at;
+DROP INDEX IF EXISTS idx_track_deleted_at;
+DROP INDEX IF EXISTS idx_event_deleted_at;
+
+ALTER TABLE location
+DROP COLUMN deleted_at;
+
+ALTER TABLE timeline
+DROP COLUMN deleted_at;
+
+ALTER TABLE track
+DROP COLUMN deleted_at;
+
+ALTER TABLE event
+DROP COLUMN deleted_at;
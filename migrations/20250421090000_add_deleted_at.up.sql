@@ -0,0 +1,16 @@
+ALTER TABLE event
+ADD deleted_at timestamptz;
+
+ALTER TABLE track
+ADD deleted_at timestamptz;
+
+ALTER TABLE timeline
+ADD deleted_at timestamptz;
+
+ALTER TABLE location
+ADD deleted_at timestamptz;
+
+CREATE INDEX idx_event_deleted_at ON event (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_track_deleted_at ON track (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_timeline_deleted_at ON timeline (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_location_deleted_at ON location (deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,17 @@
+CREATE TABLE audit_log
+(
+    id         BIGSERIAL PRIMARY KEY,
+    entity     VARCHAR(64) NOT NULL,
+    entity_id  VARCHAR(64) NOT NULL,
+    action     VARCHAR(16) NOT NULL,
+    actor_id   INT,
+    request_id VARCHAR(255),
+    before     JSONB,
+    after      JSONB,
+    diff       JSONB,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_audit_log_entity ON audit_log (entity, entity_id);
+CREATE INDEX idx_audit_log_actor ON audit_log (actor_id);
+CREATE INDEX idx_audit_log_created_at ON audit_log (created_at);
@@ -0,0 +1,8 @@
+ALTER TABLE timeline
+DROP COLUMN version;
+
+ALTER TABLE track
+DROP COLUMN version;
+
+ALTER TABLE event
+DROP COLUMN version;
@@ -0,0 +1,8 @@
+ALTER TABLE event
+ADD version INT NOT NULL DEFAULT 1;
+
+ALTER TABLE track
+ADD version INT NOT NULL DEFAULT 1;
+
+ALTER TABLE timeline
+ADD version INT NOT NULL DEFAULT 1;
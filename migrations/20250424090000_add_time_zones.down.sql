@@ -0,0 +1,18 @@
+ALTER TABLE location
+DROP COLUMN time_zone;
+
+ALTER TABLE timeline
+ALTER COLUMN deadline TYPE TIMESTAMP USING deadline AT TIME ZONE 'UTC';
+
+ALTER TABLE event_prize
+ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE 'UTC',
+ALTER COLUMN updated_at TYPE TIMESTAMP USING updated_at AT TIME ZONE 'UTC',
+ALTER COLUMN created_at SET DEFAULT CURRENT_TIMESTAMP,
+ALTER COLUMN updated_at SET DEFAULT CURRENT_TIMESTAMP;
+
+ALTER TABLE event
+DROP COLUMN time_zone,
+ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE 'UTC',
+ALTER COLUMN updated_at TYPE TIMESTAMP USING updated_at AT TIME ZONE 'UTC',
+ALTER COLUMN created_at SET DEFAULT CURRENT_TIMESTAMP,
+ALTER COLUMN updated_at SET DEFAULT CURRENT_TIMESTAMP;
@@ -0,0 +1,19 @@
+-- Naive columns were written by the service in UTC.
+ALTER TABLE event
+ALTER COLUMN created_at TYPE timestamptz USING created_at AT TIME ZONE 'UTC',
+ALTER COLUMN updated_at TYPE timestamptz USING updated_at AT TIME ZONE 'UTC',
+ALTER COLUMN created_at SET DEFAULT now(),
+ALTER COLUMN updated_at SET DEFAULT now(),
+ADD time_zone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+
+ALTER TABLE event_prize
+ALTER COLUMN created_at TYPE timestamptz USING created_at AT TIME ZONE 'UTC',
+ALTER COLUMN updated_at TYPE timestamptz USING updated_at AT TIME ZONE 'UTC',
+ALTER COLUMN created_at SET DEFAULT now(),
+ALTER COLUMN updated_at SET DEFAULT now();
+
+ALTER TABLE timeline
+ALTER COLUMN deadline TYPE timestamptz USING deadline AT TIME ZONE 'UTC';
+
+ALTER TABLE location
+ADD time_zone VARCHAR(64);
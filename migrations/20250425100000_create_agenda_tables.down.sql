@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS session_speaker;
+DROP TABLE IF EXISTS session;
+DROP TABLE IF EXISTS speaker;
@@ -0,0 +1,36 @@
+CREATE TABLE speaker
+(
+    id         SERIAL PRIMARY KEY,
+    name       VARCHAR(255) NOT NULL,
+    bio        TEXT,
+    company    VARCHAR(255),
+    user_id    INT,
+    created_at timestamptz  NOT NULL DEFAULT now(),
+    updated_at timestamptz  NOT NULL DEFAULT now()
+);
+
+CREATE TABLE session
+(
+    id          SERIAL PRIMARY KEY,
+    title       VARCHAR(255) NOT NULL,
+    description TEXT,
+    kind        VARCHAR(32)  NOT NULL DEFAULT 'other',
+    date_start  timestamptz  NOT NULL,
+    date_end    timestamptz  NOT NULL,
+    event_id    INT          NOT NULL REFERENCES event (id),
+    track_id    INT REFERENCES track (id),
+    location_id INT REFERENCES location (id),
+    created_at  timestamptz  NOT NULL DEFAULT now(),
+    updated_at  timestamptz  NOT NULL DEFAULT now(),
+    CHECK (date_end > date_start)
+);
+
+CREATE INDEX idx_session_event ON session (event_id, date_start);
+CREATE INDEX idx_session_location ON session (location_id, date_start, date_end);
+
+CREATE TABLE session_speaker
+(
+    session_id INT NOT NULL REFERENCES session (id) ON DELETE CASCADE,
+    speaker_id INT NOT NULL REFERENCES speaker (id),
+    PRIMARY KEY (session_id, speaker_id)
+);
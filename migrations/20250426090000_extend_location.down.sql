@@ -0,0 +1,12 @@
+ALTER TABLE session
+DROP COLUMN attendees;
+
+ALTER TABLE location
+DROP COLUMN parent_id,
+DROP COLUMN stream_url,
+DROP COLUMN type,
+DROP COLUMN team_capacity,
+DROP COLUMN capacity,
+DROP COLUMN longitude,
+DROP COLUMN latitude,
+DROP COLUMN address;
@@ -0,0 +1,20 @@
+ALTER TABLE location
+ADD address       TEXT,
+ADD latitude      DOUBLE PRECISION,
+ADD longitude     DOUBLE PRECISION,
+ADD capacity      INT,
+ADD team_capacity INT,
+ADD type          VARCHAR(16) NOT NULL DEFAULT 'physical',
+ADD stream_url    TEXT,
+ADD parent_id     INT REFERENCES location (id),
+ADD CONSTRAINT location_type_check CHECK (type IN ('physical', 'online', 'hybrid')),
+ADD CONSTRAINT location_coordinates_check CHECK ((latitude IS NULL) = (longitude IS NULL)
+    AND latitude BETWEEN -90 AND 90 AND longitude BETWEEN -180 AND 180),
+ADD CONSTRAINT location_capacity_check CHECK (capacity >= 0 AND team_capacity >= 0),
+ADD CONSTRAINT location_parent_check CHECK (parent_id <> id);
+
+CREATE INDEX idx_location_coordinates ON location (latitude, longitude);
+CREATE INDEX idx_location_parent ON location (parent_id);
+
+ALTER TABLE session
+ADD attendees INT CHECK (attendees >= 0);
@@ -0,0 +1,33 @@
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+CREATE TABLE location_booking
+(
+    id          SERIAL PRIMARY KEY,
+    location_id INT          NOT NULL REFERENCES location (id) ON DELETE CASCADE,
+    event_id    INT REFERENCES event (id) ON DELETE CASCADE,
+    track_id    INT REFERENCES track (id) ON DELETE CASCADE,
+    source      VARCHAR(16)  NOT NULL DEFAULT 'manual',
+    title       VARCHAR(255) NOT NULL,
+    date_start  timestamptz  NOT NULL,
+    date_end    timestamptz  NOT NULL,
+    created_at  timestamptz  NOT NULL DEFAULT now(),
+    CHECK (date_end > date_start),
+    CHECK (source IN ('manual', 'track')),
+    CHECK (source = 'manual' OR track_id IS NOT NULL),
+    CONSTRAINT location_booking_no_overlap
+        EXCLUDE USING gist (location_id WITH =, tstzrange(date_start, date_end) WITH &&)
+);
+
+CREATE INDEX idx_location_booking_track ON location_booking (track_id);
+CREATE INDEX idx_location_booking_event ON location_booking (event_id);
+
+-- Existing rooms that are already double-booked keep the first track only.
+-- Tracks with an empty or inverted date hold no time and get no booking.
+INSERT INTO location_booking (location_id, event_id, track_id, source, title, date_start, date_end)
+SELECT lt.location_id, t.event_id, t.id, 'track', t.title, d.date_start, d.date_end
+FROM location_track lt
+         JOIN track t ON t.id = lt.track_id AND t.deleted_at IS NULL
+         JOIN date d ON d.id = t.date_id
+WHERE d.date_end > d.date_start
+ORDER BY t.id
+ON CONFLICT DO NOTHING;
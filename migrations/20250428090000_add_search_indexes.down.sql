@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_timeline_search;
+DROP INDEX IF EXISTS idx_track_search;
+DROP INDEX IF EXISTS idx_event_search;
+
+DROP FUNCTION IF EXISTS search_document(TEXT, TEXT);
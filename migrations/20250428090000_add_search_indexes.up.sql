@@ -0,0 +1,16 @@
+CREATE OR REPLACE FUNCTION search_document(title TEXT, description TEXT)
+    RETURNS tsvector
+    LANGUAGE sql
+    IMMUTABLE
+    PARALLEL SAFE
+AS
+$$
+SELECT setweight(to_tsvector('russian', coalesce(title, '')), 'A') ||
+       setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+       setweight(to_tsvector('russian', coalesce(description, '')), 'B') ||
+       setweight(to_tsvector('english', coalesce(description, '')), 'B')
+$$;
+
+CREATE INDEX idx_event_search ON event USING gin (search_document(title, description));
+CREATE INDEX idx_track_search ON track USING gin (search_document(title, description));
+CREATE INDEX idx_timeline_search ON timeline USING gin (search_document(title, description));
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS status_track;
+DROP TABLE IF EXISTS status_event;
@@ -0,0 +1,19 @@
+CREATE TABLE status_event
+(
+    status_id  INT         NOT NULL REFERENCES status (id) ON DELETE CASCADE,
+    event_id   INT         NOT NULL REFERENCES event (id) ON DELETE CASCADE,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (status_id, event_id)
+);
+
+CREATE INDEX idx_status_event_event ON status_event (event_id);
+
+CREATE TABLE status_track
+(
+    status_id  INT         NOT NULL REFERENCES status (id) ON DELETE CASCADE,
+    track_id   INT         NOT NULL REFERENCES track (id) ON DELETE CASCADE,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (status_id, track_id)
+);
+
+CREATE INDEX idx_status_track_track ON status_track (track_id);
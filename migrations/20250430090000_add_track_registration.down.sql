@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_track_team_waitlist;
+DROP INDEX IF EXISTS idx_track_team_track_team;
+
+ALTER TABLE track_team
+DROP COLUMN decided_at,
+DROP COLUMN created_at,
+DROP COLUMN state;
+
+ALTER TABLE track
+DROP COLUMN max_teams,
+DROP COLUMN registration_closes_at,
+DROP COLUMN registration_opens_at;
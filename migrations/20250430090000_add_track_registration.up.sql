@@ -0,0 +1,53 @@
+ALTER TABLE track
+ADD registration_opens_at  TIMESTAMP WITH TIME ZONE,
+ADD registration_closes_at TIMESTAMP WITH TIME ZONE,
+ADD max_teams              INT,
+ADD CONSTRAINT track_registration_window_check CHECK (registration_opens_at < registration_closes_at),
+ADD CONSTRAINT track_max_teams_check CHECK (max_teams > 0);
+
+ALTER TABLE track_team
+ADD state      VARCHAR(16)              NOT NULL DEFAULT 'approved',
+ADD created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+ADD decided_at TIMESTAMP WITH TIME ZONE,
+ADD CONSTRAINT track_team_state_check CHECK (state IN ('pending', 'approved', 'rejected', 'withdrawn', 'waitlisted'));
+
+ALTER TABLE track_team
+ALTER COLUMN state SET DEFAULT 'pending';
+
+-- Keep the oldest row of every duplicated registration and move whatever
+-- references the others onto it before adding the unique index.
+CREATE TEMPORARY TABLE track_team_duplicate AS
+SELECT id, min(id) OVER (PARTITION BY track_id, team_id) AS keep_id
+FROM track_team;
+
+DELETE FROM track_team_duplicate WHERE id = keep_id;
+
+-- A duplicate that has a status for a timeline the kept row lacks hands it
+-- over, the oldest duplicate winning when several have one.
+UPDATE team_action_status s
+SET track_team_id = m.keep_id
+FROM (SELECT DISTINCT ON (d.keep_id, t.timeline_id) t.track_team_id, t.timeline_id, d.keep_id
+      FROM team_action_status t
+               JOIN track_team_duplicate d ON d.id = t.track_team_id
+      WHERE NOT EXISTS (SELECT 1
+                        FROM team_action_status k
+                        WHERE k.track_team_id = d.keep_id
+                          AND k.timeline_id = t.timeline_id)
+      ORDER BY d.keep_id, t.timeline_id, t.track_team_id) m
+WHERE s.track_team_id = m.track_team_id
+  AND s.timeline_id = m.timeline_id;
+
+-- Statuses that lost to another row go with their registration.
+DELETE FROM team_action_status s USING track_team_duplicate d WHERE s.track_team_id = d.id;
+
+UPDATE track_winner w
+SET track_team_id = d.keep_id
+FROM track_team_duplicate d
+WHERE w.track_team_id = d.id;
+
+DELETE FROM track_team t USING track_team_duplicate d WHERE t.id = d.id;
+
+DROP TABLE track_team_duplicate;
+
+CREATE UNIQUE INDEX idx_track_team_track_team ON track_team (track_id, team_id);
+CREATE INDEX idx_track_team_waitlist ON track_team (track_id, created_at) WHERE state = 'waitlisted';
@@ -0,0 +1,11 @@
+CREATE TABLE track_eligibility
+(
+    track_id      INT PRIMARY KEY REFERENCES track (id) ON DELETE CASCADE,
+    min_team_size INT CHECK (min_team_size > 0),
+    max_team_size INT CHECK (max_team_size > 0),
+    students_only BOOLEAN     NOT NULL DEFAULT FALSE,
+    one_per_event BOOLEAN     NOT NULL DEFAULT FALSE,
+    no_overlap    BOOLEAN     NOT NULL DEFAULT FALSE,
+    updated_at    timestamptz NOT NULL DEFAULT now(),
+    CHECK (min_team_size <= max_team_size)
+);
@@ -0,0 +1,17 @@
+CREATE TABLE submission_file
+(
+    id            SERIAL PRIMARY KEY,
+    track_team_id INT          NOT NULL,
+    timeline_id   INT          NOT NULL,
+    storage_key   TEXT         NOT NULL UNIQUE,
+    file_name     VARCHAR(255) NOT NULL,
+    content_type  VARCHAR(255) NOT NULL,
+    size          BIGINT       NOT NULL CHECK (size >= 0),
+    sha256        CHAR(64)     NOT NULL,
+    md5           CHAR(32)     NOT NULL,
+    uploaded_by   INT,
+    created_at    timestamptz  NOT NULL DEFAULT now(),
+    FOREIGN KEY (track_team_id, timeline_id) REFERENCES team_action_status (track_team_id, timeline_id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_submission_file_action ON submission_file (track_team_id, timeline_id);
@@ -0,0 +1,4 @@
+ALTER TABLE timeline
+    DROP COLUMN IF EXISTS counted_revision;
+
+DROP TABLE IF EXISTS team_action_status_revision;
@@ -0,0 +1,23 @@
+CREATE TABLE team_action_status_revision
+(
+    id              SERIAL PRIMARY KEY,
+    track_team_id   INT         NOT NULL,
+    timeline_id     INT         NOT NULL,
+    revision        INT         NOT NULL CHECK (revision > 0),
+    result_value    INT,
+    resolution_link TEXT,
+    completed_at    timestamptz,
+    notes           TEXT,
+    actor_id        INT,
+    created_at      timestamptz NOT NULL DEFAULT now(),
+    FOREIGN KEY (track_team_id, timeline_id) REFERENCES team_action_status (track_team_id, timeline_id) ON DELETE CASCADE,
+    UNIQUE (track_team_id, timeline_id, revision)
+);
+
+INSERT INTO team_action_status_revision (track_team_id, timeline_id, revision, result_value, resolution_link, completed_at, notes)
+SELECT track_team_id, timeline_id, 1, result_value, resolution_link, completed_at, notes
+FROM team_action_status;
+
+ALTER TABLE timeline
+    ADD COLUMN counted_revision VARCHAR(8) NOT NULL DEFAULT 'last'
+        CHECK (counted_revision IN ('first', 'last', 'best'));
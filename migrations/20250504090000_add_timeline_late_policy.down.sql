@@ -0,0 +1,11 @@
+ALTER TABLE team_action_status
+DROP COLUMN penalty_percent,
+DROP COLUMN late_minutes,
+DROP COLUMN is_late,
+DROP COLUMN submitted_at;
+
+ALTER TABLE timeline
+DROP COLUMN late_step_minutes,
+DROP COLUMN late_penalty,
+DROP COLUMN late_grace_minutes,
+DROP COLUMN late_policy;
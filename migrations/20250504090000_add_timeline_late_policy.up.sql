@@ -0,0 +1,12 @@
+ALTER TABLE timeline
+    ADD COLUMN late_policy        VARCHAR(16) NOT NULL DEFAULT 'flag'
+        CHECK (late_policy IN ('reject', 'grace', 'linear', 'stepwise', 'flag')),
+    ADD COLUMN late_grace_minutes INT         NOT NULL DEFAULT 0 CHECK (late_grace_minutes >= 0),
+    ADD COLUMN late_penalty       INT         NOT NULL DEFAULT 0 CHECK (late_penalty BETWEEN 0 AND 100),
+    ADD COLUMN late_step_minutes  INT CHECK (late_step_minutes > 0);
+
+ALTER TABLE team_action_status
+    ADD COLUMN submitted_at    timestamptz NOT NULL DEFAULT now(),
+    ADD COLUMN is_late         BOOLEAN     NOT NULL DEFAULT FALSE,
+    ADD COLUMN late_minutes    INT         NOT NULL DEFAULT 0 CHECK (late_minutes >= 0),
+    ADD COLUMN penalty_percent INT         NOT NULL DEFAULT 0 CHECK (penalty_percent BETWEEN 0 AND 100);
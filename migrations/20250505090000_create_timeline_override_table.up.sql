@@ -0,0 +1,16 @@
+CREATE TABLE timeline_override
+(
+    id            SERIAL PRIMARY KEY,
+    timeline_id   INT         NOT NULL REFERENCES timeline (id) ON DELETE CASCADE,
+    track_team_id INT         NOT NULL REFERENCES track_team (id) ON DELETE CASCADE,
+    deadline      timestamptz,
+    is_blocking   BOOLEAN,
+    reason        TEXT        NOT NULL CHECK (reason <> ''),
+    author_id     INT         NOT NULL,
+    created_at    timestamptz NOT NULL DEFAULT now(),
+    updated_at    timestamptz NOT NULL DEFAULT now(),
+    UNIQUE (timeline_id, track_team_id),
+    CHECK (deadline IS NOT NULL OR is_blocking IS NOT NULL)
+);
+
+CREATE INDEX idx_timeline_override_team ON timeline_override (track_team_id);
@@ -0,0 +1 @@
+ALTER TABLE session DROP CONSTRAINT IF EXISTS session_location_no_overlap;
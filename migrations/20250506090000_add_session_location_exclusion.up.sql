@@ -0,0 +1,18 @@
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+-- Sessions that already share a room keep it in id order, later ones lose
+-- their location and have to be placed again.
+UPDATE session s
+SET location_id = NULL
+WHERE EXISTS (SELECT 1
+              FROM session o
+              WHERE o.location_id = s.location_id
+                AND o.id < s.id
+                AND tstzrange(o.date_start, o.date_end) && tstzrange(s.date_start, s.date_end));
+
+-- Deferred so that moving several sessions of one room in a transaction,
+-- as rescheduling does, is checked on the final times only.
+ALTER TABLE session
+    ADD CONSTRAINT session_location_no_overlap
+        EXCLUDE USING gist (location_id WITH =, tstzrange(date_start, date_end) WITH &&)
+        DEFERRABLE INITIALLY DEFERRED;
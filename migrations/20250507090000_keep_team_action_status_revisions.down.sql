@@ -0,0 +1,13 @@
+DROP TRIGGER IF EXISTS team_action_status_revision_append_only ON team_action_status_revision;
+DROP FUNCTION IF EXISTS team_action_status_revision_append_only();
+
+-- Revisions of deleted actions have nothing left to reference.
+DELETE FROM team_action_status_revision r
+WHERE NOT EXISTS (SELECT 1
+                  FROM team_action_status s
+                  WHERE s.track_team_id = r.track_team_id
+                    AND s.timeline_id = r.timeline_id);
+
+ALTER TABLE team_action_status_revision
+    DROP COLUMN deleted,
+    ADD FOREIGN KEY (track_team_id, timeline_id) REFERENCES team_action_status (track_team_id, timeline_id) ON DELETE CASCADE;
@@ -0,0 +1,29 @@
+-- Revisions outlive the action they record, a deleted action leaves a final
+-- revision marked as deleted instead of taking its history along.
+ALTER TABLE team_action_status_revision
+    DROP CONSTRAINT IF EXISTS team_action_status_revision_track_team_id_timeline_id_fkey,
+    ADD COLUMN deleted BOOLEAN NOT NULL DEFAULT false;
+
+-- Only the retention purge removes revisions, together with their deleted
+-- timeline or track.
+CREATE FUNCTION team_action_status_revision_append_only() RETURNS trigger AS
+$$
+BEGIN
+    IF TG_OP = 'DELETE' AND NOT EXISTS (SELECT 1
+                                        FROM timeline t
+                                                 JOIN track tr ON tr.id = t.track_id
+                                        WHERE t.id = OLD.timeline_id
+                                          AND t.deleted_at IS NULL
+                                          AND tr.deleted_at IS NULL) THEN
+        RETURN OLD;
+    END IF;
+
+    RAISE EXCEPTION 'team_action_status_revision is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER team_action_status_revision_append_only
+    BEFORE UPDATE OR DELETE
+    ON team_action_status_revision
+    FOR EACH ROW
+EXECUTE FUNCTION team_action_status_revision_append_only();
@@ -0,0 +1,2 @@
+ALTER TABLE session
+ADD version INT NOT NULL DEFAULT 1;
@@ -0,0 +1 @@
+DROP FUNCTION IF EXISTS search_snippet(TEXT, TEXT, tsquery, TEXT);
@@ -0,0 +1,18 @@
+-- Titles and descriptions are escaped before highlighting, so the only markup
+-- in a snippet is the one ts_headline adds around matches.
+CREATE OR REPLACE FUNCTION search_snippet(title TEXT, description TEXT, query tsquery, options TEXT)
+    RETURNS TEXT
+    LANGUAGE sql
+    IMMUTABLE
+    PARALLEL SAFE
+AS
+$$
+SELECT ts_headline('russian',
+                   replace(replace(replace(replace(replace(concat_ws(' ', title, description),
+                                                           '&', '&amp;'),
+                                                   '<', '&lt;'),
+                                           '>', '&gt;'),
+                                   '"', '&quot;'),
+                           '''', '&#39;'),
+                   query, options)
+$$;
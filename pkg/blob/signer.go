@@ -0,0 +1,47 @@
+package blob
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+)
+
+var ErrInvalidLink = errors.New("download link is invalid or expired")
+
+// Signer issues and checks expiring download links. A link is bound to one
+// object and stops working after its expiry, no state is kept on the server.
+type Signer struct {
+	secret []byte
+}
+
+func NewSigner(secret string) *Signer {
+	return &Signer{secret: []byte(secret)}
+}
+
+func (s *Signer) Sign(object string, expires time.Time) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(object + "\n" + strconv.FormatInt(expires.Unix(), 10)))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *Signer) Verify(object string, expires time.Time, signature string, now time.Time) error {
+	if !now.Before(expires) {
+		return ErrInvalidLink
+	}
+
+	expected, err := hex.DecodeString(s.Sign(object, expires))
+	if err != nil {
+		return err
+	}
+
+	actual, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(expected, actual) {
+		return ErrInvalidLink
+	}
+
+	return nil
+}
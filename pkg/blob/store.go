@@ -0,0 +1,18 @@
+// Package blob keeps uploaded files outside the database. Stores address
+// objects by slash separated keys, the same way S3 buckets do, so the local
+// filesystem and S3-compatible storage are interchangeable.
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var ErrNotFound = errors.New("blob not found")
+
+type Store interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,65 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/go-pg/pg/v10"
+	"github.com/prometheus/client_golang/prometheus"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	QueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "Duration of database queries by operation",
+		Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation"})
+	QueryErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_query_errors_total",
+		Help: "Total number of failed database queries by operation",
+	}, []string{"operation"})
+)
+
+// operations bounds the label set, anything else is reported as "other".
+var operations = []string{"SELECT", "INSERT", "UPDATE", "DELETE", "BEGIN", "COMMIT", "ROLLBACK", "WITH"}
+
+// MetricsHook observes the latency of every query run through the database
+// handle, transactions included.
+type MetricsHook struct{}
+
+func (MetricsHook) BeforeQuery(ctx context.Context, _ *pg.QueryEvent) (context.Context, error) {
+	return ctx, nil
+}
+
+func (MetricsHook) AfterQuery(_ context.Context, event *pg.QueryEvent) error {
+	operation := queryOperation(event)
+
+	QueryDuration.WithLabelValues(operation).Observe(time.Since(event.StartTime).Seconds())
+	if event.Err != nil && !errors.Is(event.Err, pg.ErrNoRows) {
+		QueryErrors.WithLabelValues(operation).Inc()
+	}
+
+	return nil
+}
+
+func queryOperation(event *pg.QueryEvent) string {
+	query, err := event.UnformattedQuery()
+	if err != nil {
+		return "other"
+	}
+
+	fields := bytes.Fields(query)
+	if len(fields) == 0 {
+		return "other"
+	}
+
+	keyword := strings.ToUpper(string(fields[0]))
+	if !slices.Contains(operations, keyword) {
+		return "other"
+	}
+
+	return strings.ToLower(keyword)
+}
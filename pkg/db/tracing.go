@@ -0,0 +1,73 @@
+package db
+
+import (
+	"context"
+	"event_service/pkg/tracing"
+	"github.com/go-pg/pg/v10"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"unicode/utf8"
+)
+
+// maxStatementLength keeps huge statements, bulk inserts mostly, from
+// bloating spans.
+const maxStatementLength = 4096
+
+type spanKey struct{}
+
+// TracingHook wraps every query in a span carrying the SQL statement. Queries
+// join the trace of the context the transaction was begun with.
+type TracingHook struct {
+	Database string
+}
+
+func (h TracingHook) BeforeQuery(ctx context.Context, event *pg.QueryEvent) (context.Context, error) {
+	operation := queryOperation(event)
+
+	ctx, span := tracing.Start(ctx, "db."+operation, trace.WithSpanKind(trace.SpanKindClient))
+	span.SetAttributes(
+		attribute.String("db.system", "postgresql"),
+		attribute.String("db.name", h.Database),
+		attribute.String("db.operation", operation),
+	)
+
+	// The unformatted query keeps bind values, scores and personal data
+	// among them, out of the exported spans.
+	if query, err := event.UnformattedQuery(); err == nil {
+		span.SetAttributes(attribute.String("db.statement", truncate(string(query), maxStatementLength)))
+	}
+
+	if event.Stash == nil {
+		event.Stash = make(map[interface{}]interface{})
+	}
+	event.Stash[spanKey{}] = span
+
+	return ctx, nil
+}
+
+func (h TracingHook) AfterQuery(_ context.Context, event *pg.QueryEvent) error {
+	span, ok := event.Stash[spanKey{}].(trace.Span)
+	if !ok {
+		return nil
+	}
+
+	if event.Result != nil {
+		span.SetAttributes(attribute.Int("db.rows_affected", event.Result.RowsAffected()))
+	}
+
+	tracing.End(span, event.Err, pg.ErrNoRows)
+	return nil
+}
+
+// truncate cuts s to at most n bytes without splitting a rune.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+
+	return s[:n]
+}
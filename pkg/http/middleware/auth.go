@@ -4,6 +4,7 @@ import (
 	"event_service/pkg/utils"
 	"log/slog"
 	"net/http"
+	"slices"
 )
 
 type ExternalResponse struct {
@@ -14,7 +15,7 @@ func JWTAuthMiddleware(log *slog.Logger, authURL string) func(http.Handler) http
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			authHeader := r.Header.Get("Authorization")
-			isAuthorized, err := utils.CheckAuthorization(log, &utils.AuthRequest{AuthURL: authURL, JwtToken: authHeader})
+			isAuthorized, err := utils.CheckAuthorization(r.Context(), log, &utils.AuthRequest{AuthURL: authURL, JwtToken: authHeader})
 			if err != nil || !isAuthorized {
 				return
 			}
@@ -23,3 +24,31 @@ func JWTAuthMiddleware(log *slog.Logger, authURL string) func(http.Handler) http
 		})
 	}
 }
+
+// ActorMiddleware resolves the caller through the auth service and stores its
+// id in the request context, callers listed in admins are marked as
+// administrators. Anonymous requests are passed through untouched.
+func ActorMiddleware(log *slog.Logger, authURL string, admins []int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authHeader := r.Header.Get("Authorization")
+			if authHeader == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			resp, err := utils.GetAuthorizationResponse(r.Context(), log, &utils.AuthRequest{AuthURL: authURL, JwtToken: authHeader})
+			if err != nil || resp == nil || resp.Status != "ok" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := utils.WithAuthToken(utils.WithActor(r.Context(), resp.Id), authHeader)
+			if slices.Contains(admins, resp.Id) {
+				ctx = utils.WithAdmin(ctx)
+			}
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,276 @@
+// Package ical renders RFC 5545 calendars for subscription feeds.
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	StatusConfirmed = "CONFIRMED"
+	StatusTentative = "TENTATIVE"
+	StatusCancelled = "CANCELLED"
+
+	dateTimeLayout  = "20060102T150405Z"
+	localTimeLayout = "20060102T150405"
+	lineLimit       = 75
+)
+
+type Calendar struct {
+	ProdID   string
+	Name     string
+	TimeZone string
+	Events   []Event
+}
+
+// Event is a single VEVENT. UID must stay stable between renders so that
+// subscribed clients move the existing entry instead of adding a new one,
+// and Sequence must grow every time the entry changes. Start and Stamp are
+// required.
+type Event struct {
+	UID         string
+	Sequence    int
+	Status      string
+	Summary     string
+	Description string
+	URL         string
+	Categories  []string
+	Start       time.Time
+	End         time.Time
+	Stamp       time.Time
+
+	// TimeZone, when set to anything but UTC, renders Start and End as
+	// local times with a TZID parameter.
+	TimeZone *time.Location
+}
+
+func (c *Calendar) Add(event Event) {
+	c.Events = append(c.Events, event)
+}
+
+func (c *Calendar) Encode(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:" + c.ProdID,
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+	}
+
+	if c.Name != "" {
+		lines = append(lines, "X-WR-CALNAME:"+escapeText(c.Name))
+	}
+
+	if c.TimeZone != "" {
+		lines = append(lines, "X-WR-TIMEZONE:"+c.TimeZone)
+	}
+
+	lines = append(lines, c.timeZones()...)
+
+	for _, event := range c.Events {
+		lines = append(lines, event.lines()...)
+	}
+
+	lines = append(lines, "END:VCALENDAR")
+
+	for _, line := range lines {
+		if _, err := bw.WriteString(fold(line)); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+func (e Event) lines() []string {
+	status := e.Status
+	if status == "" {
+		status = StatusConfirmed
+	}
+
+	lines := []string{
+		"BEGIN:VEVENT",
+		"UID:" + e.UID,
+		"DTSTAMP:" + formatTime(e.Stamp),
+		fmt.Sprintf("SEQUENCE:%d", e.Sequence),
+		"STATUS:" + status,
+		"DTSTART" + e.formatTime(e.Start),
+	}
+
+	if !e.End.IsZero() && e.End.After(e.Start) {
+		lines = append(lines, "DTEND"+e.formatTime(e.End))
+	}
+
+	lines = append(lines, "SUMMARY:"+escapeText(e.Summary))
+
+	if e.Description != "" {
+		lines = append(lines, "DESCRIPTION:"+escapeText(e.Description))
+	}
+
+	if e.URL != "" {
+		lines = append(lines, "URL:"+e.URL)
+	}
+
+	if len(e.Categories) > 0 {
+		categories := make([]string, 0, len(e.Categories))
+		for _, category := range e.Categories {
+			categories = append(categories, escapeText(category))
+		}
+
+		lines = append(lines, "CATEGORIES:"+strings.Join(categories, ","))
+	}
+
+	return append(lines, "END:VEVENT")
+}
+
+// timeZones emits one VTIMEZONE per zone referenced by a TZID, covering
+// every offset the zone goes through between its first and last entry.
+func (c *Calendar) timeZones() []string {
+	var lines []string
+
+	zones := make([]*time.Location, 0)
+	bounds := make(map[string][2]time.Time)
+	for _, event := range c.Events {
+		if !event.local() || event.Start.IsZero() {
+			continue
+		}
+
+		last := event.Start
+		if event.End.After(last) {
+			last = event.End
+		}
+
+		name := event.TimeZone.String()
+		span, ok := bounds[name]
+		if !ok {
+			zones = append(zones, event.TimeZone)
+			span = [2]time.Time{event.Start, last}
+		}
+
+		if event.Start.Before(span[0]) {
+			span[0] = event.Start
+		}
+
+		if last.After(span[1]) {
+			span[1] = last
+		}
+
+		bounds[name] = span
+	}
+
+	for _, zone := range zones {
+		span := bounds[zone.String()]
+
+		lines = append(lines, "BEGIN:VTIMEZONE", "TZID:"+zone.String())
+		lines = append(lines, observances(zone, span[0], span[1])...)
+		lines = append(lines, "END:VTIMEZONE")
+	}
+
+	return lines
+}
+
+// observances describes zone from the period holding from to the one holding
+// to. Every period becomes a STANDARD or DAYLIGHT block that starts at its
+// own transition, so clients need no recurrence rules to resolve the times.
+func observances(zone *time.Location, from time.Time, to time.Time) []string {
+	var lines []string
+
+	for t := from.In(zone); ; {
+		name, offset := t.Zone()
+		start, end := t.ZoneBounds()
+
+		// DTSTART is the wall time of the transition under the offset it
+		// leaves, a zone without transitions starts at the epoch.
+		previous, dtStart := offset, "19700101T000000"
+		if !start.IsZero() {
+			_, previous = start.Add(-time.Second).Zone()
+			dtStart = start.UTC().Add(time.Duration(previous) * time.Second).Format(localTimeLayout)
+		}
+
+		kind := "STANDARD"
+		if t.IsDST() {
+			kind = "DAYLIGHT"
+		}
+
+		lines = append(lines,
+			"BEGIN:"+kind,
+			"DTSTART:"+dtStart,
+			"TZNAME:"+name,
+			"TZOFFSETFROM:"+formatOffset(previous),
+			"TZOFFSETTO:"+formatOffset(offset),
+			"END:"+kind,
+		)
+
+		if end.IsZero() || end.After(to) {
+			return lines
+		}
+
+		t = end
+	}
+}
+
+func (e Event) local() bool {
+	return e.TimeZone != nil && e.TimeZone != time.UTC && e.TimeZone.String() != "UTC"
+}
+
+// formatTime renders a DTSTART or DTEND value together with its parameters.
+func (e Event) formatTime(t time.Time) string {
+	if !e.local() {
+		return ":" + formatTime(t)
+	}
+
+	return ";TZID=" + e.TimeZone.String() + ":" + t.In(e.TimeZone).Format(localTimeLayout)
+}
+
+func formatOffset(offset int) string {
+	sign := "+"
+	if offset < 0 {
+		sign = "-"
+		offset = -offset
+	}
+
+	return fmt.Sprintf("%s%02d%02d", sign, offset/3600, offset%3600/60)
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(dateTimeLayout)
+}
+
+func escapeText(value string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	)
+
+	return replacer.Replace(value)
+}
+
+// fold splits a content line into chunks of at most 75 octets without
+// breaking UTF-8 sequences, as required by RFC 5545 section 3.1.
+func fold(line string) string {
+	var b strings.Builder
+
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > lineLimit {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+
+		b.WriteRune(r)
+		width += size
+	}
+
+	b.WriteString("\r\n")
+
+	return b.String()
+}
@@ -0,0 +1,66 @@
+package teams
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// breaker opens after threshold consecutive failures and rejects calls until
+// openTimeout passes. Then a single probe is let through, which either closes
+// the breaker again or keeps it open for another period.
+type breaker struct {
+	mu sync.Mutex
+
+	threshold   int
+	openTimeout time.Duration
+
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+func newBreaker(threshold int, openTimeout time.Duration) *breaker {
+	return &breaker{threshold: threshold, openTimeout: openTimeout}
+}
+
+func (b *breaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.threshold <= 0 || b.failures < b.threshold {
+		return nil
+	}
+
+	if time.Now().Before(b.openUntil) || b.probing {
+		return fmt.Errorf("%w: circuit is open", ErrUnavailable)
+	}
+
+	b.probing = true
+	return nil
+}
+
+// release ends an attempt without an outcome, so a probe can be retried.
+func (b *breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+func (b *breaker) record(success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+
+	if success {
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.threshold > 0 && b.failures >= b.threshold {
+		b.openUntil = time.Now().Add(b.openTimeout)
+	}
+}
@@ -0,0 +1,206 @@
+// Package teams is a client for the user and teams service, which owns the
+// teams and users that tracks, judges and speakers refer to by id.
+package teams
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotFound    = errors.New("not found in user and teams service")
+	ErrUnavailable = errors.New("user and teams service is unavailable")
+)
+
+type Team struct {
+	ID           int      `json:"id" example:"1"`
+	Name         string   `json:"name" example:"Team Rocket"`
+	MembersCount int      `json:"members_count" example:"4"`
+	Members      []Member `json:"members,omitempty"`
+}
+
+type Member struct {
+	ID        int  `json:"id" example:"7"`
+	IsStudent bool `json:"is_student" example:"true"`
+}
+
+// Size prefers the member list and falls back to the reported count for
+// responses that come without members.
+func (t *Team) Size() int {
+	if len(t.Members) > 0 {
+		return len(t.Members)
+	}
+
+	return t.MembersCount
+}
+
+type User struct {
+	ID    int    `json:"id" example:"7"`
+	Name  string `json:"name" example:"Ivan Petrov"`
+	Email string `json:"email" example:"ivan@example.com"`
+}
+
+type Client interface {
+	GetTeam(ctx context.Context, id int) (*Team, error)
+	GetTeams(ctx context.Context, ids []int) (map[int]*Team, error)
+	GetUser(ctx context.Context, id int) (*User, error)
+}
+
+type Config struct {
+	BaseURL          string
+	Timeout          time.Duration
+	Retries          int
+	RetryBackoff     time.Duration
+	FailureThreshold int
+	OpenTimeout      time.Duration
+
+	// Token returns the Authorization header to forward, if any.
+	Token func(ctx context.Context) string
+}
+
+type HTTPClient struct {
+	cfg     Config
+	http    *http.Client
+	breaker *breaker
+}
+
+func NewHTTPClient(cfg Config) *HTTPClient {
+	return &HTTPClient{
+		cfg:     cfg,
+		http:    &http.Client{Timeout: cfg.Timeout, Transport: otelhttp.NewTransport(http.DefaultTransport)},
+		breaker: newBreaker(cfg.FailureThreshold, cfg.OpenTimeout),
+	}
+}
+
+// BaseURL derives the API root of the service from its auth check URL, for
+// example http://host:8000/api/v0/auth/auth-check gives http://host:8000/api/v0.
+func BaseURL(authURL string) string {
+	if idx := strings.Index(authURL, "/auth/"); idx >= 0 {
+		return authURL[:idx]
+	}
+
+	return strings.TrimSuffix(authURL, "/")
+}
+
+func (c *HTTPClient) GetTeam(ctx context.Context, id int) (*Team, error) {
+	team := new(Team)
+	if err := c.get(ctx, fmt.Sprintf("/teams/%d", id), team); err != nil {
+		return nil, fmt.Errorf("team %d: %w", id, err)
+	}
+
+	return team, nil
+}
+
+// GetTeams resolves every id one by one, the service has no batch endpoint.
+// Teams that do not exist are left out of the result.
+func (c *HTTPClient) GetTeams(ctx context.Context, ids []int) (map[int]*Team, error) {
+	teams := make(map[int]*Team, len(ids))
+
+	for _, id := range ids {
+		if _, ok := teams[id]; ok {
+			continue
+		}
+
+		team, err := c.GetTeam(ctx, id)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		teams[id] = team
+	}
+
+	return teams, nil
+}
+
+func (c *HTTPClient) GetUser(ctx context.Context, id int) (*User, error) {
+	user := new(User)
+	if err := c.get(ctx, fmt.Sprintf("/users/%d", id), user); err != nil {
+		return nil, fmt.Errorf("user %d: %w", id, err)
+	}
+
+	return user, nil
+}
+
+// get retries network failures and server errors with a linear backoff.
+// Every failed attempt counts towards opening the circuit breaker, except
+// those cut short by the caller's context.
+func (c *HTTPClient) get(ctx context.Context, path string, dst interface{}) error {
+	var lastErr error
+
+	for attempt := 0; attempt <= c.cfg.Retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Duration(attempt) * c.cfg.RetryBackoff):
+			}
+		}
+
+		if err := c.breaker.allow(); err != nil {
+			return err
+		}
+
+		retry, err := c.do(ctx, path, dst)
+
+		// A caller that gave up says nothing about the service, so the
+		// attempt is neither a failure nor a success.
+		if err != nil && ctx.Err() != nil {
+			c.breaker.release()
+			return err
+		}
+
+		c.breaker.record(!retry)
+
+		if !retry {
+			return err
+		}
+
+		lastErr = err
+	}
+
+	return fmt.Errorf("%w: %w", ErrUnavailable, lastErr)
+}
+
+func (c *HTTPClient) do(ctx context.Context, path string, dst interface{}) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.cfg.BaseURL+path, nil)
+	if err != nil {
+		return false, err
+	}
+
+	if c.cfg.Token != nil {
+		if token := c.cfg.Token(ctx); token != "" {
+			req.Header.Set("Authorization", token)
+		}
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return false, ErrNotFound
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return true, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	case resp.StatusCode != http.StatusOK:
+		return false, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(dst); err != nil {
+		return false, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return false, nil
+}
@@ -0,0 +1,87 @@
+package teams
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Fake is an in-memory Client for tests and local runs without the user and
+// teams service. Setting Err makes every call fail with it.
+type Fake struct {
+	mu sync.RWMutex
+
+	Teams map[int]*Team
+	Users map[int]*User
+	Err   error
+}
+
+func NewFake() *Fake {
+	return &Fake{
+		Teams: make(map[int]*Team),
+		Users: make(map[int]*User),
+	}
+}
+
+func (f *Fake) AddTeam(team *Team) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.Teams[team.ID] = team
+}
+
+func (f *Fake) AddUser(user *User) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.Users[user.ID] = user
+}
+
+func (f *Fake) GetTeam(_ context.Context, id int) (*Team, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if f.Err != nil {
+		return nil, f.Err
+	}
+
+	team, ok := f.Teams[id]
+	if !ok {
+		return nil, fmt.Errorf("team %d: %w", id, ErrNotFound)
+	}
+
+	return team, nil
+}
+
+func (f *Fake) GetTeams(ctx context.Context, ids []int) (map[int]*Team, error) {
+	teams := make(map[int]*Team, len(ids))
+
+	for _, id := range ids {
+		team, err := f.GetTeam(ctx, id)
+		if err != nil && f.Err != nil {
+			return nil, err
+		}
+
+		if team != nil {
+			teams[id] = team
+		}
+	}
+
+	return teams, nil
+}
+
+func (f *Fake) GetUser(_ context.Context, id int) (*User, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if f.Err != nil {
+		return nil, f.Err
+	}
+
+	user, ok := f.Users[id]
+	if !ok {
+		return nil, fmt.Errorf("user %d: %w", id, ErrNotFound)
+	}
+
+	return user, nil
+}
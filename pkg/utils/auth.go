@@ -1,11 +1,20 @@
 package utils
 
 import (
+	"context"
 	"encoding/json"
+	"github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"log/slog"
 	"net/http"
 )
 
+type actorKey struct{}
+
+type authTokenKey struct{}
+
+type adminKey struct{}
+
 type AuthRequest struct {
 	AuthURL  string
 	JwtToken string
@@ -16,8 +25,12 @@ type AuthResponsePayload struct {
 	Id     int    `json:"id" validate:"required" example:"52"`
 }
 
-func GetAuthorizationResponse(log *slog.Logger, request *AuthRequest) (*AuthResponsePayload, error) {
-	req, err := http.NewRequest(http.MethodGet, request.AuthURL, nil)
+// authClient propagates the trace context of the request to the auth service
+// and records every call as a client span.
+var authClient = &http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport)}
+
+func GetAuthorizationResponse(ctx context.Context, log *slog.Logger, request *AuthRequest) (*AuthResponsePayload, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, request.AuthURL, nil)
 	if err != nil {
 		log.Error("Failed to create request to external service")
 		return nil, nil
@@ -25,8 +38,7 @@ func GetAuthorizationResponse(log *slog.Logger, request *AuthRequest) (*AuthResp
 
 	req.Header.Set("Authorization", request.JwtToken)
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := authClient.Do(req)
 	if err != nil {
 		log.Error("External service unavailable")
 		return nil, nil
@@ -42,11 +54,45 @@ func GetAuthorizationResponse(log *slog.Logger, request *AuthRequest) (*AuthResp
 	return &extResp, nil
 }
 
-func CheckAuthorization(log *slog.Logger, request *AuthRequest) (bool, error) {
-	extResp, err := GetAuthorizationResponse(log, request)
+func CheckAuthorization(ctx context.Context, log *slog.Logger, request *AuthRequest) (bool, error) {
+	extResp, err := GetAuthorizationResponse(ctx, log, request)
 	if err != nil {
 		return false, err
 	}
 
 	return extResp.Status == "ok", nil
 }
+
+func WithActor(ctx context.Context, actorID int) context.Context {
+	return context.WithValue(ctx, actorKey{}, actorID)
+}
+
+func ActorFromContext(ctx context.Context) (int, bool) {
+	actorID, ok := ctx.Value(actorKey{}).(int)
+	return actorID, ok
+}
+
+// WithAdmin marks the actor of the request as an administrator.
+func WithAdmin(ctx context.Context) context.Context {
+	return context.WithValue(ctx, adminKey{}, true)
+}
+
+func IsAdmin(ctx context.Context) bool {
+	admin, _ := ctx.Value(adminKey{}).(bool)
+	return admin
+}
+
+// WithAuthToken keeps the caller's Authorization header so that calls to
+// other services can be made on its behalf.
+func WithAuthToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, authTokenKey{}, token)
+}
+
+func AuthTokenFromContext(ctx context.Context) string {
+	token, _ := ctx.Value(authTokenKey{}).(string)
+	return token
+}
+
+func RequestIDFromContext(ctx context.Context) string {
+	return middleware.GetReqID(ctx)
+}
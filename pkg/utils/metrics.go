@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"context"
+	"event_service/internal/schemas"
+	"event_service/pkg/tracing"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/robfig/cron/v3"
+	"log/slog"
+)
+
+var (
+	EventsByStatus = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "events",
+		Help: "Number of events by status",
+	}, []string{"status"})
+	TracksByStatus = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "tracks",
+		Help: "Number of tracks by status",
+	}, []string{"status"})
+	ActiveTracks = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "tracks_active",
+		Help: "Number of tracks in process",
+	})
+	Registrations = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "track_registrations",
+		Help: "Number of team registrations by state",
+	}, []string{"state"})
+
+	Submissions = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "submissions_total",
+		Help: "Total number of team submissions and resubmissions",
+	})
+)
+
+type MetricsService interface {
+	GetBusinessMetrics(context.Context) (*schemas.BusinessMetrics, error)
+}
+
+// ScheduleMetrics refreshes the business gauges from the database, right away
+// and then every minute.
+func ScheduleMetrics(log *slog.Logger, service MetricsService) {
+	refresh := func() {
+		ctx, span := tracing.Start(context.Background(), "cron.RefreshMetrics")
+		defer span.End()
+
+		metrics, err := service.GetBusinessMetrics(ctx)
+		if err != nil {
+			log.ErrorContext(ctx, "Failed to get business metrics", slog.String("error", err.Error()))
+			return
+		}
+
+		setGauges(EventsByStatus, metrics.EventsByStatus)
+		setGauges(TracksByStatus, metrics.TracksByStatus)
+		setGauges(Registrations, metrics.RegistrationsByState)
+		ActiveTracks.Set(float64(metrics.TracksByStatus["in_process"]))
+	}
+
+	refresh()
+
+	c := cron.New()
+
+	_, err := c.AddFunc("@every 1m", refresh)
+	if err != nil {
+		log.Error("Error scheduling business metrics:", slog.String("error", err.Error()))
+		CronTaskFailure.Inc()
+	} else {
+		CronTaskSuccess.Inc()
+	}
+
+	c.Start()
+}
+
+// setGauges replaces every label value of gauges, so statuses that are gone
+// drop to nothing instead of keeping their last count.
+func setGauges(gauges *prometheus.GaugeVec, counts map[string]int) {
+	gauges.Reset()
+
+	for label, count := range counts {
+		gauges.WithLabelValues(label).Set(float64(count))
+	}
+}
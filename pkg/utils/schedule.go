@@ -1,7 +1,10 @@
 package utils
 
 import (
+	"context"
 	"event_service/internal/models"
+	"event_service/internal/schemas"
+	"event_service/pkg/tracing"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/robfig/cron/v3"
 	"log/slog"
@@ -51,42 +54,64 @@ var (
 		Name: "track_end_failure_total",
 		Help: "Total number of failed track ends",
 	})
+
+	TimelineExpireSuccess = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "timeline_expire_success_total",
+		Help: "Total number of successful timeline expirations",
+	})
+	TimelineExpireFailure = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "timeline_expire_failure_total",
+		Help: "Total number of failed timeline expirations",
+	})
 )
 
 type EventService interface {
-	GetAllEventsToStart() ([]*models.Event, error)
-	GetAllEventsToEnd() ([]*models.Event, error)
+	GetAllEventsToStart(context.Context) ([]*models.Event, error)
+	GetAllEventsToEnd(context.Context) ([]*models.Event, error)
 
-	StartEvent(int) (*models.Event, error)
-	EndEvent(int) (*models.Event, error)
+	StartEvent(context.Context, int) (*models.Event, error)
+	EndEvent(context.Context, int) (*models.Event, error)
+}
+
+type RetentionService interface {
+	PurgeDeleted(context.Context) (*schemas.PurgeResult, error)
 }
 
 type TrackService interface {
-	GetAllTracksToStart() ([]*models.Track, error)
-	GetAllTracksToEnd() ([]*models.Track, error)
+	GetAllTracksToStart(context.Context) ([]*models.Track, error)
+	GetAllTracksToEnd(context.Context) ([]*models.Track, error)
 
-	StartTrack(int) (*models.Track, error)
-	EndTrack(int) (*models.Track, error)
+	StartTrack(context.Context, int) (*models.Track, error)
+	EndTrack(context.Context, int) (*models.Track, error)
+}
+
+type TimelineService interface {
+	GetAllTimelinesToExpire(context.Context) ([]*models.Timeline, error)
+
+	ExpireTimeline(context.Context, int) error
 }
 
 func ScheduleEvents(log *slog.Logger, service EventService) {
 	c := cron.New()
 
 	_, err := c.AddFunc("@every 1m", func() {
-		events, err := service.GetAllEventsToStart()
+		ctx, span := tracing.Start(context.Background(), "cron.StartEvents")
+		defer span.End()
+
+		events, err := service.GetAllEventsToStart(ctx)
 		if err != nil {
-			log.Error("Failed to get events to start")
+			log.ErrorContext(ctx, "Failed to get events to start")
 			return
 		}
 
 		for _, event := range events {
-			_, err = service.StartEvent(event.ID)
+			_, err = service.StartEvent(ctx, event.ID)
 
 			if err != nil {
-				log.Error("Failed to start event", slog.String("id", strconv.Itoa(event.ID)))
+				log.ErrorContext(ctx, "Failed to start event", slog.String("id", strconv.Itoa(event.ID)))
 				EventStartFailure.Inc()
 			} else {
-				log.Info("Successfully started event", slog.String("id", strconv.Itoa(event.ID)))
+				log.InfoContext(ctx, "Successfully started event", slog.String("id", strconv.Itoa(event.ID)))
 				EventStartSuccess.Inc()
 			}
 		}
@@ -100,20 +125,23 @@ func ScheduleEvents(log *slog.Logger, service EventService) {
 	}
 
 	_, err = c.AddFunc("@every 1m", func() {
-		events, err := service.GetAllEventsToEnd()
+		ctx, span := tracing.Start(context.Background(), "cron.EndEvents")
+		defer span.End()
+
+		events, err := service.GetAllEventsToEnd(ctx)
 		if err != nil {
-			log.Error("Failed to get events to end")
+			log.ErrorContext(ctx, "Failed to get events to end")
 			return
 		}
 
 		for _, event := range events {
-			_, err = service.EndEvent(event.ID)
+			_, err = service.EndEvent(ctx, event.ID)
 
 			if err != nil {
-				log.Error("Failed to env event", slog.String("id", strconv.Itoa(event.ID)))
+				log.ErrorContext(ctx, "Failed to env event", slog.String("id", strconv.Itoa(event.ID)))
 				EventEndFailure.Inc()
 			} else {
-				log.Info("Successfully ended event", slog.String("id", strconv.Itoa(event.ID)))
+				log.InfoContext(ctx, "Successfully ended event", slog.String("id", strconv.Itoa(event.ID)))
 				EventEndSuccess.Inc()
 			}
 		}
@@ -133,20 +161,23 @@ func ScheduleTracks(log *slog.Logger, service TrackService) {
 	c := cron.New()
 
 	_, err := c.AddFunc("@every 1m", func() {
-		tracks, err := service.GetAllTracksToStart()
+		ctx, span := tracing.Start(context.Background(), "cron.StartTracks")
+		defer span.End()
+
+		tracks, err := service.GetAllTracksToStart(ctx)
 		if err != nil {
-			log.Error("Failed to get tracks to start")
+			log.ErrorContext(ctx, "Failed to get tracks to start")
 			return
 		}
 
 		for _, track := range tracks {
-			_, err = service.StartTrack(track.ID)
+			_, err = service.StartTrack(ctx, track.ID)
 
 			if err != nil {
-				log.Error("Failed to start track", slog.String("id", strconv.Itoa(track.ID)))
+				log.ErrorContext(ctx, "Failed to start track", slog.String("id", strconv.Itoa(track.ID)))
 				TrackStartFailure.Inc()
 			} else {
-				log.Info("Successfully started track", slog.String("id", strconv.Itoa(track.ID)))
+				log.InfoContext(ctx, "Successfully started track", slog.String("id", strconv.Itoa(track.ID)))
 				TrackStartSuccess.Inc()
 			}
 		}
@@ -160,20 +191,23 @@ func ScheduleTracks(log *slog.Logger, service TrackService) {
 	}
 
 	_, err = c.AddFunc("@every 1m", func() {
-		tracks, err := service.GetAllTracksToEnd()
+		ctx, span := tracing.Start(context.Background(), "cron.EndTracks")
+		defer span.End()
+
+		tracks, err := service.GetAllTracksToEnd(ctx)
 		if err != nil {
-			log.Error("Failed to get tracks to end")
+			log.ErrorContext(ctx, "Failed to get tracks to end")
 			return
 		}
 
 		for _, track := range tracks {
-			_, err = service.EndTrack(track.ID)
+			_, err = service.EndTrack(ctx, track.ID)
 
 			if err != nil {
-				log.Error("Failed to env track", slog.String("id", strconv.Itoa(track.ID)))
+				log.ErrorContext(ctx, "Failed to env track", slog.String("id", strconv.Itoa(track.ID)))
 				TrackEndFailure.Inc()
 			} else {
-				log.Info("Successfully ended track", slog.String("id", strconv.Itoa(track.ID)))
+				log.InfoContext(ctx, "Successfully ended track", slog.String("id", strconv.Itoa(track.ID)))
 				TrackEndSuccess.Inc()
 			}
 		}
@@ -188,3 +222,70 @@ func ScheduleTracks(log *slog.Logger, service TrackService) {
 
 	c.Start()
 }
+
+func ScheduleRetention(log *slog.Logger, service RetentionService) {
+	c := cron.New()
+
+	_, err := c.AddFunc("@every 1h", func() {
+		ctx, span := tracing.Start(context.Background(), "cron.PurgeDeleted")
+		defer span.End()
+
+		result, err := service.PurgeDeleted(ctx)
+		if err != nil {
+			log.ErrorContext(ctx, "Failed to purge deleted rows", slog.String("error", err.Error()))
+			return
+		}
+
+		log.InfoContext(ctx, "Successfully purged deleted rows",
+			slog.Int("events", result.Events),
+			slog.Int("tracks", result.Tracks),
+			slog.Int("timelines", result.Timelines),
+			slog.Int("locations", result.Locations),
+		)
+	})
+
+	if err != nil {
+		log.Error("Error scheduling retention:", slog.String("error", err.Error()))
+		CronTaskFailure.Inc()
+	} else {
+		CronTaskSuccess.Inc()
+	}
+
+	c.Start()
+}
+
+// ScheduleTimelines expires timelines once their deadline and every per-team
+// extension of it have passed.
+func ScheduleTimelines(log *slog.Logger, service TimelineService) {
+	c := cron.New()
+
+	_, err := c.AddFunc("@every 1m", func() {
+		ctx, span := tracing.Start(context.Background(), "cron.ExpireTimelines")
+		defer span.End()
+
+		timelines, err := service.GetAllTimelinesToExpire(ctx)
+		if err != nil {
+			log.ErrorContext(ctx, "Failed to get timelines to expire")
+			return
+		}
+
+		for _, timeline := range timelines {
+			if err = service.ExpireTimeline(ctx, timeline.ID); err != nil {
+				log.ErrorContext(ctx, "Failed to expire timeline", slog.String("id", strconv.Itoa(timeline.ID)))
+				TimelineExpireFailure.Inc()
+			} else {
+				log.InfoContext(ctx, "Successfully expired timeline", slog.String("id", strconv.Itoa(timeline.ID)))
+				TimelineExpireSuccess.Inc()
+			}
+		}
+	})
+
+	if err != nil {
+		log.Error("Error scheduling expire timelines:", slog.String("error", err.Error()))
+		CronTaskFailure.Inc()
+	} else {
+		CronTaskSuccess.Inc()
+	}
+
+	c.Start()
+}